@@ -4,10 +4,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/vecosy/vecosy/v2/internal/grpcapi"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 )
 
-func startGRPC(repo configrepo.Repo) {
+func startGRPC(repo configrepo.Repo, schemaValidator *validation.SchemaValidator) {
 	var err error
 	viper.SetDefault("server.grpc.address", ":8081")
 	var server *grpcapi.Server
@@ -19,6 +20,9 @@ func startGRPC(repo configrepo.Repo) {
 	if err != nil {
 		logrus.Fatalf("Error starting GPRC server:%s", err)
 	}
+	server.SetAdminPubKey(getAdminPubKey())
+	server.SetEncryptor(getEncryptor())
+	server.SetSchemaValidator(schemaValidator)
 	err = server.Start()
 	if err != nil {
 		logrus.Fatalf("Error starting GPRC server:%s", err)
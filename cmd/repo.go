@@ -1,74 +1,219 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/compositeconfigrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/fsconfigrepo"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo/gitconfigrepo"
 	ssh2 "golang.org/x/crypto/ssh"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	"io/ioutil"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+const defaultPullEvery = 30 * time.Second
+
+type remoteConfig struct {
+	URL       string        `mapstructure:"url"`
+	PullEvery time.Duration `mapstructure:"pullEvery"`
+	LocalPath string        `mapstructure:"localPath"`
+	Auth      authConfig    `mapstructure:"auth"`
+	Verify    verifyConfig  `mapstructure:"verify"`
+	// SharedBranch the branch merged underneath every application (i.e. `_common/<version>` or `shared/main`)
+	SharedBranch string `mapstructure:"sharedBranch"`
+}
+
+type authConfig struct {
+	Type            string `mapstructure:"type"`
+	Username        string `mapstructure:"username"`
+	Password        string `mapstructure:"password"`
+	KeyFile         string `mapstructure:"keyFile"`
+	KeyFilePassword string `mapstructure:"keyFilePassword"`
+}
+
+type verifyConfig struct {
+	KeyRingFile string `mapstructure:"keyRingFile"`
+	Policy      string `mapstructure:"policy"`
+}
+
 func initRepo() configrepo.Repo {
-	repoURL := viper.GetString("repo.remote.url")
+	switch repoType := viper.GetString("repo.type"); repoType {
+	case "", "git":
+		if viper.IsSet("repo.remotes") {
+			return initCompositeGitRepo()
+		}
+		remote := getRemoteConfig()
+		return startRepo(initGitRepo(remote), remote.PullEvery)
+	case "fs":
+		viper.SetDefault("repo.local.scanEvery", 10*time.Second)
+		return startRepo(initFsRepo(), viper.GetDuration("repo.local.scanEvery"))
+	default:
+		logrus.Fatalf("unsupported repo type:%s", repoType)
+	}
+	return nil
+}
+
+func startRepo(cfgRepo configrepo.Repo, fetchEvery time.Duration) configrepo.Repo {
+	err := cfgRepo.Init()
+	if err != nil {
+		logrus.Fatalf("error loading the config repo:%s", err)
+	}
+	logrus.Infof("Fetch repo every :%s", fetchEvery)
+	err = cfgRepo.StartFetchingEvery(fetchEvery)
+	if err != nil {
+		logrus.Fatalf("error fetching the repo:%s", err)
+	}
+	return cfgRepo
+}
+
+func initFsRepo() configrepo.Repo {
+	cfgRepo, err := fsconfigrepo.NewFsConfigRepo(viper.GetString("repo.local.path"))
+	if err != nil {
+		logrus.Fatalf("error initializing repo:%s", err)
+	}
+	return cfgRepo
+}
+
+// initCompositeGitRepo creates a repo layer for each repo.remotes entry, the first entry has the highest precedence
+func initCompositeGitRepo() configrepo.Repo {
+	remotes := make([]remoteConfig, 0)
+	err := viper.UnmarshalKey("repo.remotes", &remotes)
+	if err != nil {
+		logrus.Fatalf("error reading repo.remotes:%s", err)
+	}
+	layers := make([]configrepo.Repo, len(remotes))
+	for i := range remotes {
+		if remotes[i].LocalPath == "" {
+			remotes[i].LocalPath = fmt.Sprintf("%s/%d", viper.GetString("repo.local.path"), i)
+		}
+		if remotes[i].PullEvery == 0 {
+			remotes[i].PullEvery = defaultPullEvery
+		}
+		layers[i] = initGitRepo(remotes[i])
+	}
+	cfgRepo, err := compositeconfigrepo.NewCompositeConfigRepo(layers...)
+	if err != nil {
+		logrus.Fatalf("error initializing repo:%s", err)
+	}
+	err = cfgRepo.Init()
+	if err != nil {
+		logrus.Fatalf("error loading the config repo:%s", err)
+	}
+	for i, layer := range layers {
+		logrus.Infof("Fetch repo %s every :%s", remotes[i].URL, remotes[i].PullEvery)
+		err = layer.StartFetchingEvery(remotes[i].PullEvery)
+		if err != nil {
+			logrus.Fatalf("error fetching the repo:%s", err)
+		}
+	}
+	return cfgRepo
+}
+
+func getRemoteConfig() remoteConfig {
+	remote := remoteConfig{}
+	err := viper.UnmarshalKey("repo.remote", &remote)
+	if err != nil {
+		logrus.Fatalf("error reading repo.remote:%s", err)
+	}
+	remote.LocalPath = viper.GetString("repo.local.path")
+	return remote
+}
+
+func initGitRepo(remote remoteConfig) configrepo.Repo {
+	repoURL := remote.URL
 	if strings.Contains(repoURL, "file://") {
 		repoPath := strings.Replace(repoURL, "file://", "", 1)
 		if !path.IsAbs(repoPath) {
 			repoURL, _ = filepath.Abs(repoPath)
 		}
 	}
-	auth, err := getAuth()
+	auth, err := getAuth(remote.Auth)
 	if err != nil {
 		logrus.Fatalf("error initializing repo auth:%s", err)
 	}
-	cfgRepo, err := gitconfigrepo.NewGitConfigRepo(viper.GetString("repo.local.path"), &git.CloneOptions{URL: repoURL, Auth: auth})
+	convention, err := getRefConvention()
+	if err != nil {
+		logrus.Fatalf("error initializing the reference convention:%s", err)
+	}
+	opts := []gitconfigrepo.Option{gitconfigrepo.WithRefConvention(convention)}
+	if remote.Verify.KeyRingFile != "" {
+		verifyOpt, err := getSignatureVerification(remote.Verify)
+		if err != nil {
+			logrus.Fatalf("error initializing the signature verification:%s", err)
+		}
+		opts = append(opts, verifyOpt)
+	}
+	if remote.SharedBranch != "" {
+		opts = append(opts, gitconfigrepo.WithSharedBranch(remote.SharedBranch))
+	}
+	cfgRepo, err := gitconfigrepo.NewGitConfigRepo(remote.LocalPath, &git.CloneOptions{URL: repoURL, Auth: auth}, opts...)
 	if err != nil {
 		logrus.Fatalf("error initializing repo:%s", err)
 	}
-	err = cfgRepo.Init()
+	return cfgRepo
+}
+
+// getRefConvention returns the reference convention configured in repo.refs (the default one if not specified)
+func getRefConvention() (*gitconfigrepo.RefConvention, error) {
+	if !viper.IsSet("repo.refs") {
+		return gitconfigrepo.DefaultRefConvention, nil
+	}
+	return gitconfigrepo.NewRefConvention(
+		viper.GetString("repo.refs.localBranch"),
+		viper.GetString("repo.refs.remoteBranch"),
+		viper.GetString("repo.refs.tag"),
+		viper.GetString("repo.refs.versionPrefix"),
+	)
+}
+
+// getSignatureVerification returns the signature verification option of the keyring file and policy (keepLastTrusted or failClosed)
+func getSignatureVerification(verify verifyConfig) (gitconfigrepo.Option, error) {
+	keyRing, err := ioutil.ReadFile(verify.KeyRingFile)
 	if err != nil {
-		logrus.Fatalf("error loading the config repo:%s", err)
+		return nil, err
 	}
-	pullEvery := viper.GetDuration("repo.remote.pullEvery")
-	logrus.Infof("Fetch repo every :%s", pullEvery)
-	err = cfgRepo.StartFetchingEvery(pullEvery)
+	err = gitconfigrepo.ValidateKeyRing(string(keyRing))
 	if err != nil {
-		logrus.Fatalf("error fetching the repo:%s", err)
+		return nil, err
+	}
+	switch verify.Policy {
+	case "", "keepLastTrusted":
+		return gitconfigrepo.WithSignatureVerification(string(keyRing), gitconfigrepo.KeepLastTrusted), nil
+	case "failClosed":
+		return gitconfigrepo.WithSignatureVerification(string(keyRing), gitconfigrepo.FailClosed), nil
+	default:
+		return nil, fmt.Errorf("unsupported untrusted commit policy:%s", verify.Policy)
 	}
-	return cfgRepo
 }
 
-func getAuth() (transport.AuthMethod, error) {
-	authType := viper.GetString("repo.remote.auth.type")
-	username := viper.GetString("repo.remote.auth.username")
-	switch authType {
+func getAuth(auth authConfig) (transport.AuthMethod, error) {
+	switch auth.Type {
 	case "pubKey":
-		keyFile := viper.GetString("repo.remote.auth.keyFile")
-		keyFilePassword := viper.GetString("repo.remote.auth.keyFilePassword")
-		sshAuth, err := ssh.NewPublicKeysFromFile(username, keyFile, keyFilePassword)
+		sshAuth, err := ssh.NewPublicKeysFromFile(auth.Username, auth.KeyFile, auth.KeyFilePassword)
 		if err != nil {
 			return nil, err
 		}
 		sshAuth.HostKeyCallback = ssh2.InsecureIgnoreHostKey()
 		return sshAuth, nil
 	case "plain":
-		password := viper.GetString("repo.remote.auth.password")
 		return &ssh.Password{
-			User:                  username,
-			Password:              password,
+			User:                  auth.Username,
+			Password:              auth.Password,
 			HostKeyCallbackHelper: ssh.HostKeyCallbackHelper{},
 		}, nil
 	case "http":
-		password := viper.GetString("repo.remote.auth.password")
 		return &http.BasicAuth{
-			Username: username,
-			Password: password,
+			Username: auth.Username,
+			Password: auth.Password,
 		}, nil
 	default:
 		return nil, nil
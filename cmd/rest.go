@@ -1,16 +1,27 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/vecosy/vecosy/v2/internal/restapi"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 )
 
-func startRest(cfgRepo configrepo.Repo) {
+func startRest(cfgRepo configrepo.Repo, schemaValidator *validation.SchemaValidator) {
 	var err error
 	viper.SetDefault("server.rest.address", ":443")
 	restSrv := restapi.New(cfgRepo, viper.GetString("server.rest.address"), viper.GetBool("security.enabled"))
+	for _, provider := range restapi.WebHookProviders {
+		if secret := viper.GetString(fmt.Sprintf("server.rest.webhooks.%s.secret", provider)); secret != "" {
+			logrus.Infof("web hook enabled for %s", provider)
+			restSrv.SetWebHookSecret(provider, secret)
+		}
+	}
+	restSrv.SetAdminPubKey(getAdminPubKey())
+	restSrv.SetEncryptor(getEncryptor())
+	restSrv.SetSchemaValidator(schemaValidator)
 	if viper.GetBool("server.tls.enabled") {
 		err = restSrv.StartTLS(viper.GetString("server.tls.certificateFile"), viper.GetString("server.tls.keyFile"))
 	} else {
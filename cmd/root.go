@@ -6,6 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/vecosy/vecosy/v2/internal/validation"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"net/http"
 	"os"
 	"os/signal"
@@ -24,9 +26,15 @@ var rootCmd = &cobra.Command{
 		if *ignoreTlsCertValidationFlag {
 			http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 		}
+		merger.SetEnvAllowList(viper.GetStringSlice("merge.env.allowed"))
+		if err := merger.SetDefaultSearchPaths(viper.GetStringSlice("merge.searchPaths")); err != nil {
+			logrus.Fatalf("invalid merge.searchPaths:%s", err)
+		}
 		cfgRepo := initRepo()
-		go startRest(cfgRepo)
-		go startGRPC(cfgRepo)
+		// the last served configurations are shared by the APIs
+		schemaValidator := validation.NewSchemaValidator(viper.GetBool("schema.keepLastServed"))
+		go startRest(cfgRepo, schemaValidator)
+		go startGRPC(cfgRepo, schemaValidator)
 		<-waitForever()
 	},
 }
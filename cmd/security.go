@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"crypto/rsa"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"io/ioutil"
+)
+
+// getAdminPubKey returns the public key configured in security.adminPubKeyFile (nil if not specified, the write API is disabled)
+func getAdminPubKey() *rsa.PublicKey {
+	keyFile := viper.GetString("security.adminPubKeyFile")
+	if keyFile == "" {
+		return nil
+	}
+	keyContent, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		logrus.Fatalf("error reading the admin public key:%s", err)
+	}
+	pubKey, err := utils.BytesToPublicKey(keyContent)
+	if err != nil {
+		logrus.Fatalf("error parsing the admin public key:%s", err)
+	}
+	return pubKey
+}
+
+// getEncryptor returns the encryptor configured in encrypt.key (symmetric) or in encrypt.rsa.privateKeyFile
+// (nil if not specified, the `{cipher}` values are not decrypted)
+func getEncryptor() encryption.Encryptor {
+	if key := viper.GetString("encrypt.key"); key != "" {
+		encryptor, err := encryption.NewSymmetricEncryptor(key, viper.GetString("encrypt.salt"))
+		if err != nil {
+			logrus.Fatalf("error configuring the symmetric encryption:%s", err)
+		}
+		return encryptor
+	}
+	keyFile := viper.GetString("encrypt.rsa.privateKeyFile")
+	if keyFile == "" {
+		return nil
+	}
+	keyContent, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		logrus.Fatalf("error reading the encryption private key:%s", err)
+	}
+	privKey, err := utils.BytesToPrivateKey(keyContent)
+	if err != nil {
+		logrus.Fatalf("error parsing the encryption private key:%s", err)
+	}
+	encryptor, err := encryption.NewRSAEncryptor(privKey, viper.GetString("encrypt.rsa.algorithm"), viper.GetString("encrypt.rsa.salt"), viper.GetBool("encrypt.rsa.strong"))
+	if err != nil {
+		logrus.Fatalf("error configuring the RSA encryption:%s", err)
+	}
+	return encryptor
+}
@@ -0,0 +1,121 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// DefaultSalt the default (hex) salt of the spring-cloud-config server
+const DefaultSalt = "deadbeef"
+
+const ivSize = 16
+
+// aesBytesEncryptor the equivalent of the spring-security AesBytesEncryptor:
+// the key is derived by PBKDF2 (HmacSHA1, 1024 iterations, 256 bits) and the random iv is prepended to the encrypted bytes
+type aesBytesEncryptor struct {
+	key []byte
+	// gcm AES/GCM mode (spring `Encryptors.stronger`), otherwise AES/CBC with PKCS5 padding (spring `Encryptors.standard`)
+	gcm bool
+}
+
+func newAESBytesEncryptor(password, hexSalt string, gcm bool) (*aesBytesEncryptor, error) {
+	salt, err := hex.DecodeString(hexSalt)
+	if err != nil {
+		return nil, err
+	}
+	return &aesBytesEncryptor{key: pbkdf2.Key([]byte(password), salt, 1024, 32, sha1.New), gcm: gcm}, nil
+}
+
+func (e *aesBytesEncryptor) encrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ivSize)
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+	if e.gcm {
+		gcm, err := cipher.NewGCMWithNonceSize(block, ivSize)
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Seal(iv, iv, data, nil), nil
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(append(make([]byte, 0, len(data)+padding), data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+	return append(iv, encrypted...), nil
+}
+
+func (e *aesBytesEncryptor) decrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < ivSize {
+		return nil, errors.New("invalid encrypted data")
+	}
+	iv, encrypted := data[:ivSize], data[ivSize:]
+	if e.gcm {
+		gcm, err := cipher.NewGCMWithNonceSize(block, ivSize)
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Open(nil, iv, encrypted, nil)
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted data length")
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, encrypted)
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid padding (wrong key?)")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// symmetricEncryptor the equivalent of the spring-cloud-config `encrypt.key` encryptor (hex encoded)
+type symmetricEncryptor struct {
+	aes *aesBytesEncryptor
+}
+
+// NewSymmetricEncryptor returns the encryptor of a symmetric key, the salt is hex encoded (DefaultSalt if empty)
+func NewSymmetricEncryptor(key, salt string) (Encryptor, error) {
+	if salt == "" {
+		salt = DefaultSalt
+	}
+	aesEncryptor, err := newAESBytesEncryptor(key, salt, false)
+	if err != nil {
+		return nil, err
+	}
+	return &symmetricEncryptor{aes: aesEncryptor}, nil
+}
+
+func (e *symmetricEncryptor) Encrypt(text string) (string, error) {
+	encrypted, err := e.aes.encrypt([]byte(text))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(encrypted), nil
+}
+
+func (e *symmetricEncryptor) Decrypt(encrypted string) (string, error) {
+	data, err := hex.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	text, err := e.aes.decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,88 @@
+package encryption
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CipherPrefix the prefix of the encrypted configuration values (i.e. `password: '{cipher}a3f5...'`)
+const CipherPrefix = "{cipher}"
+
+// ErrDecryption returned if an encrypted value cannot be decrypted
+var ErrDecryption = fmt.Errorf("decryption failed")
+
+// Encryptor encrypts and decrypts the configuration values, it's compatible with the spring-cloud-config server ones
+type Encryptor interface {
+	// Encrypt returns the encrypted text (without the CipherPrefix)
+	Encrypt(text string) (string, error)
+	// Decrypt returns the text of an encrypted one (without the CipherPrefix)
+	Decrypt(encrypted string) (string, error)
+}
+
+// IsEncrypted returns true if the value is an encrypted string
+func IsEncrypted(value interface{}) bool {
+	text, isString := value.(string)
+	return isString && strings.HasPrefix(text, CipherPrefix)
+}
+
+// DecryptValue returns the text of an encrypted value (with the CipherPrefix)
+func DecryptValue(encryptor Encryptor, value string) (string, error) {
+	text, err := encryptor.Decrypt(strings.TrimPrefix(value, CipherPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w:%s", ErrDecryption, err)
+	}
+	return text, nil
+}
+
+// DecryptConfig returns a copy of the configuration with the encrypted values decrypted,
+// the configuration is returned as is if there is no encryptor
+func DecryptConfig(encryptor Encryptor, config map[interface{}]interface{}) (map[interface{}]interface{}, error) {
+	if encryptor == nil {
+		return config, nil
+	}
+	result, err := decrypt(encryptor, config, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[interface{}]interface{}), nil
+}
+
+func decrypt(encryptor Encryptor, value interface{}, path string) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(typedValue))
+		for key, child := range typedValue {
+			childPath := fmt.Sprint(key)
+			if path != "" {
+				childPath = path + "." + childPath
+			}
+			decrypted, err := decrypt(encryptor, child, childPath)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = decrypted
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			decrypted, err := decrypt(encryptor, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = decrypted
+		}
+		return result, nil
+	case string:
+		if !IsEncrypted(typedValue) {
+			return typedValue, nil
+		}
+		text, err := DecryptValue(encryptor, typedValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s", err, path)
+		}
+		return text, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,79 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"testing"
+)
+
+func newTestEncryptors(t *testing.T) map[string]Encryptor {
+	privKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	encryptors := make(map[string]Encryptor)
+	encryptors["symmetric"], err = NewSymmetricEncryptor("my-secret-key", "")
+	assert.NoError(t, err)
+	encryptors["rsa"], err = NewRSAEncryptor(privKey, "", "", false)
+	assert.NoError(t, err)
+	encryptors["rsa-oaep-strong"], err = NewRSAEncryptor(privKey, RSAOAEP, "cafebabe", true)
+	assert.NoError(t, err)
+	return encryptors
+}
+
+func TestEncryptors(t *testing.T) {
+	for name, encryptor := range newTestEncryptors(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, text := range []string{"", "secret", "a longer secret with more than a single block ✓"} {
+				encrypted, err := encryptor.Encrypt(text)
+				assert.NoError(t, err)
+				assert.NotEqual(t, text, encrypted)
+				decrypted, err := encryptor.Decrypt(encrypted)
+				assert.NoError(t, err)
+				assert.Equal(t, text, decrypted)
+			}
+			_, err := encryptor.Decrypt("not-encrypted")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSymmetricEncryptor_WrongKey(t *testing.T) {
+	encryptor, err := NewSymmetricEncryptor("key1", "")
+	assert.NoError(t, err)
+	otherEncryptor, err := NewSymmetricEncryptor("key2", "")
+	assert.NoError(t, err)
+	encrypted, err := encryptor.Encrypt("secret")
+	assert.NoError(t, err)
+	decrypted, err := otherEncryptor.Decrypt(encrypted)
+	if err == nil {
+		// the padding check can't detect every wrong key
+		assert.NotEqual(t, "secret", decrypted)
+	}
+	_, err = NewSymmetricEncryptor("key", "not-hex")
+	assert.Error(t, err)
+}
+
+func TestDecryptConfig(t *testing.T) {
+	encryptor, err := NewSymmetricEncryptor("my-secret-key", "")
+	assert.NoError(t, err)
+	encrypted, err := encryptor.Encrypt("pwd")
+	assert.NoError(t, err)
+	config := map[interface{}]interface{}{
+		"db":      map[interface{}]interface{}{"user": "admin", "password": CipherPrefix + encrypted},
+		"secrets": []interface{}{CipherPrefix + encrypted, 1},
+	}
+	decrypted, err := DecryptConfig(encryptor, config)
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"db":      map[interface{}]interface{}{"user": "admin", "password": "pwd"},
+		"secrets": []interface{}{"pwd", 1},
+	}, decrypted)
+	// without an encryptor the values are not changed
+	notDecrypted, err := DecryptConfig(nil, config)
+	assert.NoError(t, err)
+	assert.Equal(t, config, notDecrypted)
+
+	_, err = DecryptConfig(encryptor, map[interface{}]interface{}{"db": map[interface{}]interface{}{"password": CipherPrefix + "wrong"}})
+	assert.True(t, errors.Is(err, ErrDecryption))
+	assert.Contains(t, err.Error(), "db.password")
+}
@@ -0,0 +1,108 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// RSA algorithms of the encrypted secret
+const (
+	// RSADefault RSA/ECB/PKCS1Padding
+	RSADefault = "DEFAULT"
+	// RSAOAEP RSA/ECB/OAEPPadding
+	RSAOAEP = "OAEP"
+)
+
+const rsaSecretSize = 16
+
+// rsaEncryptor the equivalent of the spring-security-rsa RsaSecretEncryptor (base64 encoded):
+// a random secret encrypted by the RSA key (prefixed by its length) followed by the text encrypted by the secret
+type rsaEncryptor struct {
+	privateKey *rsa.PrivateKey
+	algorithm  string
+	salt       string
+	strong     bool
+}
+
+// NewRSAEncryptor returns the encryptor of a RSA key,
+// the algorithm is RSADefault or RSAOAEP, the salt is hex encoded (DefaultSalt if empty) and strong enables the AES/GCM mode
+func NewRSAEncryptor(privateKey *rsa.PrivateKey, algorithm, salt string, strong bool) (Encryptor, error) {
+	if salt == "" {
+		salt = DefaultSalt
+	}
+	if _, err := hex.DecodeString(salt); err != nil {
+		return nil, err
+	}
+	switch algorithm {
+	case "":
+		algorithm = RSADefault
+	case RSADefault, RSAOAEP:
+	default:
+		return nil, fmt.Errorf("unsupported RSA algorithm:%s", algorithm)
+	}
+	return &rsaEncryptor{privateKey: privateKey, algorithm: algorithm, salt: salt, strong: strong}, nil
+}
+
+func (e *rsaEncryptor) Encrypt(text string) (string, error) {
+	secret := make([]byte, rsaSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	var encryptedSecret []byte
+	var err error
+	if e.algorithm == RSAOAEP {
+		encryptedSecret, err = rsa.EncryptOAEP(sha1.New(), rand.Reader, &e.privateKey.PublicKey, secret, nil)
+	} else {
+		encryptedSecret, err = rsa.EncryptPKCS1v15(rand.Reader, &e.privateKey.PublicKey, secret)
+	}
+	if err != nil {
+		return "", err
+	}
+	aesEncryptor, err := newAESBytesEncryptor(hex.EncodeToString(secret), e.salt, e.strong)
+	if err != nil {
+		return "", err
+	}
+	encryptedText, err := aesEncryptor.encrypt([]byte(text))
+	if err != nil {
+		return "", err
+	}
+	result := append([]byte{byte(len(encryptedSecret) >> 8), byte(len(encryptedSecret))}, encryptedSecret...)
+	return base64.StdEncoding.EncodeToString(append(result, encryptedText...)), nil
+}
+
+func (e *rsaEncryptor) Decrypt(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < 2 {
+		return "", errors.New("invalid encrypted data")
+	}
+	secretLength := int(data[0])<<8 | int(data[1])
+	if len(data) < 2+secretLength {
+		return "", errors.New("invalid encrypted secret length")
+	}
+	var secret []byte
+	if e.algorithm == RSAOAEP {
+		secret, err = rsa.DecryptOAEP(sha1.New(), rand.Reader, e.privateKey, data[2:2+secretLength], nil)
+	} else {
+		secret, err = rsa.DecryptPKCS1v15(rand.Reader, e.privateKey, data[2:2+secretLength])
+	}
+	if err != nil {
+		return "", err
+	}
+	aesEncryptor, err := newAESBytesEncryptor(hex.EncodeToString(secret), e.salt, e.strong)
+	if err != nil {
+		return "", err
+	}
+	text, err := aesEncryptor.decrypt(data[2+secretLength:])
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/validation"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"sort"
+)
+
+// Explain returns the flattened keys of the merged configuration with the files that set them (sorted by key)
+func (s *Server) Explain(ctx context.Context, request *ExplainRequest) (*ExplainResponse, error) {
+	log := logrus.WithField("method", "GRPC:Explain").WithField("request", request)
+	log.Infof("Explain")
+
+	appVersion := configrepo.NewApplicationVersionAtLabel(request.AppName, request.AppVersion, request.Label)
+	err := validation.ValidateApplicationVersion(appVersion)
+	if err != nil {
+		log.Errorf("Error validating the application:%+v", appVersion)
+		return nil, err
+	}
+
+	err = s.CheckToken(ctx, appVersion)
+	if err != nil {
+		log.Errorf("Error checking token:%s", err)
+		return nil, err
+	}
+
+	var explainer merger.ConfigExplainer = merger.ManifestMerger{}
+	if request.Strategy != "" {
+		explainer, err = merger.GetExplainer(request.Strategy)
+	}
+	if err != nil {
+		log.Errorf("Error getting the explainer:%s", err)
+		return nil, err
+	}
+	explanation, err := explainer.Explain(s.repo, appVersion, requestEnvironments("", request.Environments))
+	if err != nil {
+		log.Errorf("error explaining the configuration:%s", err)
+		return nil, err
+	}
+
+	response := &ExplainResponse{Keys: make([]*ExplainedKey, 0, len(explanation))}
+	for key, explainedValue := range explanation {
+		jsonValue, err := json.Marshal(explainedValue.Value)
+		if err != nil {
+			log.Errorf("error encoding the value of %s:%s", key, err)
+			return nil, err
+		}
+		explainedKey := &ExplainedKey{Key: key, JsonValue: string(jsonValue), Origins: make([]*Origin, len(explainedValue.Origins))}
+		for i, origin := range explainedValue.Origins {
+			explainedKey.Origins[i] = &Origin{
+				App:       origin.App,
+				File:      origin.File,
+				Version:   origin.Version,
+				Directive: origin.Directive,
+				Deleted:   origin.Deleted,
+			}
+		}
+		response.Keys = append(response.Keys, explainedKey)
+	}
+	sort.Slice(response.Keys, func(i, j int) bool { return response.Keys[i].Key < response.Keys[j].Key })
+	return response, nil
+}
@@ -0,0 +1,41 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"testing"
+)
+
+func TestServer_Explain(t *testing.T) {
+	check := assert.New(t)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", "config.yml", []byte("db:\n  user: admin\nfeatures: [f1]")))
+	check.NoError(repo.SetFile("app", "1.0.0", "dev/config.yml", []byte("db:\n  user: dev\nfeatures: !append [f2]")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+	version := func(filePath string) string {
+		file, err := repo.GetFile(configrepo.NewApplicationVersion("app", "1.0.0"), filePath)
+		check.NoError(err)
+		return file.Version
+	}
+
+	response, err := srv.Explain(context.Background(), &ExplainRequest{AppName: "app", AppVersion: "1.0.0", Environments: []string{"dev"}})
+	check.NoError(err)
+	check.Len(response.Keys, 2)
+	check.Equal("db.user", response.Keys[0].Key)
+	check.Equal(`"dev"`, response.Keys[0].JsonValue)
+	check.Len(response.Keys[0].Origins, 2)
+	check.Equal("features", response.Keys[1].Key)
+	check.Equal(`["f1","f2"]`, response.Keys[1].JsonValue)
+	check.Equal("dev/config.yml", response.Keys[1].Origins[1].File)
+	check.Equal(version("dev/config.yml"), response.Keys[1].Origins[1].Version)
+	check.Equal(merger.AppendDirective, response.Keys[1].Origins[1].Directive)
+
+	_, err = srv.Explain(context.Background(), &ExplainRequest{AppName: "app", AppVersion: "1.0.0", Strategy: "unknown"})
+	check.True(errors.Is(err, merger.ErrUnknownStrategy))
+}
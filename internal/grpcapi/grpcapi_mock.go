@@ -55,6 +55,26 @@ func (mr *MockSmartConfigClientMockRecorder) GetConfig(ctx, in interface{}, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockSmartConfigClient)(nil).GetConfig), varargs...)
 }
 
+// Explain mocks base method
+func (m *MockSmartConfigClient) Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Explain", varargs...)
+	ret0, _ := ret[0].(*ExplainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain
+func (mr *MockSmartConfigClientMockRecorder) Explain(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockSmartConfigClient)(nil).Explain), varargs...)
+}
+
 // MockSmartConfigServer is a mock of SmartConfigServer interface
 type MockSmartConfigServer struct {
 	ctrl     *gomock.Controller
@@ -93,6 +113,21 @@ func (mr *MockSmartConfigServerMockRecorder) GetConfig(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockSmartConfigServer)(nil).GetConfig), arg0, arg1)
 }
 
+// Explain mocks base method
+func (m *MockSmartConfigServer) Explain(arg0 context.Context, arg1 *ExplainRequest) (*ExplainResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", arg0, arg1)
+	ret0, _ := ret[0].(*ExplainResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain
+func (mr *MockSmartConfigServerMockRecorder) Explain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockSmartConfigServer)(nil).Explain), arg0, arg1)
+}
+
 // MockRawClient is a mock of RawClient interface
 type MockRawClient struct {
 	ctrl     *gomock.Controller
@@ -136,6 +171,26 @@ func (mr *MockRawClientMockRecorder) GetFile(ctx, in interface{}, opts ...interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getFile", reflect.TypeOf((*MockRawClient)(nil).GetFile), varargs...)
 }
 
+// WriteFile mocks base method
+func (m *MockRawClient) WriteFile(ctx context.Context, in *WriteFileRequest, opts ...grpc.CallOption) (*WriteFileResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WriteFile", varargs...)
+	ret0, _ := ret[0].(*WriteFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteFile indicates an expected call of WriteFile
+func (mr *MockRawClientMockRecorder) WriteFile(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteFile", reflect.TypeOf((*MockRawClient)(nil).WriteFile), varargs...)
+}
+
 // MockRawServer is a mock of RawServer interface
 type MockRawServer struct {
 	ctrl     *gomock.Controller
@@ -174,6 +229,21 @@ func (mr *MockRawServerMockRecorder) GetFile(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getFile", reflect.TypeOf((*MockRawServer)(nil).GetFile), arg0, arg1)
 }
 
+// WriteFile mocks base method
+func (m *MockRawServer) WriteFile(arg0 context.Context, arg1 *WriteFileRequest) (*WriteFileResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteFile", arg0, arg1)
+	ret0, _ := ret[0].(*WriteFileResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteFile indicates an expected call of WriteFile
+func (mr *MockRawServerMockRecorder) WriteFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteFile", reflect.TypeOf((*MockRawServer)(nil).WriteFile), arg0, arg1)
+}
+
 // MockWatchServiceClient is a mock of WatchServiceClient interface
 type MockWatchServiceClient struct {
 	ctrl     *gomock.Controller
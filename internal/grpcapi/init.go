@@ -1,8 +1,11 @@
 package grpcapi
 
 import (
+	"crypto/rsa"
 	"github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -12,11 +15,13 @@ import (
 
 // Watcher represent an application watcher connected through GRPC
 type Watcher struct {
-	id          string
-	watcherName string
-	appName     string
-	appVersion  *version.Version
-	ch          chan *WatchResponse
+	id           string
+	watcherName  string
+	appName      string
+	appVersion   *version.Version
+	environments []string
+	ch           chan *WatchResponse
+	done         <-chan struct{}
 }
 
 // Server represent a GRPC server
@@ -25,7 +30,11 @@ type Server struct {
 	server          *grpc.Server
 	address         string
 	watchers        sync.Map
+	watchersOnce    sync.Once
 	securityEnabled bool
+	adminPubKey     *rsa.PublicKey
+	encryptor       encryption.Encryptor
+	schemaValidator *validation.SchemaValidator
 }
 
 // NewTLS instantiate a new GRPC server with TLS enabled
@@ -34,7 +43,7 @@ func NewTLS(repo configrepo.Repo, address string, securityEnabled bool, certFile
 	if err != nil {
 		return nil, err
 	}
-	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled}
+	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled, schemaValidator: validation.NewSchemaValidator(false)}
 	s.server = grpc.NewServer(grpc.Creds(tlsCreds))
 	s.registerServices()
 	return s, nil
@@ -42,7 +51,7 @@ func NewTLS(repo configrepo.Repo, address string, securityEnabled bool, certFile
 
 // NewNoTLS instantiate a new GRPC server without TLS
 func NewNoTLS(repo configrepo.Repo, address string, securityEnabled bool) (*Server, error) {
-	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled}
+	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled, schemaValidator: validation.NewSchemaValidator(false)}
 	s.server = grpc.NewServer()
 	s.registerServices()
 	return s, nil
@@ -65,6 +74,11 @@ func (s *Server) Stop() {
 	s.server.Stop()
 }
 
+// SetSchemaValidator set the validator of the served configurations (see validation.SchemaValidator)
+func (s *Server) SetSchemaValidator(validator *validation.SchemaValidator) {
+	s.schemaValidator = validator
+}
+
 // IsSecurityEnabled return if the server has the security enabled
 func (s *Server) IsSecurityEnabled() bool {
 	return s.securityEnabled
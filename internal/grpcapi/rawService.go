@@ -2,15 +2,18 @@ package grpcapi
 
 import (
 	"context"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GetFile returns a raw file on the repo
 func (s *Server) GetFile(ctx context.Context, request *GetFileRequest) (*GetFileResponse, error) {
 	log := logrus.WithField("method", "GetFile").WithField("request", request)
-	appVersion := configrepo.NewApplicationVersion(request.AppName, request.AppVersion)
+	appVersion := configrepo.NewApplicationVersionAtLabel(request.AppName, request.AppVersion, request.Label)
 	err := validation.ValidateApplicationVersion(appVersion)
 	if err != nil {
 		log.Errorf("Error validating the application:%+v", appVersion)
@@ -22,7 +25,7 @@ func (s *Server) GetFile(ctx context.Context, request *GetFileRequest) (*GetFile
 		return nil, err
 	}
 
-	file, err := s.repo.GetFile(appVersion, request.FilePath)
+	file, err := configrepo.GetFileOrShared(s.repo, appVersion, request.FilePath)
 	if err != nil {
 		log.Errorf("Error getting file %s: %s", request.FilePath, err)
 		return nil, err
@@ -31,3 +34,53 @@ func (s *Server) GetFile(ctx context.Context, request *GetFileRequest) (*GetFile
 		FileContent: file.Content,
 	}, nil
 }
+
+// WriteFile commit a file change (or removal) to the application version, the admin token is required
+//
+// a change based on an outdated version (ExpectedVersion) fails with the Aborted code
+func (s *Server) WriteFile(ctx context.Context, request *WriteFileRequest) (*WriteFileResponse, error) {
+	log := logrus.WithField("method", "WriteFile").WithField("appName", request.AppName).WithField("appVersion", request.AppVersion)
+	log = log.WithField("filePath", request.FilePath).WithField("delete", request.Delete)
+	appVersion := configrepo.NewApplicationVersion(request.AppName, request.AppVersion)
+	err := validation.ValidateApplicationVersion(appVersion)
+	if err != nil {
+		log.Errorf("Error validating the application:%+v", appVersion)
+		return nil, err
+	}
+	err = s.CheckAdminToken(ctx)
+	if err != nil {
+		log.Errorf("Error checking the admin token:%s", err)
+		return nil, err
+	}
+	writer, isWriter := s.repo.(configrepo.Writer)
+	if !isWriter {
+		return nil, status.Error(codes.Unimplemented, configrepo.ErrWriteNotSupported.Error())
+	}
+	if request.AuthorName == "" || request.AuthorEmail == "" || request.Message == "" {
+		return nil, status.Error(codes.InvalidArgument, "authorName, authorEmail and message are mandatory")
+	}
+	opts := &configrepo.WriteOptions{
+		AuthorName:      request.AuthorName,
+		AuthorEmail:     request.AuthorEmail,
+		Message:         request.Message,
+		ExpectedVersion: request.ExpectedVersion,
+	}
+	var newVersion string
+	if request.Delete {
+		newVersion, err = writer.DeleteFile(appVersion, request.FilePath, opts)
+	} else {
+		content := request.FileContent
+		if content == nil {
+			content = []byte{}
+		}
+		newVersion, err = writer.WriteFile(appVersion, request.FilePath, content, opts)
+	}
+	if err != nil {
+		log.Errorf("Error writing file %s: %s", request.FilePath, err)
+		if errors.Is(err, configrepo.ErrConflict) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, err
+	}
+	return &WriteFileResponse{Version: newVersion}, nil
+}
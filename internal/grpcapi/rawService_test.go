@@ -11,7 +11,9 @@ import (
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"testing"
 )
 
@@ -147,3 +149,115 @@ func TestServer_GetFile_InvalidApp(t *testing.T) {
 	check.Equal(err, validation.ErrInvalidVersion)
 	check.Nil(response)
 }
+
+func TestServer_GetFile_Label(t *testing.T) {
+	check := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	privKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+
+	mockRepo := mocks.NewMockRepo(ctrl)
+	srv, err := NewNoTLS(mockRepo, ":8080", true)
+	check.NoError(err)
+	label := uuid.New().String()
+	app := configrepo.NewApplicationVersionAtLabel("app", "1.0.0", label)
+	filePath := "config.yml"
+	repoFile := &configrepo.RepoFile{
+		Version: label,
+		Content: []byte(uuid.New().String()),
+	}
+	mockRepo.EXPECT().GetFile(app, filePath).Return(repoFile, nil)
+	request := &GetFileRequest{
+		AppName:    app.AppName,
+		AppVersion: app.AppVersion,
+		FilePath:   filePath,
+		Label:      label,
+	}
+	// the token is checked with the public key of the application version head
+	ctx := applySecurityIn(context.Background(), t, privKey, mockRepo, app.AppName, app.AppVersion)
+	response, err := srv.GetFile(ctx, request)
+	check.NoError(err)
+	check.Equal(repoFile.Content, response.FileContent)
+}
+
+type writerRepo struct {
+	*mocks.MockRepo
+	*mocks.MockWriter
+}
+
+func TestServer_WriteFile(t *testing.T) {
+	check := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	adminKey, _, err := testutil.GenerateKeyPair()
+	check.NoError(err)
+	wrongKey, _, err := testutil.GenerateKeyPair()
+	check.NoError(err)
+
+	repo := &writerRepo{mocks.NewMockRepo(ctrl), mocks.NewMockWriter(ctrl)}
+	srv, err := NewNoTLS(repo, ":8080", true)
+	check.NoError(err)
+	srv.SetAdminPubKey(&adminKey.PublicKey)
+	app := configrepo.NewApplicationVersion("app", "1.0.0")
+	request := &WriteFileRequest{
+		AppName:         app.AppName,
+		AppVersion:      app.AppVersion,
+		FilePath:        "config.yml",
+		FileContent:     []byte(uuid.New().String()),
+		AuthorName:      "deploy bot",
+		AuthorEmail:     "bot@vecosy.io",
+		Message:         "updated",
+		ExpectedVersion: uuid.New().String(),
+	}
+	opts := &configrepo.WriteOptions{
+		AuthorName:      request.AuthorName,
+		AuthorEmail:     request.AuthorEmail,
+		Message:         request.Message,
+		ExpectedVersion: request.ExpectedVersion,
+	}
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.MD{"token": []string{testutil.GenJwsFromPrivateKey(t, adminKey, "admin").FullSerialize()}})
+
+	newVersion := uuid.New().String()
+	repo.MockWriter.EXPECT().WriteFile(app, request.FilePath, request.FileContent, opts).Return(newVersion, nil)
+	response, err := srv.WriteFile(adminCtx, request)
+	check.NoError(err)
+	check.Equal(newVersion, response.Version)
+
+	repo.MockWriter.EXPECT().WriteFile(app, request.FilePath, request.FileContent, opts).Return("", configrepo.ErrConflict)
+	_, err = srv.WriteFile(adminCtx, request)
+	check.Equal(codes.Aborted, status.Code(err))
+
+	deleteRequest := *request
+	deleteRequest.Delete = true
+	repo.MockWriter.EXPECT().DeleteFile(app, request.FilePath, opts).Return(newVersion, nil)
+	response, err = srv.WriteFile(adminCtx, &deleteRequest)
+	check.NoError(err)
+	check.Equal(newVersion, response.Version)
+
+	wrongCtx := metadata.NewIncomingContext(context.Background(), metadata.MD{"token": []string{testutil.GenJwsFromPrivateKey(t, wrongKey, "admin").FullSerialize()}})
+	_, err = srv.WriteFile(wrongCtx, request)
+	check.Equal(security.ErrAuthFailed, err)
+}
+
+func TestServer_WriteFile_NoAdminKey(t *testing.T) {
+	check := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// the writes are rejected even if the security is disabled
+	srv, err := NewNoTLS(&writerRepo{mocks.NewMockRepo(ctrl), mocks.NewMockWriter(ctrl)}, ":8080", false)
+	check.NoError(err)
+	request := &WriteFileRequest{
+		AppName:     "app",
+		AppVersion:  "1.0.0",
+		FilePath:    "config.yml",
+		FileContent: []byte("prop: value"),
+		AuthorName:  "deploy bot",
+		AuthorEmail: "bot@vecosy.io",
+		Message:     "updated",
+	}
+	_, err = srv.WriteFile(metadata.NewIncomingContext(context.Background(), metadata.MD{"token": []string{"token"}}), request)
+	check.Equal(security.ErrAuthFailed, err)
+	_, err = srv.WriteFile(context.Background(), request)
+	check.Error(err)
+}
@@ -2,7 +2,9 @@ package grpcapi
 
 import (
 	"context"
+	"crypto/rsa"
 	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
 	"github.com/vecosy/vecosy/v2/internal/security"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"google.golang.org/grpc/metadata"
@@ -10,23 +12,48 @@ import (
 
 // CheckToken checks if the request has a valid token on the GRPC metadata
 func (s *Server) CheckToken(ctx context.Context, app *configrepo.ApplicationVersion) error {
-	log := logrus.WithField("method", "CheckToken")
 	if !s.IsSecurityEnabled() {
 		return nil
 	}
+	token, err := getToken(ctx)
+	if err != nil {
+		return err
+	}
+	return security.CheckJwtToken(s.repo, app, token)
+}
+
+// CheckAdminToken checks if the request has a valid admin token (signed with the admin private key) on the GRPC metadata
+//
+// the admin token is checked even if the security is disabled, the request is rejected if no admin key is configured
+func (s *Server) CheckAdminToken(ctx context.Context) error {
+	token, err := getToken(ctx)
+	if err != nil {
+		return err
+	}
+	return security.CheckAdminToken(s.adminPubKey, token)
+}
+
+// SetAdminPubKey set the public key used to check the admin tokens
+func (s *Server) SetAdminPubKey(pubKey *rsa.PublicKey) {
+	s.adminPubKey = pubKey
+}
+
+// SetEncryptor set the encryptor of the `{cipher}` values (nil to leave them encrypted)
+func (s *Server) SetEncryptor(encryptor encryption.Encryptor) {
+	s.encryptor = encryptor
+}
+
+func getToken(ctx context.Context) (string, error) {
+	log := logrus.WithField("method", "getToken")
 	md, found := metadata.FromIncomingContext(ctx)
 	if !found {
-		return security.ErrNoMetadataFound
+		return "", security.ErrNoMetadataFound
 	}
 	tokens := md.Get("token")
 	if len(tokens) != 1 {
-		return security.ErrAuthFailed
+		return "", security.ErrAuthFailed
 	}
 	token := tokens[0]
 	log.Debugf("metadata token:%s", token)
-	err := security.CheckJwtToken(s.repo, app, token)
-	if err != nil {
-		return err
-	}
-	return nil
+	return token, nil
 }
@@ -2,22 +2,25 @@ package grpcapi
 
 import (
 	"context"
+	"fmt"
 	"github.com/ghodss/yaml"
 	"github.com/sirupsen/logrus"
-	"github.com/vecosy/vecosy/v2/internal/merger"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
 	"github.com/vecosy/vecosy/v2/internal/utils"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 )
 
-var smartConfigFileMerger = merger.SmartConfigMerger{}
+// configFileMerger merge the configuration following the strategy declared by the application manifest
+var configFileMerger = merger.ManifestMerger{}
 
-// GetConfig returns the merged configuration based on smartconfig strategies
+// GetConfig returns the merged configuration based on the strategy of the application manifest (smart config by default)
 func (s *Server) GetConfig(ctx context.Context, request *GetConfigRequest) (*GetConfigResponse, error) {
 	log := logrus.WithField("method", "GRPC:GetConfig").WithField("request", request)
 	log.Infof("GetConfig")
 
-	appVersion := configrepo.NewApplicationVersion(request.AppName, request.AppVersion)
+	appVersion := configrepo.NewApplicationVersionAtLabel(request.AppName, request.AppVersion, request.Label)
 	err := validation.ValidateApplicationVersion(appVersion)
 	if err != nil {
 		log.Errorf("Error validating the application:%+v", appVersion)
@@ -30,9 +33,32 @@ func (s *Server) GetConfig(ctx context.Context, request *GetConfigRequest) (*Get
 		return nil, err
 	}
 
-	config, err := smartConfigFileMerger.Merge(s.repo, appVersion, []string{request.Environment})
+	environments := requestEnvironments(request.Environment, request.Environments)
+	config, err := configFileMerger.Merge(s.repo, appVersion, environments)
 	if err != nil {
-		log.Errorf("error merging smartconfig:%s", err)
+		log.Errorf("error merging the configuration:%s", err)
+		return nil, err
+	}
+
+	config, err = encryption.DecryptConfig(s.encryptor, config)
+	if err != nil {
+		log.Errorf("error decrypting the configuration:%s", err)
+		return nil, err
+	}
+
+	if !request.DisableInterpolation {
+		config, err = merger.Interpolate(config)
+		if err != nil {
+			log.Errorf("error resolving the placeholders:%s", err)
+			return nil, err
+		}
+	}
+
+	// the same key of the REST smart config endpoint, the last served configurations are shared when the servers share the validator
+	key := validation.NewRequestKey("smart", appVersion, environments, fmt.Sprintf("interpolate:%t", !request.DisableInterpolation))
+	config, err = s.schemaValidator.Validate(s.repo, appVersion, key, config)
+	if err != nil {
+		log.Errorf("error validating the configuration:%s", err)
 		return nil, err
 	}
 
@@ -43,7 +69,6 @@ func (s *Server) GetConfig(ctx context.Context, request *GetConfigRequest) (*Get
 	}
 
 	yml, err := yaml.Marshal(normConfig)
-	logrus.Debugf("received:%s", string(yml))
 	if err != nil {
 		log.Errorf("error generating yaml:%s", err)
 		return nil, err
@@ -2,16 +2,21 @@ package grpcapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ghodss/yaml"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
 	"github.com/vecosy/vecosy/v2/internal/security"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/internal/testutil/repotest"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"google.golang.org/grpc/metadata"
 	"testing"
 )
@@ -27,6 +32,7 @@ func TestServer_GetConfig(t *testing.T) {
 		t.Run(fmt.Sprintf("GetConfig_Security_%v", security), func(t *testing.T) {
 
 			mockRepo := mocks.NewMockRepo(ctrl)
+			repotest.ExpectOnlyYmlSources(mockRepo)
 			srv, err := NewNoTLS(mockRepo, ":8080", security)
 			check.NoError(err)
 			check.NotNil(srv)
@@ -72,6 +78,7 @@ func TestServer_GetConfig_NotFound(t *testing.T) {
 	for _, security := range []bool{false, true} {
 		t.Run(fmt.Sprintf("GetConfig_NotFound_Security_%v", security), func(t *testing.T) {
 			mockRepo := mocks.NewMockRepo(ctrl)
+			repotest.ExpectOnlyYmlSources(mockRepo)
 			srv, err := NewNoTLS(mockRepo, ":8080", security)
 			check.NoError(err)
 			check.NotNil(srv)
@@ -154,3 +161,110 @@ func TestServer_GetConfig_InvalidApp(t *testing.T) {
 	check.Equal(err, validation.ErrInvalidVersion)
 	check.Nil(response)
 }
+
+func TestServer_GetConfig_Interpolation(t *testing.T) {
+	check := assert.New(t)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", "config.yml", []byte("host: localhost\nurl: http://${host}")))
+	check.NoError(repo.SetFile("app", "1.0.0", "dev/config.yml", []byte("url: ${missing}")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+
+	request := &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "int"}
+	response, err := srv.GetConfig(context.Background(), request)
+	check.NoError(err)
+	check.Contains(response.ConfigContent, "url: http://localhost")
+
+	request.DisableInterpolation = true
+	response, err = srv.GetConfig(context.Background(), request)
+	check.NoError(err)
+	check.Contains(response.ConfigContent, "url: http://${host}")
+
+	// the missing keys are left untouched
+	request = &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "dev"}
+	response, err = srv.GetConfig(context.Background(), request)
+	check.NoError(err)
+	check.Contains(response.ConfigContent, "url: ${missing}")
+}
+
+func TestServer_GetConfig_Environments(t *testing.T) {
+	check := assert.New(t)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", "config.yml", []byte("region: none\nfeature: false")))
+	check.NoError(repo.SetFile("app", "1.0.0", "prod/config.yml", []byte("region: prod")))
+	check.NoError(repo.SetFile("app", "1.0.0", "eu-west/config.yml", []byte("region: eu-west")))
+	check.NoError(repo.SetFile("app", "1.0.0", "canary/config.yml", []byte("feature: true")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+
+	request := &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "prod", Environments: []string{"eu-west", "canary"}}
+	response, err := srv.GetConfig(context.Background(), request)
+	check.NoError(err)
+	check.Equal("feature: true\nregion: eu-west\n", response.ConfigContent)
+
+	request = &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environments: []string{"eu-west", "prod"}}
+	response, err = srv.GetConfig(context.Background(), request)
+	check.NoError(err)
+	check.Equal("feature: false\nregion: prod\n", response.ConfigContent)
+}
+
+func TestServer_GetConfig_ManifestStrategy(t *testing.T) {
+	check := assert.New(t)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", merger.ManifestFile, []byte("strategy: spring\nlayout:\n  common: defaults")))
+	check.NoError(repo.SetFile("app", "1.0.0", "defaults.yml", []byte("port: 8080\nname: app")))
+	check.NoError(repo.SetFile("app", "1.0.0", "app-prod.yml", []byte("port: 9090")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+
+	response, err := srv.GetConfig(context.Background(), &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "prod"})
+	check.NoError(err)
+	check.Equal("name: app\nport: 9090\n", response.ConfigContent)
+
+	explanation, err := srv.Explain(context.Background(), &ExplainRequest{AppName: "app", AppVersion: "1.0.0", Environments: []string{"prod"}})
+	check.NoError(err)
+	check.Len(explanation.Keys, 2)
+	check.Equal("app-prod.yml", explanation.Keys[1].Origins[1].File)
+}
+
+func TestServer_GetConfig_CipherValues(t *testing.T) {
+	check := assert.New(t)
+	encryptor, err := encryption.NewSymmetricEncryptor("my-secret-key", "")
+	check.NoError(err)
+	encrypted, err := encryptor.Encrypt("pwd")
+	check.NoError(err)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", "config.yml", []byte("password: '{cipher}"+encrypted+"'")))
+	check.NoError(repo.SetFile("app", "1.0.0", "dev/config.yml", []byte("password: '{cipher}wrong'")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+	srv.SetEncryptor(encryptor)
+
+	response, err := srv.GetConfig(context.Background(), &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "int"})
+	check.NoError(err)
+	check.Equal("password: pwd\n", response.ConfigContent)
+	_, err = srv.GetConfig(context.Background(), &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "dev"})
+	check.True(errors.Is(err, encryption.ErrDecryption))
+}
+
+func TestServer_GetConfig_Schema(t *testing.T) {
+	check := assert.New(t)
+	repo := memconfigrepo.NewMemConfigRepo()
+	check.NoError(repo.SetFile("app", "1.0.0", "schema.json", []byte(`{"properties": {"port": {"type": "integer"}}}`)))
+	check.NoError(repo.SetFile("app", "1.0.0", "config.yml", []byte("port: 8080")))
+	check.NoError(repo.SetFile("app", "1.0.0", "prod/config.yml", []byte("port: 80a80")))
+	check.NoError(repo.Init())
+	srv, err := NewNoTLS(repo, ":8080", false)
+	check.NoError(err)
+
+	response, err := srv.GetConfig(context.Background(), &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "dev"})
+	check.NoError(err)
+	check.Equal("port: 8080\n", response.ConfigContent)
+	_, err = srv.GetConfig(context.Background(), &GetConfigRequest{AppName: "app", AppVersion: "1.0.0", Environment: "prod"})
+	check.True(errors.Is(err, validation.ErrInvalidConfig))
+	check.Contains(err.Error(), "port: Invalid type")
+}
@@ -28,6 +28,9 @@ type GetConfigRequest struct {
 	AppName              string   `protobuf:"bytes,1,opt,name=appName,proto3" json:"appName,omitempty"`
 	AppVersion           string   `protobuf:"bytes,2,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
 	Environment          string   `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	Label                string   `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	DisableInterpolation bool     `protobuf:"varint,5,opt,name=disableInterpolation,proto3" json:"disableInterpolation,omitempty"`
+	Environments         []string `protobuf:"bytes,6,rep,name=environments,proto3" json:"environments,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -79,6 +82,27 @@ func (m *GetConfigRequest) GetEnvironment() string {
 	return ""
 }
 
+func (m *GetConfigRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *GetConfigRequest) GetDisableInterpolation() bool {
+	if m != nil {
+		return m.DisableInterpolation
+	}
+	return false
+}
+
+func (m *GetConfigRequest) GetEnvironments() []string {
+	if m != nil {
+		return m.Environments
+	}
+	return nil
+}
+
 type GetConfigResponse struct {
 	ConfigContent        string   `protobuf:"bytes,1,opt,name=configContent,proto3" json:"configContent,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -118,6 +142,242 @@ func (m *GetConfigResponse) GetConfigContent() string {
 	return ""
 }
 
+type ExplainRequest struct {
+	AppName              string   `protobuf:"bytes,1,opt,name=appName,proto3" json:"appName,omitempty"`
+	AppVersion           string   `protobuf:"bytes,2,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
+	Environments         []string `protobuf:"bytes,3,rep,name=environments,proto3" json:"environments,omitempty"`
+	Label                string   `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	Strategy             string   `protobuf:"bytes,5,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExplainRequest) Reset()         { *m = ExplainRequest{} }
+func (m *ExplainRequest) String() string { return proto.CompactTextString(m) }
+func (*ExplainRequest) ProtoMessage()    {}
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{2}
+}
+
+func (m *ExplainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExplainRequest.Unmarshal(m, b)
+}
+func (m *ExplainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExplainRequest.Marshal(b, m, deterministic)
+}
+func (m *ExplainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainRequest.Merge(m, src)
+}
+func (m *ExplainRequest) XXX_Size() int {
+	return xxx_messageInfo_ExplainRequest.Size(m)
+}
+func (m *ExplainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExplainRequest proto.InternalMessageInfo
+
+func (m *ExplainRequest) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *ExplainRequest) GetAppVersion() string {
+	if m != nil {
+		return m.AppVersion
+	}
+	return ""
+}
+
+func (m *ExplainRequest) GetEnvironments() []string {
+	if m != nil {
+		return m.Environments
+	}
+	return nil
+}
+
+func (m *ExplainRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *ExplainRequest) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+type Origin struct {
+	App                  string   `protobuf:"bytes,1,opt,name=app,proto3" json:"app,omitempty"`
+	File                 string   `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Version              string   `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Directive            string   `protobuf:"bytes,4,opt,name=directive,proto3" json:"directive,omitempty"`
+	Deleted              bool     `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Origin) Reset()         { *m = Origin{} }
+func (m *Origin) String() string { return proto.CompactTextString(m) }
+func (*Origin) ProtoMessage()    {}
+func (*Origin) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{3}
+}
+
+func (m *Origin) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Origin.Unmarshal(m, b)
+}
+func (m *Origin) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Origin.Marshal(b, m, deterministic)
+}
+func (m *Origin) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Origin.Merge(m, src)
+}
+func (m *Origin) XXX_Size() int {
+	return xxx_messageInfo_Origin.Size(m)
+}
+func (m *Origin) XXX_DiscardUnknown() {
+	xxx_messageInfo_Origin.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Origin proto.InternalMessageInfo
+
+func (m *Origin) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *Origin) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *Origin) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *Origin) GetDirective() string {
+	if m != nil {
+		return m.Directive
+	}
+	return ""
+}
+
+func (m *Origin) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+type ExplainedKey struct {
+	Key                  string    `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	JsonValue            string    `protobuf:"bytes,2,opt,name=jsonValue,proto3" json:"jsonValue,omitempty"`
+	Origins              []*Origin `protobuf:"bytes,3,rep,name=origins,proto3" json:"origins,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ExplainedKey) Reset()         { *m = ExplainedKey{} }
+func (m *ExplainedKey) String() string { return proto.CompactTextString(m) }
+func (*ExplainedKey) ProtoMessage()    {}
+func (*ExplainedKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{4}
+}
+
+func (m *ExplainedKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExplainedKey.Unmarshal(m, b)
+}
+func (m *ExplainedKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExplainedKey.Marshal(b, m, deterministic)
+}
+func (m *ExplainedKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainedKey.Merge(m, src)
+}
+func (m *ExplainedKey) XXX_Size() int {
+	return xxx_messageInfo_ExplainedKey.Size(m)
+}
+func (m *ExplainedKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainedKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExplainedKey proto.InternalMessageInfo
+
+func (m *ExplainedKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ExplainedKey) GetJsonValue() string {
+	if m != nil {
+		return m.JsonValue
+	}
+	return ""
+}
+
+func (m *ExplainedKey) GetOrigins() []*Origin {
+	if m != nil {
+		return m.Origins
+	}
+	return nil
+}
+
+type ExplainResponse struct {
+	Keys                 []*ExplainedKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExplainResponse) Reset()         { *m = ExplainResponse{} }
+func (m *ExplainResponse) String() string { return proto.CompactTextString(m) }
+func (*ExplainResponse) ProtoMessage()    {}
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{5}
+}
+
+func (m *ExplainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExplainResponse.Unmarshal(m, b)
+}
+func (m *ExplainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExplainResponse.Marshal(b, m, deterministic)
+}
+func (m *ExplainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExplainResponse.Merge(m, src)
+}
+func (m *ExplainResponse) XXX_Size() int {
+	return xxx_messageInfo_ExplainResponse.Size(m)
+}
+func (m *ExplainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExplainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExplainResponse proto.InternalMessageInfo
+
+func (m *ExplainResponse) GetKeys() []*ExplainedKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
 type GetFileResponse struct {
 	FileContent          []byte   `protobuf:"bytes,1,opt,name=fileContent,proto3" json:"fileContent,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -129,7 +389,7 @@ func (m *GetFileResponse) Reset()         { *m = GetFileResponse{} }
 func (m *GetFileResponse) String() string { return proto.CompactTextString(m) }
 func (*GetFileResponse) ProtoMessage()    {}
 func (*GetFileResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4b068b76ab253eb, []int{2}
+	return fileDescriptor_f4b068b76ab253eb, []int{6}
 }
 
 func (m *GetFileResponse) XXX_Unmarshal(b []byte) error {
@@ -161,6 +421,7 @@ type GetFileRequest struct {
 	AppName              string   `protobuf:"bytes,1,opt,name=appName,proto3" json:"appName,omitempty"`
 	AppVersion           string   `protobuf:"bytes,2,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
 	FilePath             string   `protobuf:"bytes,3,opt,name=filePath,proto3" json:"filePath,omitempty"`
+	Label                string   `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -170,7 +431,7 @@ func (m *GetFileRequest) Reset()         { *m = GetFileRequest{} }
 func (m *GetFileRequest) String() string { return proto.CompactTextString(m) }
 func (*GetFileRequest) ProtoMessage()    {}
 func (*GetFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4b068b76ab253eb, []int{3}
+	return fileDescriptor_f4b068b76ab253eb, []int{7}
 }
 
 func (m *GetFileRequest) XXX_Unmarshal(b []byte) error {
@@ -212,6 +473,155 @@ func (m *GetFileRequest) GetFilePath() string {
 	return ""
 }
 
+func (m *GetFileRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+type WriteFileRequest struct {
+	AppName              string   `protobuf:"bytes,1,opt,name=appName,proto3" json:"appName,omitempty"`
+	AppVersion           string   `protobuf:"bytes,2,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
+	FilePath             string   `protobuf:"bytes,3,opt,name=filePath,proto3" json:"filePath,omitempty"`
+	FileContent          []byte   `protobuf:"bytes,4,opt,name=fileContent,proto3" json:"fileContent,omitempty"`
+	Delete               bool     `protobuf:"varint,5,opt,name=delete,proto3" json:"delete,omitempty"`
+	AuthorName           string   `protobuf:"bytes,6,opt,name=authorName,proto3" json:"authorName,omitempty"`
+	AuthorEmail          string   `protobuf:"bytes,7,opt,name=authorEmail,proto3" json:"authorEmail,omitempty"`
+	Message              string   `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	ExpectedVersion      string   `protobuf:"bytes,9,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteFileRequest) Reset()         { *m = WriteFileRequest{} }
+func (m *WriteFileRequest) String() string { return proto.CompactTextString(m) }
+func (*WriteFileRequest) ProtoMessage()    {}
+func (*WriteFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{8}
+}
+
+func (m *WriteFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteFileRequest.Unmarshal(m, b)
+}
+func (m *WriteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteFileRequest.Marshal(b, m, deterministic)
+}
+func (m *WriteFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteFileRequest.Merge(m, src)
+}
+func (m *WriteFileRequest) XXX_Size() int {
+	return xxx_messageInfo_WriteFileRequest.Size(m)
+}
+func (m *WriteFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteFileRequest proto.InternalMessageInfo
+
+func (m *WriteFileRequest) GetAppName() string {
+	if m != nil {
+		return m.AppName
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetAppVersion() string {
+	if m != nil {
+		return m.AppVersion
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetFilePath() string {
+	if m != nil {
+		return m.FilePath
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetFileContent() []byte {
+	if m != nil {
+		return m.FileContent
+	}
+	return nil
+}
+
+func (m *WriteFileRequest) GetDelete() bool {
+	if m != nil {
+		return m.Delete
+	}
+	return false
+}
+
+func (m *WriteFileRequest) GetAuthorName() string {
+	if m != nil {
+		return m.AuthorName
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetAuthorEmail() string {
+	if m != nil {
+		return m.AuthorEmail
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *WriteFileRequest) GetExpectedVersion() string {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return ""
+}
+
+type WriteFileResponse struct {
+	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteFileResponse) Reset()         { *m = WriteFileResponse{} }
+func (m *WriteFileResponse) String() string { return proto.CompactTextString(m) }
+func (*WriteFileResponse) ProtoMessage()    {}
+func (*WriteFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f4b068b76ab253eb, []int{9}
+}
+
+func (m *WriteFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteFileResponse.Unmarshal(m, b)
+}
+func (m *WriteFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteFileResponse.Marshal(b, m, deterministic)
+}
+func (m *WriteFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteFileResponse.Merge(m, src)
+}
+func (m *WriteFileResponse) XXX_Size() int {
+	return xxx_messageInfo_WriteFileResponse.Size(m)
+}
+func (m *WriteFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteFileResponse proto.InternalMessageInfo
+
+func (m *WriteFileResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
 type Application struct {
 	AppName              string   `protobuf:"bytes,1,opt,name=appName,proto3" json:"appName,omitempty"`
 	AppVersion           string   `protobuf:"bytes,2,opt,name=appVersion,proto3" json:"appVersion,omitempty"`
@@ -224,7 +634,7 @@ func (m *Application) Reset()         { *m = Application{} }
 func (m *Application) String() string { return proto.CompactTextString(m) }
 func (*Application) ProtoMessage()    {}
 func (*Application) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4b068b76ab253eb, []int{4}
+	return fileDescriptor_f4b068b76ab253eb, []int{10}
 }
 
 func (m *Application) XXX_Unmarshal(b []byte) error {
@@ -262,6 +672,8 @@ func (m *Application) GetAppVersion() string {
 type WatchRequest struct {
 	WatcherName          string       `protobuf:"bytes,1,opt,name=watcherName,proto3" json:"watcherName,omitempty"`
 	Application          *Application `protobuf:"bytes,2,opt,name=application,proto3" json:"application,omitempty"`
+	Environment          string       `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`
+	Environments         []string     `protobuf:"bytes,4,rep,name=environments,proto3" json:"environments,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -271,7 +683,7 @@ func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
 func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
 func (*WatchRequest) ProtoMessage()    {}
 func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4b068b76ab253eb, []int{5}
+	return fileDescriptor_f4b068b76ab253eb, []int{11}
 }
 
 func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
@@ -306,6 +718,20 @@ func (m *WatchRequest) GetApplication() *Application {
 	return nil
 }
 
+func (m *WatchRequest) GetEnvironment() string {
+	if m != nil {
+		return m.Environment
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetEnvironments() []string {
+	if m != nil {
+		return m.Environments
+	}
+	return nil
+}
+
 type WatchResponse struct {
 	Changed              bool     `protobuf:"varint,1,opt,name=changed,proto3" json:"changed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -317,7 +743,7 @@ func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
 func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
 func (*WatchResponse) ProtoMessage()    {}
 func (*WatchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f4b068b76ab253eb, []int{6}
+	return fileDescriptor_f4b068b76ab253eb, []int{12}
 }
 
 func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
@@ -348,8 +774,14 @@ func (m *WatchResponse) GetChanged() bool {
 func init() {
 	proto.RegisterType((*GetConfigRequest)(nil), "grpcapi.GetConfigRequest")
 	proto.RegisterType((*GetConfigResponse)(nil), "grpcapi.GetConfigResponse")
+	proto.RegisterType((*ExplainRequest)(nil), "grpcapi.ExplainRequest")
+	proto.RegisterType((*Origin)(nil), "grpcapi.Origin")
+	proto.RegisterType((*ExplainedKey)(nil), "grpcapi.ExplainedKey")
+	proto.RegisterType((*ExplainResponse)(nil), "grpcapi.ExplainResponse")
 	proto.RegisterType((*GetFileResponse)(nil), "grpcapi.GetFileResponse")
 	proto.RegisterType((*GetFileRequest)(nil), "grpcapi.GetFileRequest")
+	proto.RegisterType((*WriteFileRequest)(nil), "grpcapi.WriteFileRequest")
+	proto.RegisterType((*WriteFileResponse)(nil), "grpcapi.WriteFileResponse")
 	proto.RegisterType((*Application)(nil), "grpcapi.Application")
 	proto.RegisterType((*WatchRequest)(nil), "grpcapi.WatchRequest")
 	proto.RegisterType((*WatchResponse)(nil), "grpcapi.WatchResponse")
@@ -360,30 +792,53 @@ func init() {
 }
 
 var fileDescriptor_f4b068b76ab253eb = []byte{
-	// 362 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x4d, 0x4f, 0xe3, 0x30,
-	0x14, 0xdc, 0x6e, 0xb5, 0x9b, 0xf6, 0xa5, 0xdd, 0x05, 0x8b, 0x8f, 0x90, 0x03, 0x8a, 0x2c, 0x0e,
-	0x70, 0xa9, 0x50, 0x2b, 0x21, 0xc1, 0x01, 0x09, 0x15, 0xa8, 0xc4, 0x01, 0xa1, 0x54, 0x82, 0xb3,
-	0x09, 0xaf, 0x8d, 0xa5, 0xd6, 0x36, 0x89, 0x69, 0xc5, 0xbf, 0x47, 0x71, 0x1d, 0x63, 0x0a, 0x27,
-	0x38, 0xce, 0xf8, 0xcd, 0x8c, 0xfd, 0x26, 0x81, 0xce, 0x02, 0x33, 0x59, 0xbe, 0xf6, 0x54, 0x21,
-	0xb5, 0x24, 0xc1, 0xb4, 0x50, 0x19, 0x53, 0x9c, 0x0a, 0xd8, 0x18, 0xa1, 0x1e, 0x4a, 0x31, 0xe1,
-	0xd3, 0x14, 0x9f, 0x5f, 0xb0, 0xd4, 0x24, 0x82, 0x80, 0x29, 0x75, 0xcb, 0xe6, 0x18, 0x35, 0x92,
-	0xc6, 0x61, 0x3b, 0xad, 0x21, 0xd9, 0x07, 0x60, 0x4a, 0xdd, 0x63, 0x51, 0x72, 0x29, 0xa2, 0xdf,
-	0xe6, 0xd0, 0x63, 0x48, 0x02, 0x21, 0x8a, 0x05, 0x2f, 0xa4, 0x98, 0xa3, 0xd0, 0x51, 0xd3, 0x0c,
-	0xf8, 0x14, 0x3d, 0x85, 0x4d, 0x2f, 0xaf, 0x54, 0x52, 0x94, 0x48, 0x0e, 0xa0, 0x9b, 0x19, 0x66,
-	0x28, 0x85, 0xae, 0x84, 0xab, 0xd8, 0x8f, 0x24, 0x1d, 0xc0, 0xff, 0x11, 0xea, 0x6b, 0x3e, 0x43,
-	0x27, 0x4c, 0x20, 0x9c, 0xf0, 0x19, 0xfa, 0xb2, 0x4e, 0xea, 0x53, 0x74, 0x02, 0xff, 0x9c, 0xe8,
-	0xa7, 0xaf, 0x8b, 0xa1, 0x55, 0x59, 0xdf, 0x31, 0x9d, 0xdb, 0xa7, 0x39, 0x4c, 0x47, 0x10, 0x5e,
-	0x28, 0x35, 0xe3, 0x19, 0xd3, 0xd5, 0xe8, 0xb7, 0x43, 0x68, 0x0e, 0x9d, 0x07, 0xa6, 0xb3, 0xbc,
-	0xbe, 0x6e, 0x02, 0xe1, 0xb2, 0xc2, 0x58, 0x78, 0x6e, 0x3e, 0x45, 0x4e, 0x20, 0x64, 0xef, 0xd1,
-	0xc6, 0x32, 0xec, 0x6f, 0xf5, 0x6c, 0xc3, 0x3d, 0xef, 0x5a, 0xa9, 0x3f, 0x48, 0x8f, 0xa0, 0x6b,
-	0x93, 0xec, 0x36, 0x23, 0x08, 0xb2, 0x9c, 0x89, 0x29, 0x3e, 0x99, 0x98, 0x56, 0x5a, 0xc3, 0xfe,
-	0x18, 0xc2, 0xf1, 0x9c, 0x15, 0xb6, 0x37, 0x72, 0x09, 0x6d, 0x57, 0x22, 0xd9, 0x73, 0x49, 0xeb,
-	0x1f, 0x52, 0x1c, 0x7f, 0x75, 0xb4, 0x0a, 0xa3, 0xbf, 0xfa, 0x57, 0xd0, 0x4c, 0xd9, 0x92, 0x9c,
-	0x43, 0x60, 0x1b, 0x22, 0xbb, 0xfe, 0xbc, 0xd7, 0x59, 0x1c, 0x7d, 0x3e, 0x70, 0x36, 0x37, 0x76,
-	0x61, 0x63, 0x2c, 0x16, 0x3c, 0x43, 0x72, 0x06, 0x7f, 0x0c, 0x26, 0xdb, 0x4e, 0xe4, 0x2f, 0x34,
-	0xde, 0x59, 0xa7, 0x6b, 0xa7, 0xe3, 0xc6, 0xe3, 0x5f, 0xf3, 0x77, 0x0c, 0xde, 0x02, 0x00, 0x00,
-	0xff, 0xff, 0xdc, 0xd0, 0x83, 0xf6, 0x2d, 0x03, 0x00, 0x00,
+	// 722 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x41, 0x6f, 0xd3, 0x4a,
+	0x10, 0x7e, 0x6e, 0xd2, 0x24, 0x1e, 0xa7, 0x4d, 0xbb, 0xea, 0xeb, 0xf3, 0xb3, 0x9e, 0x9e, 0xa2,
+	0x15, 0x87, 0xf4, 0x40, 0x85, 0x52, 0x09, 0x09, 0x84, 0x90, 0x50, 0x5b, 0x2a, 0x40, 0x02, 0xe4,
+	0x4a, 0xed, 0x79, 0x6b, 0x4f, 0x93, 0xa5, 0x8e, 0xbd, 0xd8, 0xdb, 0xb4, 0x91, 0x38, 0x70, 0xe7,
+	0xc8, 0x1f, 0xe0, 0xc8, 0x89, 0x5f, 0xc4, 0x8f, 0x41, 0x5e, 0xaf, 0x9d, 0x8d, 0x13, 0x24, 0x44,
+	0xc5, 0xcd, 0xf3, 0xcd, 0xce, 0xec, 0x37, 0xdf, 0xcc, 0x8e, 0xa1, 0x3b, 0xc5, 0x20, 0xc9, 0x66,
+	0xfb, 0x22, 0x4d, 0x64, 0x42, 0xda, 0xa3, 0x54, 0x04, 0x4c, 0x70, 0xfa, 0xdd, 0x82, 0xad, 0x13,
+	0x94, 0x87, 0x49, 0x7c, 0xc9, 0x47, 0x3e, 0xbe, 0xbf, 0xc6, 0x4c, 0x12, 0x17, 0xda, 0x4c, 0x88,
+	0xd7, 0x6c, 0x82, 0xae, 0xd5, 0xb7, 0x06, 0xb6, 0x5f, 0x9a, 0xe4, 0x7f, 0x00, 0x26, 0xc4, 0x19,
+	0xa6, 0x19, 0x4f, 0x62, 0x77, 0x4d, 0x39, 0x0d, 0x84, 0xf4, 0xc1, 0xc1, 0x78, 0xca, 0xd3, 0x24,
+	0x9e, 0x60, 0x2c, 0xdd, 0x86, 0x3a, 0x60, 0x42, 0x64, 0x07, 0xd6, 0x23, 0x76, 0x81, 0x91, 0xdb,
+	0x54, 0xbe, 0xc2, 0x20, 0x43, 0xd8, 0x09, 0x79, 0xc6, 0x2e, 0x22, 0x7c, 0x11, 0x4b, 0x4c, 0x45,
+	0x12, 0x31, 0x99, 0xdf, 0xb0, 0xde, 0xb7, 0x06, 0x1d, 0x7f, 0xa5, 0x8f, 0x50, 0xe8, 0x1a, 0x89,
+	0x33, 0xb7, 0xd5, 0x6f, 0x0c, 0x6c, 0x7f, 0x01, 0xa3, 0x8f, 0x60, 0xdb, 0xa8, 0x2e, 0x13, 0x49,
+	0x9c, 0x21, 0xb9, 0x07, 0x1b, 0x81, 0x42, 0x0e, 0x93, 0x58, 0xe6, 0x34, 0x8b, 0x22, 0x17, 0x41,
+	0xfa, 0xc5, 0x82, 0xcd, 0xe3, 0x5b, 0x11, 0x31, 0x1e, 0xdf, 0x5d, 0x97, 0x3a, 0xd7, 0xc6, 0x32,
+	0xd7, 0x9f, 0x28, 0xe3, 0x41, 0x27, 0x93, 0x29, 0x93, 0x38, 0x9a, 0x29, 0x35, 0x6c, 0xbf, 0xb2,
+	0xe9, 0x47, 0x0b, 0x5a, 0x6f, 0x52, 0x3e, 0xe2, 0x31, 0xd9, 0x82, 0x06, 0x13, 0x42, 0xd3, 0xca,
+	0x3f, 0x09, 0x81, 0xe6, 0x25, 0x8f, 0x50, 0x93, 0x51, 0xdf, 0x79, 0x01, 0x53, 0xcd, 0xb1, 0x68,
+	0x4d, 0x69, 0x92, 0xff, 0xc0, 0x0e, 0x79, 0x8a, 0x81, 0xe4, 0x53, 0xd4, 0x04, 0xe6, 0x40, 0x1e,
+	0x17, 0x62, 0x84, 0x12, 0x43, 0xdd, 0x91, 0xd2, 0xa4, 0x1c, 0xba, 0x5a, 0x24, 0x0c, 0x5f, 0xe1,
+	0x2c, 0xe7, 0x71, 0x85, 0xb3, 0x92, 0xc7, 0x15, 0xce, 0xf2, 0xcc, 0xef, 0xb2, 0x24, 0x3e, 0x63,
+	0xd1, 0x75, 0x49, 0x66, 0x0e, 0x90, 0x3d, 0x68, 0x27, 0xaa, 0x82, 0x42, 0x13, 0x67, 0xd8, 0xdb,
+	0xd7, 0xa3, 0xb9, 0x5f, 0x54, 0xe6, 0x97, 0x7e, 0xfa, 0x04, 0x7a, 0x55, 0x3f, 0x74, 0x27, 0xf7,
+	0xa0, 0x79, 0x85, 0xb3, 0xcc, 0xb5, 0x54, 0xe8, 0xdf, 0x55, 0xa8, 0x49, 0xc9, 0x57, 0x47, 0xe8,
+	0x01, 0xf4, 0x4e, 0x50, 0x3e, 0xe7, 0x11, 0x56, 0xd1, 0x7d, 0x70, 0x72, 0x55, 0xcc, 0x29, 0xe8,
+	0xfa, 0x26, 0x44, 0x3f, 0xc0, 0x66, 0x15, 0x74, 0xd7, 0x11, 0xf0, 0xa0, 0x93, 0xa7, 0x7e, 0xcb,
+	0xe4, 0x58, 0x8b, 0x5f, 0xd9, 0xab, 0x5b, 0x4f, 0xbf, 0xae, 0xc1, 0xd6, 0x79, 0xca, 0x25, 0xfe,
+	0x79, 0x02, 0x35, 0x29, 0x9a, 0x4b, 0x52, 0x90, 0x5d, 0x68, 0x15, 0x3d, 0xd7, 0x13, 0xa0, 0x2d,
+	0x75, 0xeb, 0xb5, 0x1c, 0x27, 0xa9, 0xa2, 0xd4, 0xd2, 0xb7, 0x56, 0x48, 0x9e, 0xb9, 0xb0, 0x8e,
+	0x27, 0x8c, 0x47, 0x6e, 0xbb, 0xd8, 0x08, 0x06, 0x94, 0x57, 0x34, 0xc1, 0x2c, 0x63, 0x23, 0x74,
+	0x3b, 0x45, 0x45, 0xda, 0x24, 0x03, 0xe8, 0xe1, 0xad, 0xc0, 0x40, 0x62, 0x58, 0x96, 0x65, 0xab,
+	0x13, 0x75, 0x98, 0xde, 0x87, 0x6d, 0x43, 0x29, 0xdd, 0x5f, 0x63, 0xda, 0xad, 0x85, 0x69, 0xa7,
+	0x27, 0xe0, 0x3c, 0x13, 0x22, 0xe2, 0x41, 0xb1, 0x49, 0x7e, 0x5b, 0x53, 0xfa, 0xcd, 0x82, 0xee,
+	0x39, 0x93, 0xc1, 0xb8, 0x6c, 0x4f, 0x1f, 0x9c, 0x9b, 0xdc, 0xc6, 0xd4, 0x48, 0x67, 0x42, 0xe4,
+	0x21, 0x38, 0x6c, 0x7e, 0xb7, 0xca, 0xe9, 0x0c, 0x77, 0xaa, 0xd1, 0x35, 0x78, 0xf9, 0xe6, 0xc1,
+	0x5f, 0x58, 0xad, 0xf5, 0x25, 0xd3, 0x5c, 0xb1, 0x10, 0xf7, 0x60, 0x43, 0xf3, 0x9d, 0x8b, 0x14,
+	0x8c, 0x59, 0x3c, 0xc2, 0x50, 0x91, 0xed, 0xf8, 0xa5, 0x39, 0xfc, 0x6c, 0x81, 0x73, 0x3a, 0x61,
+	0xa9, 0x5e, 0x9f, 0xe4, 0x08, 0xec, 0x6a, 0x97, 0x92, 0x7f, 0x2b, 0xc2, 0xf5, 0xbf, 0x87, 0xe7,
+	0xad, 0x72, 0x15, 0xb7, 0xd1, 0xbf, 0xc8, 0x53, 0x68, 0xeb, 0xd7, 0x49, 0xfe, 0xa9, 0xbf, 0xd7,
+	0x32, 0x83, 0xbb, 0xec, 0x28, 0xe3, 0x87, 0x9f, 0x2c, 0x68, 0xf8, 0xec, 0x26, 0xcf, 0xa3, 0x9f,
+	0xa6, 0x91, 0x67, 0xf1, 0xb1, 0x7a, 0xee, 0xb2, 0xa3, 0xe2, 0x71, 0x04, 0x76, 0x35, 0x31, 0x46,
+	0x35, 0xf5, 0xf7, 0xe6, 0x79, 0xab, 0x5c, 0x15, 0x9b, 0x97, 0xba, 0xfd, 0xa7, 0x98, 0x4e, 0x79,
+	0x80, 0xe4, 0x31, 0xac, 0x2b, 0x9b, 0xcc, 0x77, 0x91, 0x39, 0x1e, 0xde, 0x6e, 0x1d, 0x2e, 0x33,
+	0x3d, 0xb0, 0x2e, 0x5a, 0xea, 0xd7, 0x7c, 0xf0, 0x63, 0x00, 0x18, 0x76, 0x29, 0x54, 0xaa, 0x07,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -399,6 +854,7 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type SmartConfigClient interface {
 	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
+	Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error)
 }
 
 type smartConfigClient struct {
@@ -418,9 +874,19 @@ func (c *smartConfigClient) GetConfig(ctx context.Context, in *GetConfigRequest,
 	return out, nil
 }
 
+func (c *smartConfigClient) Explain(ctx context.Context, in *ExplainRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	out := new(ExplainResponse)
+	err := c.cc.Invoke(ctx, "/grpcapi.SmartConfig/Explain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SmartConfigServer is the server API for SmartConfig service.
 type SmartConfigServer interface {
 	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
+	Explain(context.Context, *ExplainRequest) (*ExplainResponse, error)
 }
 
 // UnimplementedSmartConfigServer can be embedded to have forward compatible implementations.
@@ -430,6 +896,9 @@ type UnimplementedSmartConfigServer struct {
 func (*UnimplementedSmartConfigServer) GetConfig(ctx context.Context, req *GetConfigRequest) (*GetConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
 }
+func (*UnimplementedSmartConfigServer) Explain(ctx context.Context, req *ExplainRequest) (*ExplainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
 
 func RegisterSmartConfigServer(s *grpc.Server, srv SmartConfigServer) {
 	s.RegisterService(&_SmartConfig_serviceDesc, srv)
@@ -453,6 +922,24 @@ func _SmartConfig_GetConfig_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SmartConfig_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmartConfigServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.SmartConfig/Explain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmartConfigServer).Explain(ctx, req.(*ExplainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SmartConfig_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "grpcapi.SmartConfig",
 	HandlerType: (*SmartConfigServer)(nil),
@@ -461,6 +948,10 @@ var _SmartConfig_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfig",
 			Handler:    _SmartConfig_GetConfig_Handler,
 		},
+		{
+			MethodName: "Explain",
+			Handler:    _SmartConfig_Explain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "vecosy.proto",
@@ -471,6 +962,7 @@ var _SmartConfig_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type RawClient interface {
 	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error)
+	WriteFile(ctx context.Context, in *WriteFileRequest, opts ...grpc.CallOption) (*WriteFileResponse, error)
 }
 
 type rawClient struct {
@@ -490,9 +982,19 @@ func (c *rawClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grp
 	return out, nil
 }
 
+func (c *rawClient) WriteFile(ctx context.Context, in *WriteFileRequest, opts ...grpc.CallOption) (*WriteFileResponse, error) {
+	out := new(WriteFileResponse)
+	err := c.cc.Invoke(ctx, "/grpcapi.Raw/WriteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RawServer is the server API for Raw service.
 type RawServer interface {
 	GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error)
+	WriteFile(context.Context, *WriteFileRequest) (*WriteFileResponse, error)
 }
 
 // UnimplementedRawServer can be embedded to have forward compatible implementations.
@@ -502,6 +1004,9 @@ type UnimplementedRawServer struct {
 func (*UnimplementedRawServer) GetFile(ctx context.Context, req *GetFileRequest) (*GetFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
 }
+func (*UnimplementedRawServer) WriteFile(ctx context.Context, req *WriteFileRequest) (*WriteFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteFile not implemented")
+}
 
 func RegisterRawServer(s *grpc.Server, srv RawServer) {
 	s.RegisterService(&_Raw_serviceDesc, srv)
@@ -525,6 +1030,24 @@ func _Raw_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Raw_WriteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RawServer).WriteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Raw/WriteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RawServer).WriteFile(ctx, req.(*WriteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Raw_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "grpcapi.Raw",
 	HandlerType: (*RawServer)(nil),
@@ -533,6 +1056,10 @@ var _Raw_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetFile",
 			Handler:    _Raw_GetFile_Handler,
 		},
+		{
+			MethodName: "WriteFile",
+			Handler:    _Raw_WriteFile_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "vecosy.proto",
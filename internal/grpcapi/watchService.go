@@ -6,6 +6,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 )
 
 // Watch manage a GRPC watch request
@@ -34,25 +35,20 @@ func (s *Server) addWatcher(request *WatchRequest, stream WatchService_WatchServ
 		return err
 	}
 	watcher := &Watcher{
-		id:          uuid.New().String(),
-		watcherName: request.WatcherName,
-		appName:     request.Application.AppName,
-		appVersion:  appVer,
-		ch:          make(chan *WatchResponse),
+		id:           uuid.New().String(),
+		watcherName:  request.WatcherName,
+		appName:      request.Application.AppName,
+		appVersion:   appVer,
+		environments: requestEnvironments(request.Environment, request.Environments),
+		ch:           make(chan *WatchResponse),
+		done:         stream.Context().Done(),
 	}
 	s.watchers.Store(watcher.id, watcher)
-
-	s.repo.AddOnChangeHandler(func(application configrepo.ApplicationVersion) {
-		logrus.Infof("Changes detected on application:%+v", application)
-		watcherStreams, err := s.getWatcherStreamByApp(application)
-		if err != nil {
-			logrus.Errorf("Error getting watcher streams:%s", err)
-			return
-		}
-		for _, watcher := range watcherStreams {
-			watcher.ch <- &WatchResponse{Changed: true}
-		}
+	defer s.watchers.Delete(watcher.id)
+	s.watchersOnce.Do(func() {
+		s.repo.AddOnChangeHandler(s.notifyWatchers)
 	})
+
 	for {
 		select {
 		case resp := <-watcher.ch:
@@ -61,27 +57,79 @@ func (s *Server) addWatcher(request *WatchRequest, stream WatchService_WatchServ
 				logrus.Errorf("Error sending response:%s", err)
 				return err
 			}
-		case <-stream.Context().Done():
-			close(watcher.ch)
-			s.watchers.Delete(watcher.id)
+		case <-watcher.done:
 			logrus.Infof("watcher %+v removed", watcher)
 			return nil
 		}
 	}
 }
 
-func (s *Server) getWatcherStreamByApp(app configrepo.ApplicationVersion) ([]*Watcher, error) {
-	newVersion, err := version.NewVersion(app.AppVersion)
+// notifyWatchers notify the change to the watchers of the application versions and environments affected by it
+func (s *Server) notifyWatchers(change configrepo.Change) {
+	logrus.Infof("Changes detected on application:%+v kind:%s paths:%v", change.ApplicationVersion, change.Kind, change.ChangedPaths)
+	watcherStreams, err := s.getWatcherStreamByChange(change)
 	if err != nil {
-		logrus.Errorf("Error parsing the application version for version %s err:%s", app.AppVersion, err)
+		logrus.Errorf("Error getting watcher streams:%s", err)
+		return
+	}
+	for _, watcher := range watcherStreams {
+		select {
+		case watcher.ch <- &WatchResponse{Changed: true}:
+		case <-watcher.done:
+		}
+	}
+}
+
+func (s *Server) getWatcherStreamByChange(change configrepo.Change) ([]*Watcher, error) {
+	newVersion, err := version.NewVersion(change.AppVersion)
+	if err != nil {
+		logrus.Errorf("Error parsing the application version for version %s err:%s", change.AppVersion, err)
+		return nil, err
 	}
 	result := make([]*Watcher, 0)
 	s.watchers.Range(func(watcherId, value interface{}) bool {
 		watcher := value.(*Watcher)
-		if watcher.appName == app.AppName && watcher.appVersion.GreaterThanOrEqual(newVersion) {
+		if watcher.appName == change.AppName && watcher.appVersion.GreaterThanOrEqual(newVersion) && watcher.isAffectedBy(s.repo, change) {
 			result = append(result, watcher)
 		}
 		return true
 	})
 	return result, nil
 }
+
+// isAffectedBy returns true if the change affects at least one of the watcher environments (every change without environments),
+// the application layout decides which files affect an environment (see merger.AffectsEnvironment)
+func (w *Watcher) isAffectedBy(repo configrepo.Repo, change configrepo.Change) bool {
+	if len(w.environments) == 0 || changesSchema(change) {
+		return true
+	}
+	for _, environment := range w.environments {
+		if merger.AffectsEnvironment(repo, change, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// changesSchema returns true if the change contains a schema file (see validation.SchemaFiles), the schema validates every environment
+func changesSchema(change configrepo.Change) bool {
+	for _, changedPath := range change.ChangedPaths {
+		for _, schemaFile := range validation.SchemaFiles {
+			if changedPath == schemaFile {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestEnvironments returns the not empty environments of a request, the environment field is merged before the environments list ones
+func requestEnvironments(environment string, environments []string) []string {
+	result := make([]string, 0, len(environments)+1)
+	for _, env := range append([]string{environment}, environments...) {
+		if env != "" {
+			result = append(result, env)
+		}
+	}
+	return result
+}
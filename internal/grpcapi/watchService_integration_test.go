@@ -75,7 +75,7 @@ func TestServer_Watch_IT(t *testing.T) {
 
 	//simulate repo changes
 	assert.NotNil(t, onChangeHandlerCapture)
-	onChangeHandlerCapture(configrepo.ApplicationVersion{AppName: app.AppName, AppVersion: app.AppVersion})
+	onChangeHandlerCapture(configrepo.Change{ApplicationVersion: configrepo.ApplicationVersion{AppName: app.AppName, AppVersion: app.AppVersion}})
 
 	timeout.Reset(1 * time.Second)
 	select {
@@ -7,9 +7,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/internal/security"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/internal/testutil/repotest"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"google.golang.org/grpc/metadata"
 	"testing"
 	"time"
@@ -61,6 +63,67 @@ func TestServer_Watch(t *testing.T) {
 	}
 }
 
+func TestServer_Watch_Environment(t *testing.T) {
+	check := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(mockRepo)
+	srv, err := NewNoTLS(mockRepo, ":8080", false)
+	check.NoError(err)
+	onChangeCh := make(chan configrepo.OnChangeHandler, 1)
+	mockRepo.EXPECT().AddOnChangeHandler(gomock.Any()).Do(func(handler configrepo.OnChangeHandler) {
+		onChangeCh <- handler
+	})
+
+	request := &WatchRequest{
+		WatcherName:  "test",
+		Application:  &Application{AppName: "app", AppVersion: "1.0.0"},
+		Environment:  "int",
+		Environments: []string{"canary"},
+	}
+	sentCh := make(chan *WatchResponse, 1)
+	stream := NewMockWatchService_WatchServer(ctrl)
+	streamCtx, cancelFn := context.WithCancel(context.Background())
+	stream.EXPECT().Context().AnyTimes().Return(streamCtx)
+	stream.EXPECT().Send(gomock.Any()).Times(6).DoAndReturn(func(resp *WatchResponse) error {
+		sentCh <- resp
+		return nil
+	})
+	watchErrCh := make(chan error, 1)
+	go func() {
+		watchErrCh <- srv.Watch(request, stream)
+	}()
+	handler := <-onChangeCh
+
+	newChange := func(paths ...string) configrepo.Change {
+		return configrepo.Change{
+			ApplicationVersion: configrepo.ApplicationVersion{AppName: "app", AppVersion: "1.0.0"},
+			Kind:               configrepo.VersionUpdated,
+			ChangedPaths:       paths,
+		}
+	}
+	handler(newChange("dev/config.yml"))
+	check.Empty(sentCh)
+	handler(newChange("int/config.yml"))
+	check.True((<-sentCh).Changed)
+	handler(newChange("canary/config.yml"))
+	check.True((<-sentCh).Changed)
+	// the changes that can affect every environment are notified
+	handler(newChange("dev/config.yml", "config.yml"))
+	check.True((<-sentCh).Changed)
+	handler(newChange("dev/certs/ca.pem"))
+	check.True((<-sentCh).Changed)
+	handler(newChange(merger.ManifestFile))
+	check.True((<-sentCh).Changed)
+	handler(newChange("schema.json"))
+	check.True((<-sentCh).Changed)
+
+	cancelFn()
+	check.NoError(<-watchErrCh)
+}
+
 func TestServer_Watch_Unauthorized(t *testing.T) {
 	check := assert.New(t)
 	ctrl := gomock.NewController(t)
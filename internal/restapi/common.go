@@ -2,11 +2,12 @@ package restapi
 
 import (
 	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/utils"
 )
 
-const invalidFormatErrorMessage = "unsupported extension. Valid formats: [.yml,.json]"
+const invalidFormatErrorMessage = "unsupported extension. Valid formats: [.yml,.json,.properties]"
 
 func respondConfig(ctx iris.Context, finalConfig map[interface{}]interface{}, ext string, log *logrus.Entry) {
 	// converting and responding
@@ -23,6 +24,16 @@ func respondConfig(ctx iris.Context, finalConfig map[interface{}]interface{}, ex
 			return
 		}
 		_, err = ctx.JSON(normalizedMap)
+	case ".properties":
+		var normalizedMap map[string]interface{}
+		normalizedMap, err = utils.NormalizeMap(finalConfig)
+		if err != nil {
+			log.Errorf("Error normalizing properties map:%#+vs, err:%s", finalConfig, err)
+			internalServerError(ctx)
+			return
+		}
+		ctx.ContentType(context.ContentTextHeaderValue)
+		_, err = ctx.WriteString(utils.ToProperties(normalizedMap))
 	default:
 		badRequest(ctx, invalidFormatErrorMessage)
 		return
@@ -2,6 +2,7 @@ package restapi
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/kataras/iris/v12/context"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"net/http"
@@ -23,6 +24,10 @@ func Test_respondConfig(t *testing.T) {
 	ctx.EXPECT().JSON(gomock.Any()).Times(1)
 	respondConfig(ctx, config, ".json", log)
 
+	ctx.EXPECT().ContentType(context.ContentTextHeaderValue).Times(1)
+	ctx.EXPECT().WriteString("config: test\n").Times(1)
+	respondConfig(ctx, config, ".properties", log)
+
 	ctx.EXPECT().WriteString(invalidFormatErrorMessage).Times(1)
 	ctx.EXPECT().StatusCode(gomock.Eq(http.StatusBadRequest)).Times(1)
 	respondConfig(ctx, config, ".notValid", log)
@@ -0,0 +1,136 @@
+package restapi
+
+import (
+	"encoding/hex"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// invalidPropertyPrefix prefix of the property source keys that cannot be decrypted (like spring-cloud-config does)
+const invalidPropertyPrefix = "invalid."
+
+// SetEncryptor set the encryptor of the `{cipher}` values and of the encrypt/decrypt endpoints (nil to disable them)
+func (s *Server) SetEncryptor(encryptor encryption.Encryptor) {
+	s.encryptor = encryptor
+}
+
+func (s *Server) registerEncryptionEndpoints(parent router.Party) {
+	springParty := parent.Party("/spring")
+	springParty.Post("/encrypt", s.encrypt)
+	springParty.Post("/decrypt", s.decrypt)
+	springParty.Get("/encrypt/status", s.encryptionStatus)
+}
+
+// GET: /encrypt/status
+func (s *Server) encryptionStatus(ctx iris.Context) {
+	if !s.checkEncryptor(ctx) {
+		return
+	}
+	_, _ = ctx.JSON(iris.Map{"status": "OK"})
+}
+
+// POST: /encrypt
+func (s *Server) encrypt(ctx iris.Context) {
+	if !s.checkEncryptor(ctx) {
+		return
+	}
+	text, err := getEncryptionBody(ctx, false)
+	if err != nil {
+		badRequest(ctx, "cannot read the text to encrypt")
+		return
+	}
+	encrypted, err := s.encryptor.Encrypt(text)
+	if err != nil {
+		logrus.Errorf("Error encrypting:%s", err)
+		internalServerError(ctx)
+		return
+	}
+	ctx.ContentType(context.ContentTextHeaderValue)
+	_, _ = ctx.WriteString(encrypted)
+}
+
+// POST: /decrypt (admin token required)
+func (s *Server) decrypt(ctx iris.Context) {
+	if !s.checkEncryptor(ctx) {
+		return
+	}
+	err := s.CheckAdminToken(ctx)
+	if err != nil {
+		return
+	}
+	encrypted, err := getEncryptionBody(ctx, true)
+	if err != nil {
+		badRequest(ctx, "cannot read the text to decrypt")
+		return
+	}
+	text, err := encryption.DecryptValue(s.encryptor, encrypted)
+	if err != nil {
+		logrus.Errorf("Error decrypting:%s", err)
+		badRequest(ctx, "decryption failed")
+		return
+	}
+	ctx.ContentType(context.ContentTextHeaderValue)
+	_, _ = ctx.WriteString(text)
+}
+
+// checkEncryptor responds 404 if no key has been configured
+func (s *Server) checkEncryptor(ctx iris.Context) bool {
+	if s.encryptor == nil {
+		ctx.StatusCode(http.StatusNotFound)
+		_, _ = ctx.JSON(iris.Map{"status": "NO_KEY", "description": "No key was installed for encryption service"})
+		return false
+	}
+	return true
+}
+
+// getEncryptionBody returns the request body, the form data sent by `curl -d` (with a trailing `=`) are decoded like spring-cloud-config does
+func getEncryptionBody(ctx iris.Context, encrypted bool) (string, error) {
+	body, err := ctx.GetBody()
+	if err != nil {
+		return "", err
+	}
+	data := string(body)
+	if !strings.HasSuffix(data, "=") || strings.HasPrefix(ctx.GetHeader("Content-Type"), context.ContentTextHeaderValue) {
+		return data, nil
+	}
+	if decoded, err := url.QueryUnescape(data); err == nil {
+		data = decoded
+	}
+	candidate := data[:len(data)-1]
+	if !encrypted {
+		return candidate, nil
+	}
+	// the trailing `=` of a base64 (RSA) encrypted text is part of it
+	data = strings.ReplaceAll(data, " ", "+")
+	if _, err := hex.DecodeString(candidate); err == nil {
+		return candidate, nil
+	}
+	return data, nil
+}
+
+// decryptPropertySource decrypts the `{cipher}` values of a property source,
+// the values that cannot be decrypted are replaced by an `invalid.[key]` property
+func (s *Server) decryptPropertySource(source map[string]interface{}) {
+	if s.encryptor == nil {
+		return
+	}
+	for key, value := range source {
+		if !encryption.IsEncrypted(value) {
+			continue
+		}
+		text, err := encryption.DecryptValue(s.encryptor, value.(string))
+		if err != nil {
+			logrus.Warnf("Error decrypting the property %s:%s", key, err)
+			delete(source, key)
+			source[invalidPropertyPrefix+key] = "<n/a>"
+			continue
+		}
+		source[key] = text
+	}
+}
@@ -0,0 +1,86 @@
+package restapi
+
+import (
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestServer_Encryption(t *testing.T) {
+	adminKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	wrongKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	encryptor, err := encryption.NewSymmetricEncryptor("my-secret-key", "")
+	assert.NoError(t, err)
+	srv := New(memconfigrepo.NewMemConfigRepo(), "127.0.0.1:8080", true)
+	ht := httptest.New(t, srv.app)
+
+	// no key configured
+	ht.GET("/v1/spring/encrypt/status").Expect().Status(httptest.StatusNotFound).JSON().Object().ValueEqual("status", "NO_KEY")
+	ht.POST("/v1/spring/encrypt").WithText("secret").Expect().Status(httptest.StatusNotFound)
+
+	srv.SetEncryptor(encryptor)
+	srv.SetAdminPubKey(&adminKey.PublicKey)
+	ht.GET("/v1/spring/encrypt/status").Expect().Status(httptest.StatusOK).JSON().Object().ValueEqual("status", "OK")
+
+	encrypted := ht.POST("/v1/spring/encrypt").WithText("secret").Expect().Status(httptest.StatusOK).Body().Raw()
+	decrypted, err := encryptor.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+	// curl -d sends the text as form data
+	encrypted = ht.POST("/v1/spring/encrypt").WithBytes([]byte("secret=")).WithHeader("Content-Type", "application/x-www-form-urlencoded").
+		Expect().Status(httptest.StatusOK).Body().Raw()
+	decrypted, err = encryptor.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	// the decrypt endpoint requires an admin token
+	ht.POST("/v1/spring/decrypt").WithText(encrypted).Expect().Status(httptest.StatusUnauthorized)
+	ht.POST("/v1/spring/decrypt").WithText(encrypted).
+		WithHeader("Authorization", "Bearer "+testutil.GenJwsFromPrivateKey(t, wrongKey, "admin").FullSerialize()).
+		Expect().Status(httptest.StatusUnauthorized)
+	adminToken := "Bearer " + testutil.GenJwsFromPrivateKey(t, adminKey, "admin").FullSerialize()
+	ht.POST("/v1/spring/decrypt").WithText(encrypted).WithHeader("Authorization", adminToken).
+		Expect().Status(httptest.StatusOK).Body().Equal("secret")
+	ht.POST("/v1/spring/decrypt").WithText(encryption.CipherPrefix+encrypted).WithHeader("Authorization", adminToken).
+		Expect().Status(httptest.StatusOK).Body().Equal("secret")
+	ht.POST("/v1/spring/decrypt").WithText("wrong").WithHeader("Authorization", adminToken).
+		Expect().Status(httptest.StatusBadRequest)
+}
+
+func TestServer_CipherValues(t *testing.T) {
+	encryptor, err := encryption.NewSymmetricEncryptor("my-secret-key", "")
+	assert.NoError(t, err)
+	encrypted, err := encryptor.Encrypt("pwd")
+	assert.NoError(t, err)
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("db:\n  user: admin\n  password: '{cipher}"+encrypted+"'")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("db:\n  password: '{cipher}wrong'")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("db:\n  password: '{cipher}"+encrypted+"'")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.yml", []byte("db:\n  old: '{cipher}wrong'")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	// without a key the values are not decrypted
+	ht.GET("/v1/config/app1/1.0.0/int").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		JSON().Path("$.db.password").Equal(encryption.CipherPrefix + encrypted)
+
+	srv.SetEncryptor(encryptor)
+	ht.GET("/v1/config/app1/1.0.0/int").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		JSON().Path("$.db").Equal(map[string]interface{}{"user": "admin", "password": "pwd"})
+	ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusUnprocessableEntity).Body().Contains("db.password")
+	ht.GET("/v1/spring/1.0.0/app1-int.json").Expect().
+		JSON().Path("$.db").Equal(map[string]interface{}{"password": "pwd"})
+
+	// the property sources that cannot be decrypted are reported like spring-cloud-config does
+	res := ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.propertySources[0].source").Equal(map[string]interface{}{"invalid.db.old": "<n/a>"})
+	res.Path("$.propertySources[1].source").Equal(map[string]interface{}{"db.password": "pwd"})
+}
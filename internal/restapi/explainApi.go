@@ -0,0 +1,76 @@
+package restapi
+
+import (
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"strings"
+)
+
+type explainResponse struct {
+	Name     string             `json:"name"`
+	Version  string             `json:"version"`
+	Profiles []string           `json:"profiles"`
+	Label    *string            `json:"label"`
+	Strategy string             `json:"strategy"`
+	Keys     merger.Explanation `json:"keys"`
+}
+
+func (s *Server) registerExplainEndpoints(parent router.Party) {
+	parent.Get("/explain/{appName:string}/{appVersion:string}/{profiles:string}", s.explain)
+}
+
+// GET: /explain/{appName}/{appVersion}/{profiles}?strategy=[smart|spring|...] (the manifest strategy by default)
+func (s *Server) explain(ctx iris.Context) {
+	appName := ctx.Params().GetString("appName")
+	appVersion := ctx.Params().GetString("appVersion")
+	profiles := strings.Split(ctx.Params().GetString("profiles"), ",")
+	label := getLabel(ctx)
+	strategy := ctx.URLParam("strategy")
+	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("profiles", profiles)
+	log = log.WithField("label", label).WithField("strategy", strategy)
+	log.Info("explain")
+
+	app := configrepo.NewApplicationVersionAtLabel(appName, appVersion, label)
+	err := checkApplication(ctx, app, log)
+	if err != nil {
+		return
+	}
+
+	err = s.CheckToken(ctx, app)
+	if err != nil {
+		return
+	}
+
+	if strategy == "" {
+		strategy, err = merger.StrategyOf(s.repo, app)
+		if err != nil {
+			log.Errorf("Error reading the application strategy:%s", err)
+			repoErrorResponse(ctx, err)
+			return
+		}
+	}
+	explainer, err := merger.GetExplainer(strategy)
+	if err != nil {
+		log.Errorf("Error getting the explainer:%s", err)
+		badRequest(ctx, err.Error())
+		return
+	}
+	explanation, err := explainer.Explain(s.repo, app, profiles)
+	if err != nil {
+		log.Errorf("error explaining the configuration:%s", err)
+		repoErrorResponse(ctx, err)
+		return
+	}
+	response := explainResponse{Name: appName, Version: appVersion, Profiles: profiles, Strategy: strategy, Keys: explanation}
+	if label != "" {
+		response.Label = &label
+	}
+	_, err = ctx.JSON(response)
+	if err != nil {
+		log.Errorf("Error responding :%s", err)
+		internalServerError(ctx)
+	}
+}
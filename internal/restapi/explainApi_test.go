@@ -0,0 +1,47 @@
+package restapi
+
+import (
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestServer_Explain(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("db:\n  user: admin\n  port: 5432")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("db:\n  user: dev")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("server:\n  port: 8080")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.yml", []byte("server:\n  port: 9090")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+	version := func(filePath string) string {
+		file, err := repo.GetFile(configrepo.NewApplicationVersion("app1", "1.0.0"), filePath)
+		assert.NoError(t, err)
+		return file.Version
+	}
+
+	res := ht.GET("/v1/explain/app1/1.0.0/dev").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.strategy").Equal("smart")
+	keys := res.Path("$.keys").Object()
+	keys.Keys().ContainsOnly("db.user", "db.port")
+	keys.Value("db.user").Equal(map[string]interface{}{
+		"value": "dev",
+		"origins": []interface{}{
+			map[string]interface{}{"app": "app1/1.0.0", "file": "config.yml", "version": version("config.yml")},
+			map[string]interface{}{"app": "app1/1.0.0", "file": "dev/config.yml", "version": version("dev/config.yml")},
+		},
+	})
+	keys.Value("db.port").Path("$.origins").Array().Length().Equal(1)
+
+	res = ht.GET("/v1/explain/app1/1.0.0/dev").WithQuery("strategy", "spring").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.keys").Object().Keys().ContainsOnly("server.port")
+	serverPort := res.Path("$.keys").Object().Value("server.port")
+	serverPort.Path("$.value").Equal(9090)
+	serverPort.Path("$.origins[1].file").Equal("app1-dev.yml")
+
+	ht.GET("/v1/explain/app1/1.0.0/dev").WithQuery("strategy", "unknown").Expect().Status(httptest.StatusBadRequest)
+	ht.GET("/v1/explain/app1/wrong/dev").Expect().Status(httptest.StatusBadRequest)
+}
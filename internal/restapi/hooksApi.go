@@ -0,0 +1,178 @@
+package restapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/kataras/iris/v12"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// WebHookProviders list of the supported git providers
+var WebHookProviders = []string{"github", "gitlab", "gitea", "bitbucket"}
+
+// maxWebHookPayloadSize the max size of a push payload, the bigger ones are rejected before verifying their signature
+const maxWebHookPayloadSize = 5 << 20
+
+type pushPayload struct {
+	// github, gitlab, gitea
+	Ref string `json:"ref"`
+	// bitbucket cloud
+	Push struct {
+		Changes []struct {
+			New *bitbucketRef `json:"new"`
+			Old *bitbucketRef `json:"old"`
+		} `json:"changes"`
+	} `json:"push"`
+	// bitbucket server
+	Changes []struct {
+		Ref struct {
+			ID string `json:"id"`
+		} `json:"ref"`
+	} `json:"changes"`
+}
+
+type bitbucketRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+func (r *bitbucketRef) refName() string {
+	if r.Type == "tag" {
+		return "refs/tags/" + r.Name
+	}
+	return "refs/heads/" + r.Name
+}
+
+// refs returns the references updated by the push
+func (p *pushPayload) refs() []string {
+	result := make([]string, 0)
+	if p.Ref != "" {
+		result = append(result, p.Ref)
+	}
+	for _, change := range p.Push.Changes {
+		for _, ref := range []*bitbucketRef{change.New, change.Old} {
+			if ref != nil && ref.Name != "" {
+				result = append(result, ref.refName())
+			}
+		}
+	}
+	for _, change := range p.Changes {
+		if change.Ref.ID != "" {
+			result = append(result, change.Ref.ID)
+		}
+	}
+	return result
+}
+
+// SetWebHookSecret enable the push web hook of a git provider (github, gitlab, gitea, bitbucket)
+func (s *Server) SetWebHookSecret(provider, secret string) {
+	if s.webHookSecrets == nil {
+		s.webHookSecrets = make(map[string]string)
+	}
+	s.webHookSecrets[provider] = secret
+}
+
+func (s *Server) registerHooksEndpoints(parent iris.Party) {
+	hooksAPI := parent.Party("/hooks")
+	hooksAPI.Post("/{provider:string}", s.onPush)
+}
+
+// POST:/hooks/{provider}
+func (s *Server) onPush(ctx iris.Context) {
+	provider := ctx.Params().GetString("provider")
+	log := logrus.WithField("method", "onPush").WithField("provider", provider)
+	log.Info("onPush")
+	secret, enabled := s.webHookSecrets[provider]
+	if !enabled {
+		log.Warnf("web hook not enabled for provider:%s", provider)
+		notFoundResponse(ctx)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(ctx.ResponseWriter(), ctx.Request().Body, maxWebHookPayloadSize))
+	if err != nil {
+		log.Errorf("Error reading the body:%s", err)
+		ctx.StatusCode(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !verifyWebHook(ctx, provider, secret, body) {
+		log.Errorf("invalid web hook signature")
+		unAuthorizedResponse(ctx)
+		return
+	}
+
+	payload := &pushPayload{}
+	err = json.Unmarshal(body, payload)
+	if err != nil {
+		log.Errorf("Error parsing the payload:%s", err)
+		badRequest(ctx, "invalid payload")
+		return
+	}
+	matchedRefs := s.matchReferences(payload.refs())
+	if len(matchedRefs) == 0 {
+		log.Infof("no application references found on %+v, push ignored", payload.refs())
+		ctx.StatusCode(http.StatusAccepted)
+		return
+	}
+	log.Infof("fetching for the references:%+v", matchedRefs)
+	err = s.repo.Fetch()
+	if err != nil {
+		log.Errorf("Error fetching the repo:%s", err)
+		internalServerError(ctx)
+		return
+	}
+	_, _ = ctx.JSON(matchedRefs)
+}
+
+// matchReferences returns the references that follow the repo naming convention
+func (s *Server) matchReferences(refs []string) []string {
+	matcher, ok := s.repo.(configrepo.ReferenceMatcher)
+	if !ok {
+		return refs
+	}
+	result := make([]string, 0)
+	for _, ref := range refs {
+		if matcher.MatchReference(ref) {
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+func verifyWebHook(ctx iris.Context, provider, secret string, body []byte) bool {
+	switch provider {
+	case "github", "bitbucket":
+		if signature := ctx.GetHeader("X-Hub-Signature-256"); signature != "" {
+			return verifyHMAC(sha256.New, secret, body, strings.TrimPrefix(signature, "sha256="))
+		}
+		signature := ctx.GetHeader("X-Hub-Signature")
+		if strings.HasPrefix(signature, "sha256=") {
+			return verifyHMAC(sha256.New, secret, body, strings.TrimPrefix(signature, "sha256="))
+		}
+		return strings.HasPrefix(signature, "sha1=") && verifyHMAC(sha1.New, secret, body, strings.TrimPrefix(signature, "sha1="))
+	case "gitea":
+		return verifyHMAC(sha256.New, secret, body, ctx.GetHeader("X-Gitea-Signature"))
+	case "gitlab":
+		return subtle.ConstantTimeCompare([]byte(ctx.GetHeader("X-Gitlab-Token")), []byte(secret)) == 1
+	default:
+		return false
+	}
+}
+
+func verifyHMAC(hashFn func() hash.Hash, secret string, body []byte, hexSignature string) bool {
+	signature, err := hex.DecodeString(hexSignature)
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+	mac := hmac.New(hashFn, []byte(secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
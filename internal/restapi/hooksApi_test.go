@@ -0,0 +1,106 @@
+package restapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/vecosy/vecosy/v2/mocks"
+	"hash"
+	"regexp"
+	"testing"
+)
+
+var testRefRe = regexp.MustCompile(`^refs/(heads|tags)/app1/[0-9.]+$`)
+
+type matcherRepo struct {
+	*mocks.MockRepo
+}
+
+func (r *matcherRepo) MatchReference(refName string) bool {
+	return testRefRe.MatchString(refName)
+}
+
+func signWebHookPayload(hashFn func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(hashFn, []byte(secret))
+	_, _ = mac.Write(body)
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
+
+func TestServer_OnPush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := &matcherRepo{mocks.NewMockRepo(ctrl)}
+	secret := "mySecret"
+	srv := New(repo, "127.0.0.1:8080", true)
+	for _, provider := range WebHookProviders {
+		srv.SetWebHookSecret(provider, secret)
+	}
+	ht := httptest.New(t, srv.app)
+
+	githubPayload := []byte(`{"ref":"refs/heads/app1/1.0.0"}`)
+	bitbucketPayload := []byte(`{"push":{"changes":[{"new":{"type":"tag","name":"app1/1.0.0"},"old":null}]}}`)
+	bitbucketServerPayload := []byte(`{"changes":[{"ref":{"id":"refs/heads/app1/1.0.0"}}]}`)
+	tests := []struct {
+		name     string
+		provider string
+		payload  []byte
+		header   string
+		value    string
+	}{
+		{"github sha256", "github", githubPayload, "X-Hub-Signature-256", "sha256=" + signWebHookPayload(sha256.New, secret, githubPayload)},
+		{"github sha1", "github", githubPayload, "X-Hub-Signature", "sha1=" + signWebHookPayload(sha1.New, secret, githubPayload)},
+		{"gitea", "gitea", githubPayload, "X-Gitea-Signature", signWebHookPayload(sha256.New, secret, githubPayload)},
+		{"gitlab", "gitlab", githubPayload, "X-Gitlab-Token", secret},
+		{"bitbucket cloud", "bitbucket", bitbucketPayload, "X-Hub-Signature", "sha256=" + signWebHookPayload(sha256.New, secret, bitbucketPayload)},
+		{"bitbucket server", "bitbucket", bitbucketServerPayload, "X-Hub-Signature", "sha256=" + signWebHookPayload(sha256.New, secret, bitbucketServerPayload)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo.EXPECT().Fetch().Return(nil)
+			res := ht.POST("/v1/hooks/{provider}").WithPath("provider", test.provider).
+				WithHeader(test.header, test.value).WithBytes(test.payload).Expect()
+			res.Status(httptest.StatusOK)
+			res.JSON().Array().Length().Equal(1)
+		})
+	}
+}
+
+func TestServer_OnPush_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := &matcherRepo{mocks.NewMockRepo(ctrl)}
+	secret := "mySecret"
+	srv := New(repo, "127.0.0.1:8080", true)
+	srv.SetWebHookSecret("github", secret)
+	srv.SetWebHookSecret("gitlab", secret)
+	ht := httptest.New(t, srv.app)
+	payload := []byte(`{"ref":"refs/heads/app1/1.0.0"}`)
+
+	t.Run("wrong signature", func(t *testing.T) {
+		ht.POST("/v1/hooks/github").WithHeader("X-Hub-Signature-256", "sha256="+signWebHookPayload(sha256.New, "wrongSecret", payload)).
+			WithBytes(payload).Expect().Status(httptest.StatusUnauthorized)
+		ht.POST("/v1/hooks/github").WithBytes(payload).Expect().Status(httptest.StatusUnauthorized)
+		ht.POST("/v1/hooks/gitlab").WithHeader("X-Gitlab-Token", "wrongSecret").
+			WithBytes(payload).Expect().Status(httptest.StatusUnauthorized)
+	})
+
+	t.Run("payload too large", func(t *testing.T) {
+		largePayload := make([]byte, maxWebHookPayloadSize+1)
+		ht.POST("/v1/hooks/gitlab").WithHeader("X-Gitlab-Token", secret).
+			WithBytes(largePayload).Expect().Status(httptest.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("provider not enabled", func(t *testing.T) {
+		ht.POST("/v1/hooks/gitea").WithHeader("X-Gitea-Signature", signWebHookPayload(sha256.New, secret, payload)).
+			WithBytes(payload).Expect().Status(httptest.StatusNotFound)
+	})
+
+	t.Run("not matching reference", func(t *testing.T) {
+		masterPayload := []byte(`{"ref":"refs/heads/master"}`)
+		ht.POST("/v1/hooks/gitlab").WithHeader("X-Gitlab-Token", secret).
+			WithBytes(masterPayload).Expect().Status(httptest.StatusAccepted)
+	})
+}
@@ -2,9 +2,12 @@ package restapi
 
 import (
 	"context"
+	"crypto/rsa"
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/middleware/logger"
 	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"mime"
 )
@@ -15,11 +18,15 @@ type Server struct {
 	app             *iris.Application
 	address         string
 	securityEnabled bool
+	webHookSecrets  map[string]string
+	adminPubKey     *rsa.PublicKey
+	encryptor       encryption.Encryptor
+	schemaValidator *validation.SchemaValidator
 }
 
 // New instantiate a REST server
 func New(repo configrepo.Repo, address string, securityEnabled bool) *Server {
-	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled}
+	s := &Server{repo: repo, address: address, securityEnabled: securityEnabled, schemaValidator: validation.NewSchemaValidator(false)}
 	log := logrus.WithField("address", address).WithField("securityEnabled", securityEnabled)
 	log.Info("Rest server created")
 	app := iris.New()
@@ -54,6 +61,11 @@ func (s *Server) Stop() {
 	}
 }
 
+// SetSchemaValidator set the validator of the served configurations (see validation.SchemaValidator)
+func (s *Server) SetSchemaValidator(validator *validation.SchemaValidator) {
+	s.schemaValidator = validator
+}
+
 // IsSecurityEnabled returns if the security is enabled or not
 func (s *Server) IsSecurityEnabled() bool {
 	return s.securityEnabled
@@ -71,6 +83,9 @@ func (s *Server) initV1Api() {
 	s.registerRawEndpoints(v1Api)
 	s.registerSmartConfigEndpoints(v1Api)
 	s.registerSpringCloudEndpoints(v1Api)
+	s.registerExplainEndpoints(v1Api)
+	s.registerEncryptionEndpoints(v1Api)
+	s.registerHooksEndpoints(v1Api)
 }
 
 func init() {
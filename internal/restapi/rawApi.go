@@ -8,21 +8,27 @@ import (
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"mime"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 func (s *Server) registerRawEndpoints(parent iris.Party) {
 	configAPI := parent.Party("/raw")
 	configAPI.Get("/{appName:string}/{appVersion:string}/{filePath:path}", s.getFile)
+	configAPI.Put("/{appName:string}/{appVersion:string}/{filePath:path}", s.putFile)
+	configAPI.Delete("/{appName:string}/{appVersion:string}/{filePath:path}", s.deleteFile)
 }
 
 func (s *Server) getFile(ctx iris.Context) {
 	appName := ctx.Params().Get("appName")
 	appVersion := ctx.Params().Get("appVersion")
 	filePath := ctx.Params().Get("filePath")
+	label := getLabel(ctx)
 	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("filePath", filePath)
+	log = log.WithField("label", label)
 	log.Infof("GetFile")
 
-	app := configrepo.NewApplicationVersion(appName, appVersion)
+	app := configrepo.NewApplicationVersionAtLabel(appName, appVersion, label)
 	err := checkApplication(ctx, app, log)
 	if err != nil {
 		return
@@ -33,14 +39,10 @@ func (s *Server) getFile(ctx iris.Context) {
 		return
 	}
 
-	file, err := s.repo.GetFile(app, filePath)
+	file, err := configrepo.GetFileOrShared(s.repo, app, filePath)
 	if err != nil {
 		log.Errorf("error getting file err:%s", err)
-		if errors.Is(err, configrepo.ErrFileNotFound) {
-			notFoundResponse(ctx)
-		} else {
-			internalServerError(ctx)
-		}
+		repoErrorResponse(ctx, err)
 		return
 	}
 	var mimeType string
@@ -53,6 +55,80 @@ func (s *Server) getFile(ctx iris.Context) {
 		mimeType = mime.TypeByExtension(filepath.Ext(filePath))
 	}
 	log.Debugf("Detected fileType:%s", mimeType)
+	ctx.Header("ETag", strconv.Quote(file.Version))
 	ctx.ContentType(mimeType)
 	_, _ = ctx.Write(file.Content)
 }
+
+func (s *Server) putFile(ctx iris.Context) {
+	content, err := ctx.GetBody()
+	if err != nil {
+		badRequest(ctx, "cannot read the file content")
+		return
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	s.writeFile(ctx, content)
+}
+
+func (s *Server) deleteFile(ctx iris.Context) {
+	s.writeFile(ctx, nil)
+}
+
+// writeFile commit the file content (nil to delete the file) to the application version
+//
+// the author and the commit message are mandatory (X-Author-Name, X-Author-Email and X-Commit-Message headers),
+// the If-Match header (optional) should contain the version (ETag) the change is based on
+func (s *Server) writeFile(ctx iris.Context, content []byte) {
+	appName := ctx.Params().Get("appName")
+	appVersion := ctx.Params().Get("appVersion")
+	filePath := ctx.Params().Get("filePath")
+	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("filePath", filePath)
+	log.Infof("WriteFile delete:%v", content == nil)
+
+	app := configrepo.NewApplicationVersion(appName, appVersion)
+	err := checkApplication(ctx, app, log)
+	if err != nil {
+		return
+	}
+
+	err = s.CheckAdminToken(ctx)
+	if err != nil {
+		return
+	}
+
+	writer, isWriter := s.repo.(configrepo.Writer)
+	if !isWriter {
+		repoErrorResponse(ctx, configrepo.ErrWriteNotSupported)
+		return
+	}
+	opts := &configrepo.WriteOptions{
+		AuthorName:      ctx.GetHeader("X-Author-Name"),
+		AuthorEmail:     ctx.GetHeader("X-Author-Email"),
+		Message:         ctx.GetHeader("X-Commit-Message"),
+		ExpectedVersion: strings.Trim(ctx.GetHeader("If-Match"), `"`),
+	}
+	if opts.AuthorName == "" || opts.AuthorEmail == "" || opts.Message == "" {
+		badRequest(ctx, "the X-Author-Name, X-Author-Email and X-Commit-Message headers are mandatory")
+		return
+	}
+
+	var newVersion string
+	if content == nil {
+		newVersion, err = writer.DeleteFile(app, filePath, opts)
+	} else {
+		newVersion, err = writer.WriteFile(app, filePath, content, opts)
+	}
+	if err != nil {
+		log.Errorf("error writing file err:%s", err)
+		if errors.Is(err, configrepo.ErrApplicationNotFound) || errors.Is(err, configrepo.ErrVersionNotFound) {
+			notFoundResponse(ctx)
+			return
+		}
+		repoErrorResponse(ctx, err)
+		return
+	}
+	ctx.Header("ETag", strconv.Quote(newVersion))
+	_, _ = ctx.JSON(iris.Map{"version": newVersion})
+}
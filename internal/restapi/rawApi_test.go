@@ -1,7 +1,9 @@
 package restapi
 
 import (
+	"crypto/rsa"
 	"fmt"
+	"github.com/gavv/httpexpect"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/kataras/iris/v12/httptest"
@@ -11,6 +13,7 @@ import (
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"gopkg.in/yaml.v2"
+	"strconv"
 	"testing"
 )
 
@@ -88,3 +91,147 @@ func TestServer_GetFile(t *testing.T) {
 		}
 	}
 }
+
+func TestServer_GetFile_Label(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mocks.NewMockRepo(ctrl)
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	label := uuid.New().String()
+	app := configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", label)
+	content := []byte(uuid.New().String())
+	repo.EXPECT().GetFile(app, "config.yml").Return(&configrepo.RepoFile{Version: label, Content: content}, nil)
+	ht.GET("/v1/raw/app1/v1.0.0/config.yml").WithQuery("label", label).Expect().Body().Equal(string(content))
+
+	repo.EXPECT().GetFile(app, "config.yml").Return(nil, configrepo.ErrLabelNotFound)
+	ht.GET("/v1/raw/app1/v1.0.0/config.yml").WithQuery("label", label).Expect().Status(httptest.StatusNotFound)
+
+	repo.EXPECT().GetFile(app, "config.yml").Return(nil, configrepo.ErrLabelNotSupported)
+	ht.GET("/v1/raw/app1/v1.0.0/config.yml").WithQuery("label", label).Expect().Status(httptest.StatusBadRequest)
+}
+
+type sharedRepo struct {
+	*mocks.MockRepo
+	*mocks.MockSharedRepo
+}
+
+func TestServer_GetFile_Shared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := &sharedRepo{mocks.NewMockRepo(ctrl), mocks.NewMockSharedRepo(ctrl)}
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+	appContent := []byte(uuid.New().String())
+	repo.MockRepo.EXPECT().GetFile(app, "config.yml").Return(&configrepo.RepoFile{Version: "app", Content: appContent}, nil)
+	ht.GET("/v1/raw/app1/v1.0.0/config.yml").Expect().Body().Equal(string(appContent))
+
+	sharedContent := []byte(uuid.New().String())
+	repo.MockRepo.EXPECT().GetFile(app, "shared.yml").Return(nil, configrepo.ErrFileNotFound)
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "shared.yml").Return(&configrepo.RepoFile{Version: "shared", Content: sharedContent}, nil)
+	res := ht.GET("/v1/raw/app1/v1.0.0/shared.yml").Expect()
+	res.Body().Equal(string(sharedContent))
+	res.Header("ETag").Equal(strconv.Quote("shared"))
+
+	repo.MockRepo.EXPECT().GetFile(app, "notExisting.yml").Return(nil, configrepo.ErrFileNotFound)
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "notExisting.yml").Return(nil, configrepo.ErrFileNotFound)
+	ht.GET("/v1/raw/app1/v1.0.0/notExisting.yml").Expect().Status(httptest.StatusNotFound)
+
+	// only the missing files fall back to the shared branch
+	repo.MockRepo.EXPECT().GetFile(app, "config.yml").Return(nil, configrepo.ErrApplicationNotFound)
+	ht.GET("/v1/raw/app1/v1.0.0/config.yml").Expect()
+}
+
+type writerRepo struct {
+	*mocks.MockRepo
+	*mocks.MockWriter
+}
+
+func TestServer_WriteFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	adminKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	wrongKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	repo := &writerRepo{mocks.NewMockRepo(ctrl), mocks.NewMockWriter(ctrl)}
+	srv := New(repo, "127.0.0.1:8080", true)
+	srv.SetAdminPubKey(&adminKey.PublicKey)
+	ht := httptest.New(t, srv.app)
+
+	app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+	content := []byte(uuid.New().String())
+	currentVersion := uuid.New().String()
+	newVersion := uuid.New().String()
+	opts := &configrepo.WriteOptions{AuthorName: "deploy bot", AuthorEmail: "bot@vecosy.io", Message: "updated", ExpectedVersion: currentVersion}
+	newRequest := func(method string, key *rsa.PrivateKey) *httpexpect.Request {
+		return ht.Request(method, "/v1/raw/app1/v1.0.0/config.yml").
+			WithHeader("Authorization", "Bearer "+testutil.GenJwsFromPrivateKey(t, key, "admin").FullSerialize()).
+			WithHeader("X-Author-Name", opts.AuthorName).
+			WithHeader("X-Author-Email", opts.AuthorEmail).
+			WithHeader("X-Commit-Message", opts.Message).
+			WithHeader("If-Match", strconv.Quote(currentVersion))
+	}
+
+	repo.MockWriter.EXPECT().WriteFile(app, "config.yml", content, opts).Return(newVersion, nil)
+	res := newRequest("PUT", adminKey).WithBytes(content).Expect().Status(httptest.StatusOK)
+	res.Header("ETag").Equal(strconv.Quote(newVersion))
+	res.JSON().Object().ValueEqual("version", newVersion)
+
+	repo.MockWriter.EXPECT().DeleteFile(app, "config.yml", opts).Return(newVersion, nil)
+	newRequest("DELETE", adminKey).Expect().Status(httptest.StatusOK)
+
+	repo.MockWriter.EXPECT().WriteFile(app, "config.yml", content, opts).Return("", configrepo.ErrConflict)
+	newRequest("PUT", adminKey).WithBytes(content).Expect().Status(httptest.StatusConflict)
+
+	repo.MockWriter.EXPECT().DeleteFile(app, "config.yml", opts).Return("", configrepo.ErrFileNotFound)
+	newRequest("DELETE", adminKey).Expect().Status(httptest.StatusNotFound)
+
+	repo.MockWriter.EXPECT().DeleteFile(app, "config.yml", opts).Return("", configrepo.ErrVersionNotFound)
+	newRequest("DELETE", adminKey).Expect().Status(httptest.StatusNotFound)
+
+	newRequest("PUT", wrongKey).WithBytes(content).Expect().Status(httptest.StatusUnauthorized)
+	ht.PUT("/v1/raw/app1/v1.0.0/config.yml").
+		WithHeader("Authorization", "Bearer "+testutil.GenJwsFromPrivateKey(t, adminKey, "admin").FullSerialize()).
+		WithBytes(content).
+		Expect().Status(httptest.StatusBadRequest)
+}
+
+func TestServer_WriteFile_NotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	adminKey, _, err := testutil.GenerateKeyPair()
+	assert.NoError(t, err)
+	srv := New(mocks.NewMockRepo(ctrl), "127.0.0.1:8080", false)
+	srv.SetAdminPubKey(&adminKey.PublicKey)
+	ht := httptest.New(t, srv.app)
+	ht.PUT("/v1/raw/app1/v1.0.0/config.yml").
+		WithHeader("Authorization", "Bearer "+testutil.GenJwsFromPrivateKey(t, adminKey, "admin").FullSerialize()).
+		WithHeader("X-Author-Name", "deploy bot").
+		WithHeader("X-Author-Email", "bot@vecosy.io").
+		WithHeader("X-Commit-Message", "updated").
+		WithBytes([]byte("prop: value")).
+		Expect().Status(httptest.StatusMethodNotAllowed)
+}
+
+func TestServer_WriteFile_NoAdminKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	// the writes are rejected even if the security is disabled
+	srv := New(&writerRepo{mocks.NewMockRepo(ctrl), mocks.NewMockWriter(ctrl)}, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+	ht.PUT("/v1/raw/app1/v1.0.0/config.yml").
+		WithHeader("X-Author-Name", "deploy bot").
+		WithHeader("X-Author-Email", "bot@vecosy.io").
+		WithHeader("X-Commit-Message", "updated").
+		WithBytes([]byte("prop: value")).
+		Expect().Status(httptest.StatusUnauthorized)
+	ht.DELETE("/v1/raw/app1/v1.0.0/config.yml").
+		WithHeader("X-Author-Name", "deploy bot").
+		WithHeader("X-Author-Email", "bot@vecosy.io").
+		WithHeader("X-Commit-Message", "updated").
+		Expect().Status(httptest.StatusUnauthorized)
+}
@@ -1,6 +1,7 @@
 package restapi
 
 import (
+	"crypto/rsa"
 	"github.com/kataras/iris/v12"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/security"
@@ -12,10 +13,36 @@ import (
 //
 // http headers: Authorization and X-Config-Token
 func (s *Server) CheckToken(ctx iris.Context, app *configrepo.ApplicationVersion) error {
-	log := logrus.WithField("method", "CheckToken")
 	if !s.IsSecurityEnabled() {
 		return nil
 	}
+	err := security.CheckJwtToken(s.repo, app, getToken(ctx))
+	if err != nil {
+		unAuthorizedResponse(ctx)
+		return err
+	}
+	return nil
+}
+
+// CheckAdminToken check if a valid admin token (signed with the admin private key) is present on the request
+//
+// the admin token is checked even if the security is disabled, the request is rejected if no admin key is configured
+func (s *Server) CheckAdminToken(ctx iris.Context) error {
+	err := security.CheckAdminToken(s.adminPubKey, getToken(ctx))
+	if err != nil {
+		unAuthorizedResponse(ctx)
+		return err
+	}
+	return nil
+}
+
+// SetAdminPubKey set the public key used to check the admin tokens
+func (s *Server) SetAdminPubKey(pubKey *rsa.PublicKey) {
+	s.adminPubKey = pubKey
+}
+
+func getToken(ctx iris.Context) string {
+	log := logrus.WithField("method", "getToken")
 	var token string
 	authorizationHeader := ctx.GetHeader("Authorization")
 	if authorizationHeader == "" {
@@ -24,11 +51,5 @@ func (s *Server) CheckToken(ctx iris.Context, app *configrepo.ApplicationVersion
 		token = strings.Replace(authorizationHeader, "Bearer ", "", 1)
 	}
 	log.Debugf("checking token:%s", token)
-
-	err := security.CheckJwtToken(s.repo, app, token)
-	if err != nil {
-		unAuthorizedResponse(ctx)
-		return err
-	}
-	return nil
+	return token
 }
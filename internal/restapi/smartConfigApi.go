@@ -4,28 +4,32 @@ import (
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/context"
 	"github.com/sirupsen/logrus"
-	"github.com/vecosy/vecosy/v2/internal/merger"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"strings"
 )
 
-var smartConfigFileMerger = merger.SmartConfigMerger{}
+// configFileMerger merge the configuration following the strategy declared by the application manifest
+var configFileMerger = merger.ManifestMerger{}
 
 func (s *Server) registerSmartConfigEndpoints(parent iris.Party) {
 	configAPI := parent.Party("/config")
 	configAPI.Get("/", s.info)
-	configAPI.Get("/{appName:string}/{appVersion:string}/{profile:string}", s.getSmartConfig)
+	configAPI.Get("/{appName:string}/{appVersion:string}/{profiles:string}", s.getSmartConfig)
 }
 
 func (s *Server) getSmartConfig(ctx iris.Context) {
 	appName := ctx.Params().GetString("appName")
 	appVersion := ctx.Params().GetString("appVersion")
-	profile := ctx.Params().GetString("profile")
+	// comma separated profiles merged in order (i.e. prod,eu-west,canary)
+	profiles := strings.Split(ctx.Params().GetString("profiles"), ",")
+	label := getLabel(ctx)
 	requestedTypes := getAccepts(ctx)
-	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("profile", profile)
-	log = log.WithField("requested types", requestedTypes)
+	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("profiles", profiles)
+	log = log.WithField("label", label).WithField("requested types", requestedTypes)
 	log.Info("GetSmartConfig")
 
-	app := configrepo.NewApplicationVersion(appName, appVersion)
+	app := configrepo.NewApplicationVersionAtLabel(appName, appVersion, label)
 	err := checkApplication(ctx, app, log)
 	if err != nil {
 		return
@@ -49,10 +53,16 @@ func (s *Server) getSmartConfig(ctx iris.Context) {
 		badRequest(ctx, "invalid request type, only json,yaml are supported")
 		return
 	}
-	finalConfig, err := smartConfigFileMerger.Merge(s.repo, app, []string{profile})
+	finalConfig, err := configFileMerger.Merge(s.repo, app, profiles)
 	if err != nil {
 		log.Errorf("error merging the configuration:%s", err)
-		internalServerError(ctx)
+		repoErrorResponse(ctx, err)
+		return
+	}
+	finalConfig, err = s.resolveConfig(ctx, "smart", app, profiles, finalConfig)
+	if err != nil {
+		log.Errorf("error resolving the configuration:%s", err)
+		repoErrorResponse(ctx, err)
 		return
 	}
 	respondConfig(ctx, finalConfig, ext, log)
@@ -8,9 +8,13 @@ import (
 	"github.com/kataras/iris/v12/httptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/internal/testutil/repotest"
 	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"gopkg.in/yaml.v2"
 	"testing"
 )
@@ -19,6 +23,7 @@ func TestServer_GetSmartConfig(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	repo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(repo)
 
 	appVersion := "v1.0.0"
 	appName := "app1"
@@ -94,3 +99,142 @@ func TestServer_GetSmartConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestServer_GetSmartConfig_MemRepo(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("commonProp: common\nenvironment: none")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("environment: dev")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	req := ht.GET("/v1/config/app1/1.1.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().JSON().Equal(map[string]interface{}{"commonProp": "common", "environment": "dev"})
+
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("environment: dev2")))
+	repo.Publish()
+	req = ht.GET("/v1/config/app1/1.1.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().JSON().Equal(map[string]interface{}{"commonProp": "common", "environment": "dev2"})
+}
+
+func TestServer_GetSmartConfig_Interpolation(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("host: localhost\nurl: http://${host}:${port:8080}")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("host: dev-host\nloop: ${loop}")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	req := ht.GET("/v1/config/app1/1.0.0/int").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().JSON().Equal(map[string]interface{}{"host": "localhost", "url": "http://localhost:8080"})
+	req = ht.GET("/v1/config/app1/1.0.0/int").WithQuery("interpolate", "false").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().JSON().Equal(map[string]interface{}{"host": "localhost", "url": "http://${host}:${port:8080}"})
+	req = ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	res := req.Expect()
+	res.Status(httptest.StatusUnprocessableEntity)
+	res.Body().Contains("loop -> loop")
+}
+
+func TestServer_GetSmartConfig_MultipleProfiles(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("region: none\nreplicas: 1\nfeature: false")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "prod/config.yml", []byte("region: prod\nreplicas: 3")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "eu-west/config.yml", []byte("region: eu-west")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "canary/config.yml", []byte("feature: true")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	req := ht.GET("/v1/config/app1/1.0.0/prod,eu-west,canary").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"region": "eu-west", "replicas": 3, "feature": true})
+	req = ht.GET("/v1/config/app1/1.0.0/eu-west,prod").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"region": "prod", "replicas": 3, "feature": false})
+}
+
+func TestServer_GetSmartConfig_ManifestStrategy(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", merger.ManifestFile, []byte("strategy: spring")))
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", "application.yml", []byte("name: common\nport: 8080")))
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", "spring-app-dev.yml", []byte("port: 9090")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", merger.ManifestFile, []byte("layout:\n  profile: 'envs/{profile}'")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "config.yml", []byte("port: 8080")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "envs/dev.yml", []byte("port: 9090")))
+	assert.NoError(t, repo.SetFile("invalid-app", "1.0.0", merger.ManifestFile, []byte("strategy: unknown")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	req := ht.GET("/v1/config/spring-app/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"name": "common", "port": 9090})
+	req = ht.GET("/v1/config/custom-app/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"port": 9090})
+	req = ht.GET("/v1/config/invalid-app/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("unknown merge strategy")
+
+	ht.GET("/v1/explain/spring-app/1.0.0/dev").Expect().Status(httptest.StatusOK).JSON().Path("$.strategy").Equal("spring")
+}
+
+func TestServer_GetSmartConfig_Schema(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "schema.yml", []byte("properties:\n  port:\n    type: integer")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("port: 8080")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "prod/config.yml", []byte("port: 80a80")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-prod.yml", []byte("port: 80a80")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("port: 8080")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"port": 8080})
+	ht.GET("/v1/config/app1/1.0.0/prod").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusUnprocessableEntity).Body().Contains("port: Invalid type")
+	ht.GET("/v1/spring/1.0.0/app1-prod.json").Expect().Status(httptest.StatusUnprocessableEntity)
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK)
+	ht.GET("/v1/spring/1.0.0/app1/prod").Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("port: Invalid type")
+
+	// the last served configuration is served instead of an invalid one
+	srv.SetSchemaValidator(validation.NewSchemaValidator(true))
+	ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().Status(httptest.StatusOK)
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK)
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("port: 80a80")))
+	repo.Publish()
+	ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"port": 8080})
+	ht.GET("/v1/config/app1/1.0.0/prod").WithHeader("Accept", context.ContentJSONHeaderValue).Expect().
+		Status(httptest.StatusUnprocessableEntity)
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.yml", []byte("port: 80a80")))
+	repo.Publish()
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK).JSON().
+		Path("$.propertySources[0].source").Equal(map[string]interface{}{"port": 8080})
+}
+
+func TestServer_GetSmartConfig_LabelNotSupported(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("commonProp: common")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	req := ht.GET("/v1/config/app1/1.0.0/dev").WithQuery("label", "app1/1.0.0")
+	req.WithHeader("Accept", context.ContentJSONHeaderValue).Expect().Status(httptest.StatusBadRequest)
+}
+
+func TestServer_GetSmartConfig_Shared(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := &sharedRepo{mocks.NewMockRepo(ctrl), mocks.NewMockSharedRepo(ctrl)}
+	repotest.ExpectOnlyYmlSources(repo.MockRepo)
+	repotest.ExpectOnlyYmlSharedSources(repo.MockSharedRepo)
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "config.yml").Return(&configrepo.RepoFile{Content: []byte("db: shared\nlog: info\nenvironment: none")}, nil)
+	repo.MockRepo.EXPECT().GetFile(app, "config.yml").Return(&configrepo.RepoFile{Content: []byte("db: app1\nenvironment: none")}, nil)
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "dev/config.yml").Return(&configrepo.RepoFile{Content: []byte("log: debug\nenvironment: dev")}, nil)
+	repo.MockRepo.EXPECT().GetFile(app, "dev/config.yml").Return(nil, configrepo.ErrFileNotFound)
+
+	req := ht.GET("/v1/config/app1/1.0.0/dev").WithHeader("Accept", context.ContentJSONHeaderValue)
+	req.Expect().JSON().Equal(map[string]interface{}{"db": "app1", "log": "debug", "environment": "dev"})
+}
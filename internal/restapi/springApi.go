@@ -1,16 +1,21 @@
 package restapi
 
 import (
+	"errors"
+	"fmt"
 	"github.com/jeremywohl/flatten"
 	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/router"
 	"github.com/sirupsen/logrus"
-	"github.com/vecosy/vecosy/v2/internal/merger"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
 	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
-	"gopkg.in/yaml.v2"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -18,6 +23,8 @@ type propertySources struct {
 	Name    string                 `json:"name"`
 	Source  map[string]interface{} `json:"source"`
 	version string
+	// config the source document before the flattening and the decryption
+	config map[interface{}]interface{}
 }
 
 type springSummaryResponse struct {
@@ -31,21 +38,33 @@ type springSummaryResponse struct {
 
 var springFileMerger = merger.SpringMerger{}
 
+// sharedPropertySourcePrefix prefix of the property sources names of the shared files
+const sharedPropertySourcePrefix = "shared:"
+
 func (s *Server) registerSpringCloudEndpoints(parent router.Party) {
 	springParty := parent.Party("/spring")
 	springParty.Get("/{appVersion:string}/{appName:string}/{profile:string}", s.springAppInfo)
+	springParty.Get("/{appVersion:string}/{appName:string}/{profile:string}/{label:string}", s.springAppInfo)
+	springParty.Get("/{appVersion:string}/{appName:string}/{profile:string}/{label:string}/{path:path}", s.springResource)
 	springParty.Get("/{appVersion:string}/{appAndProfile:string}", s.springAppFile)
 }
 
-// GET:{appVersion:string}/{appName:string}/{profile:string}
+// GET:{appVersion:string}/{appName:string}/{profile:string}/{label:string}
 func (s *Server) springAppInfo(ctx iris.Context) {
+	if ctx.URLParamExists("useDefaultLabel") {
+		// a resource at the branch root (i.e. /app1/dev/nginx.conf?useDefaultLabel)
+		s.springResource(ctx)
+		return
+	}
 	appVersion := ctx.Params().GetString("appVersion")
 	appName := ctx.Params().GetString("appName")
 	profileParam := ctx.Params().GetString("profile")
 	profiles := strings.Split(profileParam, ",")
+	label := getLabel(ctx)
 	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("profiles", profiles)
+	log = log.WithField("label", label)
 	log.Info("springAppInfo")
-	app := configrepo.NewApplicationVersion(appName, appVersion)
+	app := configrepo.NewApplicationVersionAtLabel(appName, appVersion, label)
 	err := checkApplication(ctx, app, log)
 	if err != nil {
 		return
@@ -64,26 +83,137 @@ func (s *Server) springAppInfo(ctx iris.Context) {
 		PropertySources: make([]*propertySources, 0),
 	}
 
-	for _, configFilePath := range merger.GetSpringApplicationFilePaths(appName, profiles, false) {
-		propertySrc, err := s.getPropertySource(app, configFilePath)
+	if label != "" {
+		response.Label = &label
+	}
+
+	layers, err := merger.Layers(s.repo, app)
+	if err != nil {
+		log.Errorf("Error resolving the application parents:%s", err)
+		repoErrorResponse(ctx, err)
+		return
+	}
+	// the property sources are ordered by precedence (the application first, then its parents)
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		err = s.checkMergeRules(layer)
 		if err != nil {
 			log.Errorf("Error getting resource:%s", err)
-		} else {
-			if propertySrc != nil {
-				response.Version = propertySrc.version
-				response.PropertySources = append(response.PropertySources, propertySrc)
+			repoErrorResponse(ctx, err)
+			return
+		}
+		layout := s.springLayout(layer)
+		sources := layout.Sources(layer.AppName, profiles)
+		utils.ReverseStrings(sources)
+		for _, source := range sources {
+			// the variants are ordered by precedence as well (i.e. .properties before .yml)
+			variants := layout.Variants(source)
+			utils.ReverseStrings(variants)
+			for _, configFilePath := range variants {
+				propertySrcs, err := s.getPropertySources(layer, configFilePath, profiles)
+				if err != nil {
+					if isRefusedSource(err) {
+						log.Errorf("Error getting resource:%s", err)
+						repoErrorResponse(ctx, err)
+						return
+					}
+					continue
+				}
+				for _, propertySrc := range propertySrcs {
+					if layer == app {
+						response.Version = propertySrc.version
+					} else {
+						propertySrc.Name = parentPropertySourceName(layer, propertySrc.Name)
+					}
+				}
+				response.PropertySources = append(response.PropertySources, propertySrcs...)
+			}
+			if i > 0 {
+				continue
 			}
+			for _, configFilePath := range variants {
+				sharedSrcs, err := s.getSharedPropertySources(layer, configFilePath, profiles)
+				if err != nil && isRefusedSource(err) {
+					log.Errorf("Error getting shared resource:%s", err)
+					repoErrorResponse(ctx, err)
+					return
+				}
+				response.PropertySources = append(response.PropertySources, sharedSrcs...)
+			}
+		}
+	}
+
+	// the served property sources are validated as merged by the client
+	validResponse, err := s.schemaValidator.ValidateResponse(s.repo, app, validation.NewRequestKey("spring-sources", app, profiles), response, func() (map[interface{}]interface{}, error) {
+		config, err := encryption.DecryptConfig(s.encryptor, sourcesConfig(response.PropertySources))
+		if err != nil {
+			return nil, err
 		}
+		return merger.Interpolate(config)
+	})
+	if err != nil {
+		log.Errorf("error validating the property sources:%s", err)
+		repoErrorResponse(ctx, err)
+		return
 	}
 
-	_, err = ctx.JSON(response)
+	_, err = ctx.JSON(validResponse)
 	if err != nil {
 		log.Errorf("Error responding :%s", err)
 		internalServerError(ctx)
 	}
 }
 
-// GET: /{application}-{profile}.[yml|json]
+// checkMergeRules returns an error if the manifest of an application layer declares merge rules,
+// the override semantic of the property sources cannot represent them
+func (s *Server) checkMergeRules(app *configrepo.ApplicationVersion) error {
+	manifest, err := merger.ReadManifest(s.repo, app)
+	if err != nil || manifest.Merge.IsEmpty() {
+		// the manifests are validated by merger.Layers
+		return nil
+	}
+	return fmt.Errorf("%w:%s/%s the manifest merge rules are not supported by the property sources", merger.ErrInvalidDirective, app.AppName, app.AppVersion)
+}
+
+// sourcesConfig returns the configuration merged by a client from the property sources (the first one has the highest precedence)
+func sourcesConfig(sources []*propertySources) map[interface{}]interface{} {
+	config := make(map[interface{}]interface{})
+	for i := len(sources) - 1; i >= 0; i-- {
+		overrideConfig(config, sources[i].config)
+	}
+	return config
+}
+
+// overrideConfig overrides the dst values by the src ones, the maps are merged recursively and the null values are ignored
+func overrideConfig(dst, src map[interface{}]interface{}) {
+	for key, srcValue := range src {
+		srcMap, isMap := srcValue.(map[interface{}]interface{})
+		switch {
+		case srcValue == nil:
+		case isMap:
+			dstMap, isDstMap := dst[key].(map[interface{}]interface{})
+			if !isDstMap {
+				dstMap = make(map[interface{}]interface{})
+				dst[key] = dstMap
+			}
+			overrideConfig(dstMap, srcMap)
+		default:
+			dst[key] = srcValue
+		}
+	}
+}
+
+// springLayout returns the spring file layout of an application layer (see merger.Manifest.LayoutOf)
+func (s *Server) springLayout(app *configrepo.ApplicationVersion) *merger.Layout {
+	manifest, err := merger.ReadManifest(s.repo, app)
+	if err != nil {
+		// the manifests are validated by merger.Layers, the missing application version uses the default layout
+		return merger.SpringLayout
+	}
+	return manifest.LayoutOf(merger.SpringStrategy, merger.SpringLayout)
+}
+
+// GET: /{application}-{profile}.[yml|json|properties]
 func (s *Server) springAppFile(ctx iris.Context) {
 	appVersion := ctx.Params().GetString("appVersion")
 	appAndProfile := ctx.Params().GetString("appAndProfile")
@@ -104,51 +234,178 @@ func (s *Server) springAppFile(ctx iris.Context) {
 		return
 	}
 
-	if ext != ".yml" && ext != ".json" && ext != ".yaml" {
+	if ext != ".yml" && ext != ".json" && ext != ".yaml" && ext != ".properties" {
 		log.Errorf("Invalid extension :%s", ext)
-		badRequest(ctx, "invalid extension, only json,yaml are supported")
+		badRequest(ctx, "invalid extension, only json,yaml,properties are supported")
 		return
 	}
 
 	finalConfig, err := springFileMerger.Merge(s.repo, app, []string{profile})
 	if err != nil {
 		log.Errorf("error merging the configuration:%s", err)
-		internalServerError(ctx)
+		repoErrorResponse(ctx, err)
+		return
+	}
+	finalConfig, err = s.resolveConfig(ctx, "spring", app, []string{profile}, finalConfig)
+	if err != nil {
+		log.Errorf("error resolving the configuration:%s", err)
+		repoErrorResponse(ctx, err)
 		return
 	}
 	respondConfig(ctx, finalConfig, ext, log)
 }
 
-// Read a config file and convert it to propertySources
-func (s *Server) getPropertySource(app *configrepo.ApplicationVersion, configFilePath string) (*propertySources, error) {
-	profileFile, err := s.repo.GetFile(app, configFilePath)
+// GET: /{appVersion}/{appName}/{profile}/{label}/{path} or /{appVersion}/{appName}/{profile}/{path}?useDefaultLabel
+//
+// plain text resource of the application (i.e. nginx.conf) with the placeholders resolved by the spring configuration of the profiles,
+// the `interpolate=false` query parameter returns the file untouched
+func (s *Server) springResource(ctx iris.Context) {
+	appVersion := ctx.Params().GetString("appVersion")
+	appName := ctx.Params().GetString("appName")
+	profiles := strings.Split(ctx.Params().GetString("profile"), ",")
+	filePath := ctx.Params().GetString("path")
+	label := getLabel(ctx)
+	if ctx.URLParamExists("useDefaultLabel") {
+		// like spring-cloud-config the label segment is the first part of the path
+		filePath = strings.TrimSuffix(ctx.Params().GetString("label")+"/"+filePath, "/")
+		label = ""
+	}
+	log := logrus.WithField("appName", appName).WithField("appVersion", appVersion).WithField("profiles", profiles)
+	log = log.WithField("label", label).WithField("filePath", filePath)
+	log.Info("springResource")
+
+	app := configrepo.NewApplicationVersionAtLabel(appName, appVersion, label)
+	err := checkApplication(ctx, app, log)
 	if err != nil {
-		logrus.Warnf("Error getting file:%s, err:%s", configFilePath, err)
-		return nil, err
+		return
 	}
 
-	// parsing the content
-	config := make(map[interface{}]interface{})
-	err = yaml.Unmarshal(profileFile.Content, config)
+	err = s.CheckToken(ctx, app)
+	if err != nil {
+		return
+	}
+
+	file, err := configrepo.GetFileOrShared(s.repo, app, filePath)
+	if err != nil {
+		log.Errorf("error getting file err:%s", err)
+		repoErrorResponse(ctx, err)
+		return
+	}
+	content := string(file.Content)
+	if ctx.URLParam("interpolate") != "false" {
+		content, err = s.interpolateResource(app, profiles, content)
+		if err != nil {
+			log.Errorf("error resolving the resource placeholders:%s", err)
+			repoErrorResponse(ctx, err)
+			return
+		}
+	}
+	ctx.Header("ETag", strconv.Quote(file.Version))
+	ctx.ContentType(context.ContentTextHeaderValue)
+	_, err = ctx.WriteString(content)
+	if err != nil {
+		log.Errorf("Error responding :%s", err)
+		internalServerError(ctx)
+	}
+}
+
+// interpolateResource resolves the placeholders of a resource by the decrypted spring configuration (see merger.InterpolateText),
+// only the keys referenced by the resource are resolved
+func (s *Server) interpolateResource(app *configrepo.ApplicationVersion, profiles []string, content string) (string, error) {
+	config, err := springFileMerger.Merge(s.repo, app, profiles)
+	if err != nil {
+		return "", err
+	}
+	config, err = encryption.DecryptConfig(s.encryptor, config)
 	if err != nil {
-		logrus.Errorf("Error parsing yml file:%s, err:%s", configFilePath, err)
+		return "", err
+	}
+	return merger.InterpolateText(content, config)
+}
+
+// parentPropertySourceName returns the property source name of a parent application file (i.e. base-service/2.0.0:application.yml)
+func parentPropertySourceName(parent *configrepo.ApplicationVersion, configFilePath string) string {
+	return fmt.Sprintf("%s/%s:%s", parent.AppName, parent.AppVersion, configFilePath)
+}
+
+// Read a config file and convert its documents active for the profiles to propertySources (ordered by precedence)
+func (s *Server) getPropertySources(app *configrepo.ApplicationVersion, configFilePath string, profiles []string) ([]*propertySources, error) {
+	profileFile, err := s.repo.GetFile(app, configFilePath)
+	if err != nil {
+		if errors.Is(err, configrepo.ErrFileNotFound) {
+			logrus.Debugf("file not found:%s", configFilePath)
+		} else {
+			logrus.Warnf("Error getting file:%s, err:%s", configFilePath, err)
+		}
 		return nil, err
 	}
-	configMap, err := utils.NormalizeMap(config)
+	documents, count, err := merger.ReadActiveDocuments(s.repo, app, configFilePath, profileFile.Content, profiles)
 	if err != nil {
-		logrus.Errorf("Error normalizing json map:%#+vs, err:%s", config, err)
+		logrus.Errorf("Error parsing the source file:%s", err)
 		return nil, err
 	}
+	return s.toPropertySources(profileFile, configFilePath, documents, count)
+}
+
+// isRefusedSource returns true if a source error fails the property sources request,
+// like springAppFile the sources whose tags cannot be resolved are refused (the other invalid sources are skipped),
+// the sources with merge directives are refused as well
+func isRefusedSource(err error) bool {
+	return errors.Is(err, configrepo.ErrLabelNotFound) || errors.Is(err, configrepo.ErrLabelNotSupported) ||
+		errors.Is(err, merger.ErrInvalidInclude) || errors.Is(err, merger.ErrIncludeCycle) || errors.Is(err, merger.ErrInvalidDirective)
+}
 
-	flattenMap, err := flatten.Flatten(configMap, "", flatten.DotStyle)
+// Read a shared config file (see configrepo.SharedRepo) and convert it to propertySources, nil if not found
+func (s *Server) getSharedPropertySources(app *configrepo.ApplicationVersion, configFilePath string, profiles []string) ([]*propertySources, error) {
+	sharedRepo, isShared := s.repo.(configrepo.SharedRepo)
+	if !isShared {
+		return nil, nil
+	}
+	sharedFile, err := sharedRepo.GetSharedFile(app, configFilePath)
 	if err != nil {
-		logrus.Errorf("Error flattering json map:%#+vs, err:%s", config, err)
+		if !errors.Is(err, configrepo.ErrFileNotFound) {
+			logrus.Warnf("Error getting shared file:%s, err:%s", configFilePath, err)
+		}
 		return nil, err
 	}
+	documents, count, err := merger.ReadSharedActiveDocuments(sharedRepo, app, configFilePath, sharedFile.Content, profiles)
+	if err != nil {
+		logrus.Errorf("Error parsing the shared source file:%s", err)
+		return nil, err
+	}
+	return s.toPropertySources(sharedFile, sharedPropertySourcePrefix+configFilePath, documents, count)
+}
 
-	resource := &propertySources{Name: configFilePath, Source: flattenMap, version: profileFile.Version}
-	return resource, nil
+// toPropertySources convert the active documents of a config file (count is the number of documents of the file) to propertySources,
+// like spring-cloud-config every document is a property source (i.e. `application.yml (document #1)`) and the last one has the highest precedence
+func (s *Server) toPropertySources(profileFile *configrepo.RepoFile, configFilePath string, documents []*merger.SourceDocument, count int) ([]*propertySources, error) {
+	resources := make([]*propertySources, 0, len(documents))
+	for i := len(documents) - 1; i >= 0; i-- {
+		document := documents[i]
+		name := configFilePath
+		if count > 1 {
+			name = fmt.Sprintf("%s (document #%d)", configFilePath, document.Index)
+		}
+		if document.Directives {
+			// a property source can only override the keys, the client cannot delete or append what the directives do
+			return nil, fmt.Errorf("%w:%s the merge directives are not supported by the property sources", merger.ErrInvalidDirective, name)
+		}
+		configMap, err := utils.NormalizeMap(document.Config)
+		if err != nil {
+			logrus.Errorf("Error normalizing json map:%#+vs, err:%s", document.Config, err)
+			return nil, err
+		}
 
+		flattenMap, err := flatten.Flatten(configMap, "", flatten.DotStyle)
+		if err != nil {
+			logrus.Errorf("Error flattering json map:%#+vs, err:%s", document.Config, err)
+			return nil, err
+		}
+
+		s.decryptPropertySource(flattenMap)
+		resources = append(resources, &propertySources{Name: name, Source: flattenMap, version: profileFile.Version, config: document.Config})
+	}
+	return resources, nil
 }
 
 var appProfileRe = regexp.MustCompile("([a-z|A-Z|0-9|.]*)*-?")
@@ -10,8 +10,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/internal/testutil/repotest"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"os"
 	"strings"
 	"testing"
@@ -55,6 +58,7 @@ func TestServer_Get_OnlyProfile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	repo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(repo)
 
 	appVersion := "v1.0.0"
 	appName := "app1"
@@ -144,6 +148,7 @@ func TestServer_Get_ProfileAndCommon(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	repo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(repo)
 
 	appVersion := "v1.0.0"
 	appName := "app1"
@@ -356,3 +361,174 @@ func TestServer_Get_InvalidAppVersion(t *testing.T) {
 	ht := httptest.New(t, srv.app)
 	ht.GET("/v1/spring/asdfasd/app-dev.txt").Expect().Status(httptest.StatusBadRequest)
 }
+
+func TestServer_SpringAppInfo_Label(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(repo)
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	app := configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", "app1/v1.0.1")
+	commitVersion := uuid.New().String()
+	repo.EXPECT().GetFile(app, "app1-dev.yml").Return(&configrepo.RepoFile{
+		Version: commitVersion,
+		Content: []byte("prop1: value1"),
+	}, nil)
+	repo.EXPECT().GetFile(app, gomock.Any()).Times(3).Return(nil, configrepo.ErrFileNotFound)
+
+	res := ht.GET("/v1/spring/v1.0.0/app1/dev/app1(_)v1.0.1").Expect()
+	res.Status(httptest.StatusOK)
+	res.JSON().Object().ValueEqual("label", "app1/v1.0.1")
+	res.JSON().Object().ValueEqual("version", commitVersion)
+	res.JSON().Path("$.propertySources[0].source").Equal(map[string]interface{}{"prop1": "value1"})
+
+	repo.EXPECT().GetFile(app, "app1-dev.yml").Return(nil, configrepo.ErrLabelNotFound)
+	ht.GET("/v1/spring/v1.0.0/app1/dev/app1(_)v1.0.1").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestServer_SpringAppInfo_ManifestLayout(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", merger.ManifestFile, []byte("strategy: spring\nlayout:\n  common: defaults\n  profile: 'profiles/{profile}'\n  extensions: [.yml]")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "defaults.yml", []byte("prop1: common")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "profiles/dev.yml", []byte("prop1: dev")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("prop1: ignored")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	res := ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.propertySources").Array().Length().Equal(2)
+	res.Path("$.propertySources[0].name").Equal("profiles/dev.yml")
+	res.Path("$.propertySources[1].name").Equal("defaults.yml")
+}
+
+func TestServer_SpringAppInfo_MultipleDocuments(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("prop1: common\n---\nspring.config.activate.on-profile: prod & eu\nprop1: prod-eu\n---\nspring.profiles: dev\nprop1: dev")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	res := ht.GET("/v1/spring/1.0.0/app1/prod,eu").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.propertySources").Array().Length().Equal(2)
+	res.Path("$.propertySources[0]").Equal(map[string]interface{}{"name": "application.yml (document #1)", "source": map[string]interface{}{"prop1": "prod-eu"}})
+	res.Path("$.propertySources[1]").Equal(map[string]interface{}{"name": "application.yml (document #0)", "source": map[string]interface{}{"prop1": "common"}})
+
+	ht.GET("/v1/spring/1.0.0/app1-dev.yml").Expect().Status(httptest.StatusOK).Body().Equal("prop1: dev\n")
+}
+
+func TestServer_SpringAppInfo_SourceTags(t *testing.T) {
+	merger.SetEnvAllowList([]string{"VECOSY_TEST_HOME"})
+	defer merger.SetEnvAllowList(nil)
+	assert.NoError(t, os.Setenv("VECOSY_TEST_HOME", "/home/vecosy"))
+	defer func() { assert.NoError(t, os.Unsetenv("VECOSY_TEST_HOME")) }()
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("db: !include db.yml\nca: !file certs/ca.pem\nhome: !env VECOSY_TEST_HOME")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "db.yml", []byte("host: localhost")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "certs/ca.pem", []byte("CA")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-prod.yml", []byte("db: !include missing.yml")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	// the property sources contain the values of the tags like the merged configuration
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK).JSON().Path("$.propertySources[0].source").
+		Equal(map[string]interface{}{"db.host": "localhost", "ca": "CA", "home": "/home/vecosy"})
+	ht.GET("/v1/spring/1.0.0/app1-dev.json").Expect().Status(httptest.StatusOK).JSON().
+		Equal(map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "ca": "CA", "home": "/home/vecosy"})
+	ht.GET("/v1/spring/1.0.0/app1/prod").Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("invalid include")
+}
+
+func TestServer_SpringAppInfo_SharedSourceTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := &sharedRepo{mocks.NewMockRepo(ctrl), mocks.NewMockSharedRepo(ctrl)}
+	repotest.ExpectOnlyYmlSources(repo.MockRepo)
+	repotest.ExpectOnlyYmlSharedSources(repo.MockSharedRepo)
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "application.yml").Return(&configrepo.RepoFile{Content: []byte("db: !include db.yml")}, nil)
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, "db.yml").Return(nil, configrepo.ErrFileNotFound)
+	repo.MockSharedRepo.EXPECT().GetSharedFile(app, gomock.Any()).AnyTimes().Return(nil, configrepo.ErrFileNotFound)
+	repo.MockRepo.EXPECT().GetFile(app, gomock.Any()).AnyTimes().Return(nil, configrepo.ErrFileNotFound)
+
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("invalid include")
+}
+
+func TestServer_SpringAppInfo_Directives(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("features: [f1]")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.yml", []byte("features: !append [f2]")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", merger.ManifestFile, []byte("strategy: spring\nmerge:\n  lists:\n    features: append")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", "application.yml", []byte("features: [f1]")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	// the property sources cannot represent the directives, the merged configuration applies them
+	ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("app1-dev.yml the merge directives are not supported")
+	ht.GET("/v1/spring/1.0.0/app1-dev.json").Expect().Status(httptest.StatusOK).JSON().Equal(map[string]interface{}{"features": []interface{}{"f1", "f2"}})
+	ht.GET("/v1/spring/1.0.0/app1/prod").Expect().Status(httptest.StatusOK)
+	ht.GET("/v1/spring/1.0.0/app2/dev").Expect().Status(httptest.StatusUnprocessableEntity).Body().Contains("merge rules are not supported")
+}
+
+func TestServer_SpringAppFile_Properties(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("server:\n  port: 8080\ngreeting: 'hello: ${server.port}'\nservers: [s1, s2]")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	res := ht.GET("/v1/spring/1.0.0/app1-dev.properties").Expect().Status(httptest.StatusOK)
+	res.ContentType("text/plain")
+	res.Body().Equal("greeting: hello\\: 8080\nserver.port: 8080\nservers[0]: s1\nservers[1]: s2\n")
+}
+
+func TestServer_SpringResource(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	// the cycle and the malformed placeholder are not referenced by the resources
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("server:\n  port: 8080\nloop: ${loop}\ncmd: echo ${server.port")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-prod.yml", []byte("server:\n  port: 80")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "conf/nginx.conf", []byte("listen ${server.port};\nroot ${HOME}/www;")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "notes.txt", []byte("port ${server.port}")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+	file, err := repo.GetFile(configrepo.NewApplicationVersion("app1", "1.0.0"), "conf/nginx.conf")
+	assert.NoError(t, err)
+
+	res := ht.GET("/v1/spring/1.0.0/app1/prod/conf/nginx.conf").WithQuery("useDefaultLabel", "").Expect().Status(httptest.StatusOK)
+	res.ContentType("text/plain")
+	res.Header("ETag").Equal(fmt.Sprintf("%q", file.Version))
+	res.Body().Equal("listen 80;\nroot ${HOME}/www;")
+	ht.GET("/v1/spring/1.0.0/app1/dev/notes.txt").WithQuery("useDefaultLabel", "").Expect().Status(httptest.StatusOK).Body().Equal("port 8080")
+	ht.GET("/v1/spring/1.0.0/app1/dev/conf/nginx.conf").WithQuery("useDefaultLabel", "").WithQuery("interpolate", "false").
+		Expect().Status(httptest.StatusOK).Body().Equal("listen ${server.port};\nroot ${HOME}/www;")
+	ht.GET("/v1/spring/1.0.0/app1/dev/conf/missing.conf").WithQuery("useDefaultLabel", "").Expect().Status(httptest.StatusNotFound)
+	// the memory repo doesn't support the labels
+	ht.GET("/v1/spring/1.0.0/app1/dev/app1(_)1.0.0/conf/nginx.conf").Expect().Status(httptest.StatusBadRequest)
+}
+
+func TestServer_Spring_SearchPaths(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", merger.ManifestFile, []byte("strategy: spring\nlayout:\n  searchPaths: ['{application}', 'config/{profile}']")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("prop1: root\nprop2: root")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1/application.yml", []byte("prop1: app1")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config/dev/app1-dev.yml", []byte("prop2: dev")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config/prod/app1-prod.yml", []byte("prop2: prod")))
+	assert.NoError(t, repo.Init())
+	srv := New(repo, "127.0.0.1:8080", false)
+	ht := httptest.New(t, srv.app)
+
+	res := ht.GET("/v1/spring/1.0.0/app1/dev").Expect().Status(httptest.StatusOK).JSON()
+	res.Path("$.propertySources").Array().Length().Equal(3)
+	res.Path("$.propertySources[0]").Equal(map[string]interface{}{"name": "config/dev/app1-dev.yml", "source": map[string]interface{}{"prop2": "dev"}})
+	res.Path("$.propertySources[1].name").Equal("app1/application.yml")
+	res.Path("$.propertySources[2].name").Equal("application.yml")
+
+	ht.GET("/v1/spring/1.0.0/app1-dev.yml").Expect().Status(httptest.StatusOK).Body().Equal("prop1: app1\nprop2: dev\n")
+}
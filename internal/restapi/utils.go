@@ -1,11 +1,14 @@
 package restapi
 
 import (
+	"errors"
 	"fmt"
 	"github.com/kataras/iris/v12"
 	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
 	"github.com/vecosy/vecosy/v2/internal/validation"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
 	"net/http"
 	"strings"
 )
@@ -29,6 +32,59 @@ func unAuthorizedResponse(ctx iris.Context) {
 	ctx.StatusCode(http.StatusUnauthorized)
 }
 
+// getLabel returns the requested label from the path or the `label` query parameter
+//
+// the spring-cloud-config "(_)" placeholder is replaced by "/" (i.e. app1(_)1.0.0)
+func getLabel(ctx iris.Context) string {
+	label := ctx.Params().GetStringDefault("label", ctx.URLParam("label"))
+	return strings.ReplaceAll(label, "(_)", "/")
+}
+
+// resolveConfig decrypts the `{cipher}` values (see encryption.DecryptConfig) and resolves the placeholders (see merger.Interpolate)
+// of the merged configuration, the `interpolate=false` query parameter leaves the placeholders untouched for the clients that resolve them
+//
+// the resolved configuration is validated against the application branch schema (see validation.SchemaValidator)
+func (s *Server) resolveConfig(ctx iris.Context, api string, app *configrepo.ApplicationVersion, profiles []string, config map[interface{}]interface{}) (map[interface{}]interface{}, error) {
+	config, err := encryption.DecryptConfig(s.encryptor, config)
+	if err != nil {
+		return nil, err
+	}
+	interpolate := ctx.URLParam("interpolate") != "false"
+	if interpolate {
+		config, err = merger.Interpolate(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key := validation.NewRequestKey(api, app, profiles, fmt.Sprintf("interpolate:%t", interpolate))
+	return s.schemaValidator.Validate(s.repo, app, key, config)
+}
+
+// repoErrorResponse responds with the status related to a repo error
+func repoErrorResponse(ctx iris.Context, err error) {
+	switch {
+	case errors.Is(err, configrepo.ErrFileNotFound), errors.Is(err, configrepo.ErrLabelNotFound):
+		notFoundResponse(ctx)
+	case errors.Is(err, configrepo.ErrLabelNotSupported):
+		badRequest(ctx, "labels are not supported by the repo")
+	case errors.Is(err, configrepo.ErrInvalidPath):
+		badRequest(ctx, err.Error())
+	case errors.Is(err, configrepo.ErrConflict):
+		ctx.StatusCode(http.StatusConflict)
+		_, _ = ctx.WriteString(err.Error())
+	case errors.Is(err, configrepo.ErrWriteNotSupported):
+		ctx.StatusCode(http.StatusMethodNotAllowed)
+		_, _ = ctx.WriteString(err.Error())
+	case errors.Is(err, merger.ErrPlaceholderCycle), errors.Is(err, merger.ErrInvalidPlaceholder),
+		errors.Is(err, merger.ErrInvalidInclude), errors.Is(err, merger.ErrIncludeCycle), errors.Is(err, merger.ErrInvalidDirective), errors.Is(err, encryption.ErrDecryption),
+		errors.Is(err, validation.ErrInvalidConfig), errors.Is(err, validation.ErrInvalidSchema), errors.Is(err, merger.ErrInvalidManifest):
+		ctx.StatusCode(http.StatusUnprocessableEntity)
+		_, _ = ctx.WriteString(err.Error())
+	default:
+		internalServerError(ctx)
+	}
+}
+
 func getAccepts(ctx iris.Context) map[string]bool {
 	result := make(map[string]bool)
 	for _, accept := range strings.Split(ctx.GetHeader("Accept"), ",") {
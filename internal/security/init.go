@@ -1,6 +1,7 @@
 package security
 
 import (
+	"crypto/rsa"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/caches"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
@@ -10,6 +11,8 @@ import (
 // CheckJwtToken check a jws token signature
 func CheckJwtToken(repo configrepo.Repo, app *configrepo.ApplicationVersion, token string) error {
 	log := logrus.WithField("method", "CheckJwtToken")
+	// the token is always checked with the public key of the current application version
+	app = configrepo.NewApplicationVersion(app.AppName, app.AppVersion)
 	repoPubKey, err := caches.KeyCache.GetOrSetPubKey(repo, app)
 	if err != nil {
 		log.Errorf("Error getting repo pub key:%s", err)
@@ -27,3 +30,23 @@ func CheckJwtToken(repo configrepo.Repo, app *configrepo.ApplicationVersion, tok
 	}
 	return nil
 }
+
+// CheckAdminToken check a jws token signature with the admin public key, it always fails if no admin key is configured
+func CheckAdminToken(adminPubKey *rsa.PublicKey, token string) error {
+	log := logrus.WithField("method", "CheckAdminToken")
+	if adminPubKey == nil {
+		log.Error("no admin public key configured")
+		return ErrAuthFailed
+	}
+	jws, err := jose.ParseSigned(token)
+	if err != nil {
+		log.Errorf("Error parsing jws:%s", err)
+		return ErrAuthFailed
+	}
+	_, err = jws.Verify(adminPubKey)
+	if err != nil {
+		log.Errorf("Error verifying jws:%s", err)
+		return ErrAuthFailed
+	}
+	return nil
+}
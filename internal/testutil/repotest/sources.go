@@ -0,0 +1,49 @@
+package repotest
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/vecosy/vecosy/v2/internal/validation"
+	"github.com/vecosy/vecosy/v2/mocks"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"path"
+)
+
+// ExpectOnlyYmlSources TEST-ONLY: the repo mock doesn't contain the application manifest, the schema and the non .yml source variants
+func ExpectOnlyYmlSources(repo *mocks.MockRepo) {
+	repo.EXPECT().GetFile(gomock.Any(), nonYmlSource{}).Return(nil, configrepo.ErrFileNotFound).AnyTimes()
+}
+
+// ExpectOnlyYmlSharedSources TEST-ONLY: the shared repo mock doesn't contain the non .yml source variants
+func ExpectOnlyYmlSharedSources(repo *mocks.MockSharedRepo) {
+	repo.EXPECT().GetSharedFile(gomock.Any(), nonYmlSource{}).Return(nil, configrepo.ErrFileNotFound).AnyTimes()
+}
+
+// nonYmlSource matches the application manifest, the schema files and the source files with an extension other than .yml
+type nonYmlSource struct{}
+
+func (nonYmlSource) Matches(x interface{}) bool {
+	filePath, isString := x.(string)
+	if !isString {
+		return false
+	}
+	if filePath == merger.ManifestFile {
+		return true
+	}
+	for _, schemaFile := range validation.SchemaFiles {
+		if filePath == schemaFile {
+			return true
+		}
+	}
+	ext := path.Ext(filePath)
+	for _, sourceExt := range merger.SourceExtensions {
+		if ext == sourceExt && ext != ".yml" {
+			return true
+		}
+	}
+	return false
+}
+
+func (nonYmlSource) String() string {
+	return "is the manifest, a schema or a non .yml source file"
+}
@@ -34,3 +34,23 @@ func BytesToPublicKey(pub []byte) (*rsa.PublicKey, error) {
 	}
 	return key, nil
 }
+
+// BytesToPrivateKey parse a PEM (PKCS1 or PKCS8) byteArray to a rsa.PrivateKey
+func BytesToPrivateKey(priv []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(priv)
+	if block == nil {
+		return nil, errors.New("decode error")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	ifc, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := ifc.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not a RSA private key")
+	}
+	return key, nil
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
 	"testing"
@@ -22,3 +24,23 @@ func TestPublicKeyConversions(t *testing.T) {
 	check.Error(err)
 	check.Nil(readedPubKey)
 }
+
+func TestPrivateKeyConversions(t *testing.T) {
+	check := assert.New(t)
+	privKey, _, err := testutil.GenerateKeyPair()
+	check.NoError(err)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privKey)})
+	readPrivKey, err := BytesToPrivateKey(pkcs1)
+	check.NoError(err)
+	check.Equal(privKey.D, readPrivKey.D)
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(privKey)
+	check.NoError(err)
+	readPrivKey, err = BytesToPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}))
+	check.NoError(err)
+	check.Equal(privKey.D, readPrivKey.D)
+
+	readPrivKey, err = BytesToPrivateKey([]byte("notValidPrivKey"))
+	check.Error(err)
+	check.Nil(readPrivKey)
+}
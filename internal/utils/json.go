@@ -40,14 +40,30 @@ func NormalizeMap(mp map[interface{}]interface{}) (map[string]interface{}, error
 			return nil, fmt.Errorf("unsupported map key of type: %s, key: %+#v, value: %+#v",
 				reflect.TypeOf(k), k, v)
 		}
-		if mii, ok := v.(map[interface{}]interface{}); ok {
-			strMap[keyString], err = NormalizeMap(mii)
+		strMap[keyString], err = NormalizeValue(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return strMap, nil
+}
+
+// NormalizeValue normalize the maps of a value (see NormalizeMap), the list items included
+func NormalizeValue(v interface{}) (interface{}, error) {
+	switch typedValue := v.(type) {
+	case map[interface{}]interface{}:
+		return NormalizeMap(typedValue)
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			normItem, err := NormalizeValue(item)
 			if err != nil {
 				return nil, err
 			}
-		} else {
-			strMap[keyString] = v
+			result[i] = normItem
 		}
+		return result, nil
+	default:
+		return v, nil
 	}
-	return strMap, nil
 }
@@ -27,3 +27,15 @@ func TestNormalizeMap(t *testing.T) {
 	check.NoError(err)
 	check.EqualValues(expected, output)
 }
+
+func TestNormalizeMap_Lists(t *testing.T) {
+	input := map[interface{}]interface{}{
+		"servers": []interface{}{map[interface{}]interface{}{"name": "s1", 1: true}, "s2"},
+	}
+	expected := map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{"name": "s1", "1": true}, "s2"},
+	}
+	output, err := NormalizeMap(input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
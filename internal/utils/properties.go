@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// ToProperties serialize a normalized map (see NormalizeMap) to the .properties format (one sorted `key: value` line per property like spring-cloud-config),
+// the nested maps are flattened by dotted keys and the list items by index (i.e. `servers[0].name`).
+//
+// the keys and the values are escaped following the java.util.Properties rules (the non ASCII characters are written as \uXXXX)
+func ToProperties(config map[string]interface{}) string {
+	properties := make(map[string]string)
+	flattenProperties(properties, "", config)
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var result strings.Builder
+	for _, key := range keys {
+		result.WriteString(escapeProperty(key, true))
+		result.WriteString(": ")
+		result.WriteString(escapeProperty(properties[key], false))
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+func flattenProperties(properties map[string]string, key string, value interface{}) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for childKey, child := range typedValue {
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			flattenProperties(properties, childKey, child)
+		}
+	case []interface{}:
+		for i, item := range typedValue {
+			flattenProperties(properties, fmt.Sprintf("%s[%d]", key, i), item)
+		}
+	case nil:
+		properties[key] = ""
+	default:
+		properties[key] = fmt.Sprint(typedValue)
+	}
+}
+
+// escapeProperty escape a key or a value like java.util.Properties.store does
+func escapeProperty(value string, isKey bool) string {
+	var result strings.Builder
+	for i, char := range value {
+		switch char {
+		case ' ':
+			if isKey || i == 0 {
+				result.WriteString(`\ `)
+			} else {
+				result.WriteRune(char)
+			}
+		case '\\', '=', ':', '#', '!':
+			result.WriteRune('\\')
+			result.WriteRune(char)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '\f':
+			result.WriteString(`\f`)
+		default:
+			if char < 0x20 || char > 0x7e {
+				for _, unit := range utf16.Encode([]rune{char}) {
+					result.WriteString(fmt.Sprintf(`\u%04X`, unit))
+				}
+			} else {
+				result.WriteRune(char)
+			}
+		}
+	}
+	return result.String()
+}
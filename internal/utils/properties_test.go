@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"github.com/magiconair/properties"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToProperties(t *testing.T) {
+	check := assert.New(t)
+	config := map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080},
+		"servers": []interface{}{
+			map[string]interface{}{"name": "s1"},
+			"s2",
+		},
+		"empty":       nil,
+		"greeting":    " héllo: world = #1!",
+		"multiline":   "line1\nline2\\",
+		"key with=sp": true,
+	}
+	output := ToProperties(config)
+	check.Equal(`empty: 
+greeting: \ h\u00E9llo\: world \= \#1\!
+key\ with\=sp: true
+multiline: line1\nline2\\
+server.port: 8080
+servers[0].name: s1
+servers[1]: s2
+`, output)
+
+	// the output is readable by a java.util.Properties compatible parser
+	props, err := properties.Load([]byte(output), properties.ISO_8859_1)
+	check.NoError(err)
+	check.Equal(" héllo: world = #1!", props.GetString("greeting", ""))
+	check.Equal("line1\nline2\\", props.GetString("multiline", ""))
+	check.Equal("true", props.GetString("key with=sp", ""))
+	check.Equal("", props.GetString("empty", "default"))
+}
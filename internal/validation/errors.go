@@ -7,3 +7,9 @@ var ErrInvalidVersion = errors.New("invalid version")
 
 // ErrInvalidApplicationName returned if the application has an invalid name
 var ErrInvalidApplicationName = errors.New("invalid application name")
+
+// ErrInvalidConfig returned if a configuration doesn't match the schema of its branch
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ErrInvalidSchema returned if the schema of a branch cannot be parsed
+var ErrInvalidSchema = errors.New("invalid schema")
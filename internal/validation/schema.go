@@ -0,0 +1,239 @@
+package validation
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v2"
+	"path"
+	"strings"
+	"sync"
+)
+
+// SchemaFiles the JSON schema files of a branch, the first one found is used
+var SchemaFiles = []string{"schema.json", "schema.yml"}
+
+// SchemaValidator validates the served configurations against the JSON schema of the application branch (see SchemaFiles)
+type SchemaValidator struct {
+	// keepLastServed the last valid response served by this validator for the same request is served instead of an invalid one
+	keepLastServed bool
+	lastServed     *lastServedCache
+}
+
+// LastServedCacheSize the maximum number of last served responses kept by a SchemaValidator, the least recently served are dropped
+const LastServedCacheSize = 1024
+
+// NewSchemaValidator returns a schema validator, the invalid configurations are rejected (ErrInvalidConfig)
+// or replaced by the last valid response served by the validator for the same request (keepLastServed).
+//
+// The last served responses are kept in memory only: a request not served since the server start
+// (i.e. a new profiles combination) is rejected, the same validator should be shared by the APIs
+func NewSchemaValidator(keepLastServed bool) *SchemaValidator {
+	return &SchemaValidator{keepLastServed: keepLastServed, lastServed: newLastServedCache(LastServedCacheSize)}
+}
+
+// RequestKey identifies the requests served with the same configuration
+type RequestKey struct {
+	// API the kind of served configuration (i.e. smart or spring)
+	API string
+	configrepo.ApplicationVersion
+	// Profiles the non empty requested profiles (in precedence order)
+	Profiles string
+	// Options the request options that change the served configuration (i.e. the interpolation)
+	Options string
+}
+
+// NewRequestKey returns the key of a request, the empty profiles are ignored
+func NewRequestKey(api string, app *configrepo.ApplicationVersion, profiles []string, options ...string) RequestKey {
+	requestedProfiles := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile != "" {
+			requestedProfiles = append(requestedProfiles, profile)
+		}
+	}
+	return RequestKey{API: api, ApplicationVersion: *app, Profiles: strings.Join(requestedProfiles, ","), Options: strings.Join(options, ",")}
+}
+
+// Validate returns the configuration to serve for a request:
+// the configuration itself if it's valid or if the branch has no schema, otherwise the last served one (if kept) or ErrInvalidConfig
+func (v *SchemaValidator) Validate(repo configrepo.Repo, app *configrepo.ApplicationVersion, key RequestKey, config map[interface{}]interface{}) (map[interface{}]interface{}, error) {
+	response, err := v.ValidateResponse(repo, app, key, config, func() (map[interface{}]interface{}, error) {
+		return config, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(map[interface{}]interface{}), nil
+}
+
+// ValidateResponse returns the response to serve for a request built from a configuration (i.e. the spring property sources):
+// the response itself if the configuration is valid or if the branch has no schema, otherwise the last served response (if kept) or ErrInvalidConfig.
+// The configuration is built only if the branch has a schema
+func (v *SchemaValidator) ValidateResponse(repo configrepo.Repo, app *configrepo.ApplicationVersion, key RequestKey, response interface{}, config func() (map[interface{}]interface{}, error)) (interface{}, error) {
+	schemaLoader, err := getSchemaLoader(repo, app)
+	if err != nil {
+		return nil, err
+	}
+	if schemaLoader != nil {
+		validatedConfig, err := config()
+		if err != nil {
+			return nil, err
+		}
+		err = validateConfig(schemaLoader, validatedConfig)
+		if v.keepLastServed && errors.Is(err, ErrInvalidConfig) {
+			if lastServed, found := v.lastServed.load(key); found {
+				logrus.Warnf("Serving the last served configuration of %+v, err:%s", key, err)
+				return lastServed, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.keepLastServed {
+		v.lastServed.store(key, response)
+	}
+	return response, nil
+}
+
+// lastServedCache a least recently used cache of the last served (valid) responses
+type lastServedCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[RequestKey]*list.Element
+	// recent the entries ordered from the most recently used
+	recent *list.List
+}
+
+type lastServedEntry struct {
+	key      RequestKey
+	response interface{}
+}
+
+func newLastServedCache(size int) *lastServedCache {
+	return &lastServedCache{size: size, entries: make(map[RequestKey]*list.Element), recent: list.New()}
+}
+
+func (c *lastServedCache) store(key RequestKey, response interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, found := c.entries[key]; found {
+		element.Value.(*lastServedEntry).response = response
+		c.recent.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.recent.PushFront(&lastServedEntry{key: key, response: response})
+	if c.recent.Len() > c.size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lastServedEntry).key)
+	}
+}
+
+func (c *lastServedCache) load(key RequestKey) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	c.recent.MoveToFront(element)
+	return element.Value.(*lastServedEntry).response, true
+}
+
+// ValidateSchema validates the configuration against the JSON schema of the application branch (nil if the branch has no schema),
+// the error message contains the paths of the invalid values
+func ValidateSchema(repo configrepo.Repo, app *configrepo.ApplicationVersion, config map[interface{}]interface{}) error {
+	schemaLoader, err := getSchemaLoader(repo, app)
+	if err != nil || schemaLoader == nil {
+		return err
+	}
+	return validateConfig(schemaLoader, config)
+}
+
+// validateConfig validates the configuration against a JSON schema
+func validateConfig(schemaLoader gojsonschema.JSONLoader, config map[interface{}]interface{}) error {
+	schema, err := gojsonschema.NewSchema(schemaLoader)
+	if err != nil {
+		return fmt.Errorf("%w:%s", ErrInvalidSchema, err)
+	}
+	normConfig, err := utils.NormalizeMap(config)
+	if err != nil {
+		return err
+	}
+	result, err := schema.Validate(gojsonschema.NewGoLoader(normConfig))
+	if err != nil {
+		return fmt.Errorf("%w:%s", ErrInvalidSchema, err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	// the values are not reported, they can be secrets
+	violations := make([]string, len(result.Errors()))
+	for i, violation := range result.Errors() {
+		violations[i] = fmt.Sprintf("%s: %s", violation.Field(), violation.Description())
+	}
+	return fmt.Errorf("%w:%s", ErrInvalidConfig, strings.Join(violations, ", "))
+}
+
+// getSchemaLoader returns the loader of the first schema file of the branch, nil if none
+func getSchemaLoader(repo configrepo.Repo, app *configrepo.ApplicationVersion) (gojsonschema.JSONLoader, error) {
+	for _, schemaFile := range SchemaFiles {
+		file, err := repo.GetFile(app, schemaFile)
+		if err != nil {
+			if errors.Is(err, configrepo.ErrFileNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		var schema interface{}
+		if path.Ext(schemaFile) == ".json" {
+			schema, err = gojsonschema.NewBytesLoader(file.Content).LoadJSON()
+		} else {
+			schema, err = loadYamlSchema(file.Content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w:%s %s", ErrInvalidSchema, schemaFile, err)
+		}
+		if err = checkReferences(schema); err != nil {
+			return nil, fmt.Errorf("%w:%s %s", ErrInvalidSchema, schemaFile, err)
+		}
+		return gojsonschema.NewGoLoader(schema), nil
+	}
+	return nil, nil
+}
+
+func loadYamlSchema(content []byte) (interface{}, error) {
+	schema := make(map[interface{}]interface{})
+	err := yaml.Unmarshal(content, schema)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeMap(schema)
+}
+
+// checkReferences returns an error if the schema references an external document (file or url), only the local references (`#/...`) are allowed
+func checkReferences(schema interface{}) error {
+	switch typedSchema := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range typedSchema {
+			if ref, isString := value.(string); key == "$ref" && isString && !strings.HasPrefix(ref, "#") {
+				return fmt.Errorf("external reference not allowed:%s", ref)
+			}
+			if err := checkReferences(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range typedSchema {
+			if err := checkReferences(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package validation_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/internal/validation"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "properties": {
+    "server": {
+      "type": "object",
+      "properties": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}},
+      "required": ["port"]
+    },
+    "servers": {"type": "array", "items": {"$ref": "#/definitions/server"}}
+  },
+  "definitions": {
+    "server": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}
+  }
+}`
+
+func TestValidateSchema(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "schema.json", []byte(testSchema)))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", "schema.yml", []byte("type: object\nproperties:\n  port:\n    type: integer")))
+	assert.NoError(t, repo.SetFile("app3", "1.0.0", "config.yml", []byte("port: 8080")))
+	assert.NoError(t, repo.Init())
+	app1 := configrepo.NewApplicationVersion("app1", "1.0.0")
+	app2 := configrepo.NewApplicationVersion("app2", "1.0.0")
+
+	validConfig := map[interface{}]interface{}{
+		"server":  map[interface{}]interface{}{"port": 8080},
+		"servers": []interface{}{map[interface{}]interface{}{"name": "s1"}},
+	}
+	assert.NoError(t, validation.ValidateSchema(repo, app1, validConfig))
+
+	invalidConfig := map[interface{}]interface{}{
+		"server":  map[interface{}]interface{}{"port": "80800"},
+		"servers": []interface{}{map[interface{}]interface{}{"host": "s1"}},
+	}
+	err := validation.ValidateSchema(repo, app1, invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+	assert.Contains(t, err.Error(), "server.port: Invalid type")
+	assert.Contains(t, err.Error(), "servers.0: name is required")
+	// the values are not reported
+	assert.NotContains(t, err.Error(), "80800")
+
+	assert.NoError(t, validation.ValidateSchema(repo, app2, map[interface{}]interface{}{"port": 8080}))
+	assert.True(t, errors.Is(validation.ValidateSchema(repo, app2, map[interface{}]interface{}{"port": "8080"}), validation.ErrInvalidConfig))
+	// no schema
+	assert.NoError(t, validation.ValidateSchema(repo, configrepo.NewApplicationVersion("app3", "1.0.0"), map[interface{}]interface{}{"port": "8080"}))
+}
+
+func TestValidateSchema_InvalidSchema(t *testing.T) {
+	tests := map[string]string{
+		"not a json":         "{type: object",
+		"external reference": `{"properties": {"port": {"$ref": "file:///etc/schema.json"}}}`,
+		"invalid type":       `{"type": "unknown"}`,
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := memconfigrepo.NewMemConfigRepo()
+			assert.NoError(t, repo.SetFile("app1", "1.0.0", "schema.json", []byte(schema)))
+			assert.NoError(t, repo.Init())
+			err := validation.ValidateSchema(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), map[interface{}]interface{}{})
+			assert.True(t, errors.Is(err, validation.ErrInvalidSchema), "unexpected error:%s", err)
+		})
+	}
+}
+
+func TestSchemaValidator_Validate(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "schema.json", []byte(testSchema)))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	validConfig := map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 8080}}
+	invalidConfig := map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 0}}
+
+	key := validation.NewRequestKey("smart", app, []string{"dev"})
+
+	rejecting := validation.NewSchemaValidator(false)
+	config, err := rejecting.Validate(repo, app, key, validConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, validConfig, config)
+	_, err = rejecting.Validate(repo, app, key, invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+
+	keeping := validation.NewSchemaValidator(true)
+	// no valid configuration served yet
+	_, err = keeping.Validate(repo, app, key, invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+	config, err = keeping.Validate(repo, app, key, validConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, validConfig, config)
+	config, err = keeping.Validate(repo, app, key, invalidConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, validConfig, config)
+	// the empty profiles don't change the request
+	config, err = keeping.Validate(repo, app, validation.NewRequestKey("smart", app, []string{"", "dev", ""}), invalidConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, validConfig, config)
+	// the last served configuration is kept by request
+	_, err = keeping.Validate(repo, app, validation.NewRequestKey("smart", app, []string{"prod"}), invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+	_, err = keeping.Validate(repo, app, validation.NewRequestKey("smart", app, []string{"dev"}, "interpolate:false"), invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+}
+
+func TestSchemaValidator_Validate_Bounded(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "schema.json", []byte(testSchema)))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	validConfig := map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 8080}}
+	invalidConfig := map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 0}}
+
+	validator := validation.NewSchemaValidator(true)
+	for i := 0; i <= validation.LastServedCacheSize; i++ {
+		_, err := validator.Validate(repo, app, validation.NewRequestKey("smart", app, []string{fmt.Sprintf("profile%d", i)}), validConfig)
+		assert.NoError(t, err)
+	}
+	// the least recently served configuration has been dropped
+	_, err := validator.Validate(repo, app, validation.NewRequestKey("smart", app, []string{"profile0"}), invalidConfig)
+	assert.True(t, errors.Is(err, validation.ErrInvalidConfig))
+	config, err := validator.Validate(repo, app, validation.NewRequestKey("smart", app, []string{"profile1"}), invalidConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, validConfig, config)
+}
@@ -143,3 +143,131 @@ func (mr *MockRepoMockRecorder) AddOnChangeHandler(handler interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOnChangeHandler", reflect.TypeOf((*MockRepo)(nil).AddOnChangeHandler), handler)
 }
+
+// MockReferenceMatcher is a mock of ReferenceMatcher interface
+type MockReferenceMatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferenceMatcherMockRecorder
+}
+
+// MockReferenceMatcherMockRecorder is the mock recorder for MockReferenceMatcher
+type MockReferenceMatcherMockRecorder struct {
+	mock *MockReferenceMatcher
+}
+
+// NewMockReferenceMatcher creates a new mock instance
+func NewMockReferenceMatcher(ctrl *gomock.Controller) *MockReferenceMatcher {
+	mock := &MockReferenceMatcher{ctrl: ctrl}
+	mock.recorder = &MockReferenceMatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReferenceMatcher) EXPECT() *MockReferenceMatcherMockRecorder {
+	return m.recorder
+}
+
+// MatchReference mocks base method
+func (m *MockReferenceMatcher) MatchReference(refName string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchReference", refName)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// MatchReference indicates an expected call of MatchReference
+func (mr *MockReferenceMatcherMockRecorder) MatchReference(refName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchReference", reflect.TypeOf((*MockReferenceMatcher)(nil).MatchReference), refName)
+}
+
+// MockWriter is a mock of Writer interface
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// WriteFile mocks base method
+func (m *MockWriter) WriteFile(app *configrepo.ApplicationVersion, path string, content []byte, opts *configrepo.WriteOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteFile", app, path, content, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WriteFile indicates an expected call of WriteFile
+func (mr *MockWriterMockRecorder) WriteFile(app, path, content, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteFile", reflect.TypeOf((*MockWriter)(nil).WriteFile), app, path, content, opts)
+}
+
+// DeleteFile mocks base method
+func (m *MockWriter) DeleteFile(app *configrepo.ApplicationVersion, path string, opts *configrepo.WriteOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", app, path, opts)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFile indicates an expected call of DeleteFile
+func (mr *MockWriterMockRecorder) DeleteFile(app, path, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockWriter)(nil).DeleteFile), app, path, opts)
+}
+
+// MockSharedRepo is a mock of SharedRepo interface
+type MockSharedRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSharedRepoMockRecorder
+}
+
+// MockSharedRepoMockRecorder is the mock recorder for MockSharedRepo
+type MockSharedRepoMockRecorder struct {
+	mock *MockSharedRepo
+}
+
+// NewMockSharedRepo creates a new mock instance
+func NewMockSharedRepo(ctrl *gomock.Controller) *MockSharedRepo {
+	mock := &MockSharedRepo{ctrl: ctrl}
+	mock.recorder = &MockSharedRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSharedRepo) EXPECT() *MockSharedRepoMockRecorder {
+	return m.recorder
+}
+
+// GetSharedFile mocks base method
+func (m *MockSharedRepo) GetSharedFile(app *configrepo.ApplicationVersion, path string) (*configrepo.RepoFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedFile", app, path)
+	ret0, _ := ret[0].(*configrepo.RepoFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedFile indicates an expected call of GetSharedFile
+func (mr *MockSharedRepoMockRecorder) GetSharedFile(app, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedFile", reflect.TypeOf((*MockSharedRepo)(nil).GetSharedFile), app, path)
+}
@@ -0,0 +1,125 @@
+package configrepo
+
+import (
+	"github.com/hashicorp/go-version"
+	"sort"
+)
+
+// ChangeKind represent the kind of change of an application version
+type ChangeKind int
+
+const (
+	// VersionAdded a new application version has been added
+	VersionAdded ChangeKind = iota
+	// VersionUpdated the content of an application version has been changed
+	VersionUpdated
+	// VersionRemoved an application version has been removed, the requests will fall back to the previous version (if any)
+	VersionRemoved
+)
+
+var changeKindNames = map[ChangeKind]string{
+	VersionAdded:   "added",
+	VersionUpdated: "updated",
+	VersionRemoved: "removed",
+}
+
+func (k ChangeKind) String() string {
+	return changeKindNames[k]
+}
+
+// Change represent a change of an application version
+type Change struct {
+	ApplicationVersion
+	Kind ChangeKind
+	// OldHash the hash (i.e. git commit) the application version was resolved to before the change, empty if none
+	OldHash string
+	// NewHash the hash the application version is resolved to after the change, empty if none
+	NewHash string
+	// ChangedPaths the files added, modified or removed between OldHash and NewHash, nil if unknown
+	ChangedPaths []string
+}
+
+// AppsHashes represent a repo state as appName -> version -> hash of the version content
+type AppsHashes map[string]map[string]string
+
+// resolve returns the hash of the nearest (<=) version of the application, empty if no version is available
+func (h AppsHashes) resolve(appName, targetVersion string) string {
+	versionsHashes := h[appName]
+	versions := make([]*version.Version, 0, len(versionsHashes))
+	for strVersion := range versionsHashes {
+		ver, err := version.NewVersion(strVersion)
+		if err == nil {
+			versions = append(versions, ver)
+		}
+	}
+	sort.Sort(sort.Reverse(version.Collection(versions)))
+	nearestVersion, err := FindNearestVersion(versions, targetVersion)
+	if err != nil {
+		return ""
+	}
+	return versionsHashes[nearestVersion.Original()]
+}
+
+// DiffFiles returns the sorted paths added, modified or removed between two path -> content hash maps
+func DiffFiles(oldFiles, newFiles map[string]string) []string {
+	result := make([]string, 0)
+	for flPath, newHash := range newFiles {
+		if oldHash, exist := oldFiles[flPath]; !exist || oldHash != newHash {
+			result = append(result, flPath)
+		}
+	}
+	for flPath := range oldFiles {
+		if _, exist := newFiles[flPath]; !exist {
+			result = append(result, flPath)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// DetectChanges compare two repo states and returns the added, updated and removed application versions
+//
+// the added and removed versions change the resolution of the requested versions,
+// their OldHash and NewHash are the hashes resolved before and after the change (i.e. the previous version content)
+func DetectChanges(oldApps, newApps AppsHashes) []Change {
+	changes := make([]Change, 0)
+	for appName, newVersions := range newApps {
+		for verName, newHash := range newVersions {
+			oldHash, exist := oldApps[appName][verName]
+			if !exist {
+				changes = append(changes, Change{
+					ApplicationVersion: ApplicationVersion{AppName: appName, AppVersion: verName},
+					Kind:               VersionAdded,
+					OldHash:            oldApps.resolve(appName, verName),
+					NewHash:            newHash,
+				})
+			} else if oldHash != newHash {
+				changes = append(changes, Change{
+					ApplicationVersion: ApplicationVersion{AppName: appName, AppVersion: verName},
+					Kind:               VersionUpdated,
+					OldHash:            oldHash,
+					NewHash:            newHash,
+				})
+			}
+		}
+	}
+	for appName, oldVersions := range oldApps {
+		for verName, oldHash := range oldVersions {
+			if _, exist := newApps[appName][verName]; !exist {
+				changes = append(changes, Change{
+					ApplicationVersion: ApplicationVersion{AppName: appName, AppVersion: verName},
+					Kind:               VersionRemoved,
+					OldHash:            oldHash,
+					NewHash:            newApps.resolve(appName, verName),
+				})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].AppName != changes[j].AppName {
+			return changes[i].AppName < changes[j].AppName
+		}
+		return changes[i].AppVersion < changes[j].AppVersion
+	})
+	return changes
+}
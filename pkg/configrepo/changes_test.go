@@ -0,0 +1,40 @@
+package configrepo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectChanges(t *testing.T) {
+	oldApps := AppsHashes{
+		"app1": {"1.0.0": "a100", "1.2.0": "a120", "2.0.0": "a200"},
+		"app2": {"1.0.0": "b100"},
+	}
+	newApps := AppsHashes{
+		"app1": {"1.0.0": "a100", "1.1.0": "a110", "2.0.0": "a200-2"},
+		"app3": {"0.1.0": "c010"},
+	}
+	changes := DetectChanges(oldApps, newApps)
+	assert.Equal(t, []Change{
+		{ApplicationVersion{AppName: "app1", AppVersion: "1.1.0"}, VersionAdded, "a100", "a110", nil},
+		{ApplicationVersion{AppName: "app1", AppVersion: "1.2.0"}, VersionRemoved, "a120", "a110", nil},
+		{ApplicationVersion{AppName: "app1", AppVersion: "2.0.0"}, VersionUpdated, "a200", "a200-2", nil},
+		{ApplicationVersion{AppName: "app2", AppVersion: "1.0.0"}, VersionRemoved, "b100", "", nil},
+		{ApplicationVersion{AppName: "app3", AppVersion: "0.1.0"}, VersionAdded, "", "c010", nil},
+	}, changes)
+	assert.Empty(t, DetectChanges(newApps, newApps))
+}
+
+func TestDiffFiles(t *testing.T) {
+	oldFiles := map[string]string{"config.yml": "h1", "dev/config.yml": "h2", "int/config.yml": "h3"}
+	newFiles := map[string]string{"config.yml": "h1", "dev/config.yml": "h2-2", "prod/config.yml": "h4"}
+	assert.Equal(t, []string{"dev/config.yml", "int/config.yml", "prod/config.yml"}, DiffFiles(oldFiles, newFiles))
+	assert.Empty(t, DiffFiles(oldFiles, oldFiles))
+	assert.Equal(t, []string{"config.yml", "dev/config.yml", "int/config.yml"}, DiffFiles(oldFiles, nil))
+}
+
+func TestChangeKind_String(t *testing.T) {
+	assert.Equal(t, "added", VersionAdded.String())
+	assert.Equal(t, "updated", VersionUpdated.String())
+	assert.Equal(t, "removed", VersionRemoved.String())
+}
@@ -0,0 +1,54 @@
+package compositeconfigrepo
+
+import (
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// StartFetchingEvery start the auto fetch of all the layers
+func (cr *CompositeConfigRepo) StartFetchingEvery(period time.Duration) error {
+	for _, layer := range cr.layers {
+		err := layer.StartFetchingEvery(period)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopFetching stop the auto fetch of all the layers
+func (cr *CompositeConfigRepo) StopFetching() {
+	for _, layer := range cr.layers {
+		layer.StopFetching()
+	}
+}
+
+// Fetch fetch all the layers, returns the first error
+func (cr *CompositeConfigRepo) Fetch() error {
+	var result error
+	for i, layer := range cr.layers {
+		err := layer.Fetch()
+		if err != nil {
+			logrus.Errorf("Error fetching layer %d:%s", i, err)
+			if result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+// GetLastFetch return the oldest last fetch date of the layers
+func (cr *CompositeConfigRepo) GetLastFetch() *time.Time {
+	var result *time.Time
+	for _, layer := range cr.layers {
+		lastFetch := layer.GetLastFetch()
+		if lastFetch == nil {
+			return nil
+		}
+		if result == nil || lastFetch.Before(*result) {
+			result = lastFetch
+		}
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package compositeconfigrepo
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+)
+
+// not found errors ordered by relevance
+var notFoundErrors = []error{
+	configrepo.ErrFileNotFound,
+	configrepo.ErrLabelNotFound,
+	configrepo.ErrVersionNotFound,
+	configrepo.ErrApplicationNotFound,
+	configrepo.ErrLabelNotSupported,
+}
+
+// GetFile retrieve a file from the first layer that contains it
+func (cr *CompositeConfigRepo) GetFile(targetApp *configrepo.ApplicationVersion, path string) (*configrepo.RepoFile, error) {
+	log := logrus.WithField("method", "GetFile").WithField("targetApp", targetApp).WithField("path", path)
+	layersErrors := make([]error, 0, len(cr.layers))
+	for i, layer := range cr.layers {
+		file, err := layer.GetFile(targetApp, path)
+		if err == nil {
+			log.Debugf("file found on layer %d", i)
+			return file, nil
+		}
+		if !isNotFound(err) {
+			log.Errorf("Error getting the file from layer %d:%s", i, err)
+			return nil, err
+		}
+		layersErrors = append(layersErrors, err)
+	}
+	return nil, mostRelevantError(layersErrors)
+}
+
+// GetSharedFile retrieve a shared file from the first layer that contains it, the layers without shared files are ignored
+func (cr *CompositeConfigRepo) GetSharedFile(targetApp *configrepo.ApplicationVersion, path string) (*configrepo.RepoFile, error) {
+	log := logrus.WithField("method", "GetSharedFile").WithField("targetApp", targetApp).WithField("path", path)
+	for i, layer := range cr.layers {
+		sharedLayer, isShared := layer.(configrepo.SharedRepo)
+		if !isShared {
+			continue
+		}
+		file, err := sharedLayer.GetSharedFile(targetApp, path)
+		if err == nil {
+			log.Debugf("shared file found on layer %d", i)
+			return file, nil
+		}
+		if !errors.Is(err, configrepo.ErrFileNotFound) {
+			log.Errorf("Error getting the shared file from layer %d:%s", i, err)
+			return nil, err
+		}
+	}
+	return nil, configrepo.ErrFileNotFound
+}
+
+func isNotFound(err error) bool {
+	for _, notFoundErr := range notFoundErrors {
+		if errors.Is(err, notFoundErr) {
+			return true
+		}
+	}
+	return false
+}
+
+func mostRelevantError(layersErrors []error) error {
+	for _, notFoundErr := range notFoundErrors {
+		for _, err := range layersErrors {
+			if errors.Is(err, notFoundErr) {
+				return err
+			}
+		}
+	}
+	return configrepo.ErrApplicationNotFound
+}
@@ -0,0 +1,53 @@
+package compositeconfigrepo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+func getConfig(t *testing.T, cfgRepo configrepo.Repo, appName, targetVersion, filePath string) map[string]interface{} {
+	cfgFl, err := cfgRepo.GetFile(configrepo.NewApplicationVersion(appName, targetVersion), filePath)
+	assert.NoError(t, err)
+	configContent := make(map[string]interface{})
+	assert.NoError(t, yaml.Unmarshal(cfgFl.Content, configContent))
+	return configContent
+}
+
+func TestCompositeConfigRepo_GetFile(t *testing.T) {
+	cfgRepo, _, _ := InitRepos(t)
+	tests := []struct {
+		name            string
+		appName         string
+		version         string
+		filePath        string
+		expectedLayer   string
+		expectedVersion interface{}
+	}{
+		{"team layer precedence", "app1", "1.0.0", "config.yml", "team", "1.0.0"},
+		{"team layer nearest version", "app1", "2.5.0", "config.yml", "team", "2.0.0"},
+		{"shared layer missing file", "app1", "1.0.0", "dev/config.yml", "shared", nil},
+		{"shared layer missing app", "app2", "1.0.0", "config.yml", "shared", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configContent := getConfig(t, cfgRepo, test.appName, test.version, test.filePath)
+			assert.Equal(t, test.expectedLayer, configContent["layer"])
+			assert.Equal(t, test.expectedVersion, configContent["ver"])
+		})
+	}
+}
+
+func TestCompositeConfigRepo_GetFile_NotFound(t *testing.T) {
+	cfgRepo, _, _ := InitRepos(t)
+	_, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "2.0.0"), "notExisting.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app2", "0.1.0"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrVersionNotFound))
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("not-exist-app", "1.0.0"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrApplicationNotFound))
+}
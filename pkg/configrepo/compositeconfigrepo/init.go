@@ -0,0 +1,90 @@
+package compositeconfigrepo
+
+import (
+	"errors"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"sort"
+)
+
+// ErrNoLayers returned creating a composite repo without layers
+var ErrNoLayers = errors.New("no layers defined")
+
+// CompositeConfigRepo represent a configuration repository composed by several layers
+//
+// the layers are ordered by precedence (the first one has the highest precedence),
+// every layer resolves independently the nearest (<=) application version
+// and the first layer that contains the requested file wins
+type CompositeConfigRepo struct {
+	layers []configrepo.Repo
+}
+
+// NewCompositeConfigRepo instantiate a new composite configuration repository
+func NewCompositeConfigRepo(layers ...configrepo.Repo) (configrepo.Repo, error) {
+	if len(layers) == 0 {
+		return nil, ErrNoLayers
+	}
+	logrus.Infof("New Composite Config Repo with %d layers", len(layers))
+	return &CompositeConfigRepo{layers: layers}, nil
+}
+
+// Layers returns the repo layers ordered by precedence
+func (cr *CompositeConfigRepo) Layers() []configrepo.Repo {
+	return cr.layers
+}
+
+// Init initialize all the layers
+func (cr *CompositeConfigRepo) Init() error {
+	for i, layer := range cr.layers {
+		err := layer.Init()
+		if err != nil {
+			logrus.Errorf("Error initializing layer %d:%s", i, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAppsVersions returns a appName-> list of version (union of all the layers)
+func (cr *CompositeConfigRepo) GetAppsVersions() map[string][]*version.Version {
+	result := make(map[string][]*version.Version)
+	alreadyAdded := make(map[string]map[string]bool)
+	for _, layer := range cr.layers {
+		for appName, versions := range layer.GetAppsVersions() {
+			if _, found := alreadyAdded[appName]; !found {
+				alreadyAdded[appName] = make(map[string]bool)
+				result[appName] = make([]*version.Version, 0)
+			}
+			for _, ver := range versions {
+				if !alreadyAdded[appName][ver.String()] {
+					alreadyAdded[appName][ver.String()] = true
+					result[appName] = append(result[appName], ver)
+				}
+			}
+		}
+	}
+	for _, versions := range result {
+		sort.Sort(version.Collection(versions))
+		utils.ReverseVersion(versions)
+	}
+	return result
+}
+
+// AddOnChangeHandler add a new change handler to all the layers
+func (cr *CompositeConfigRepo) AddOnChangeHandler(handler configrepo.OnChangeHandler) {
+	for _, layer := range cr.layers {
+		layer.AddOnChangeHandler(handler)
+	}
+}
+
+// MatchReference returns true if at least one layer matches the reference, the layers that don't follow any convention are ignored
+func (cr *CompositeConfigRepo) MatchReference(refName string) bool {
+	for _, layer := range cr.layers {
+		if matcher, ok := layer.(configrepo.ReferenceMatcher); ok && matcher.MatchReference(refName) {
+			return true
+		}
+	}
+	return false
+}
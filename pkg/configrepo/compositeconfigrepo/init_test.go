@@ -0,0 +1,55 @@
+package compositeconfigrepo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+// InitRepos create a composite repo with a team layer (app1 1.0.0,2.0.0) and a shared layer (app1 1.0.0,3.0.0 and app2 1.0.0)
+func InitRepos(t *testing.T) (configrepo.Repo, *memconfigrepo.MemConfigRepo, *memconfigrepo.MemConfigRepo) {
+	teamLayer := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, teamLayer.SetFile("app1", "1.0.0", "config.yml", []byte("layer: team\nver: 1.0.0")))
+	assert.NoError(t, teamLayer.SetFile("app1", "2.0.0", "config.yml", []byte("layer: team\nver: 2.0.0")))
+	sharedLayer := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, sharedLayer.SetFile("app1", "1.0.0", "config.yml", []byte("layer: shared\nver: 1.0.0")))
+	assert.NoError(t, sharedLayer.SetFile("app1", "1.0.0", "dev/config.yml", []byte("layer: shared\nenvironment: dev")))
+	assert.NoError(t, sharedLayer.SetFile("app1", "3.0.0", "config.yml", []byte("layer: shared\nver: 3.0.0")))
+	assert.NoError(t, sharedLayer.SetFile("app2", "1.0.0", "config.yml", []byte("layer: shared")))
+	cfgRepo, err := NewCompositeConfigRepo(teamLayer, sharedLayer)
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	return cfgRepo, teamLayer, sharedLayer
+}
+
+func TestNewCompositeConfigRepo_NoLayers(t *testing.T) {
+	cfgRepo, err := NewCompositeConfigRepo()
+	assert.Equal(t, ErrNoLayers, err)
+	assert.Nil(t, cfgRepo)
+}
+
+func TestCompositeConfigRepo_GetAppsVersions(t *testing.T) {
+	cfgRepo, _, _ := InitRepos(t)
+	appsVersions := cfgRepo.GetAppsVersions()
+	assert.Len(t, appsVersions, 2)
+	app1Versions := make([]string, 0)
+	for _, ver := range appsVersions["app1"] {
+		app1Versions = append(app1Versions, ver.String())
+	}
+	assert.Equal(t, []string{"3.0.0", "2.0.0", "1.0.0"}, app1Versions)
+	assert.Len(t, appsVersions["app2"], 1)
+}
+
+func TestCompositeConfigRepo_AddOnChangeHandler(t *testing.T) {
+	cfgRepo, teamLayer, sharedLayer := InitRepos(t)
+	changes := make([]configrepo.ApplicationVersion, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change.ApplicationVersion)
+	})
+	assert.NoError(t, teamLayer.SetFile("app1", "2.0.0", "config.yml", []byte("layer: team2")))
+	assert.NoError(t, sharedLayer.SetFile("app2", "1.0.0", "config.yml", []byte("layer: shared2")))
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.ElementsMatch(t, []configrepo.ApplicationVersion{{AppName: "app1", AppVersion: "2.0.0"}, {AppName: "app2", AppVersion: "1.0.0"}}, changes)
+	assert.NotNil(t, cfgRepo.GetLastFetch())
+}
@@ -7,3 +7,21 @@ var ErrFileNotFound = fmt.Errorf("file not found")
 
 // ErrApplicationNotFound returned if the requested application has not been found on the repo
 var ErrApplicationNotFound = fmt.Errorf("application not found")
+
+// ErrLabelNotFound returned if the requested label doesn't exist or doesn't belong to the application history
+var ErrLabelNotFound = fmt.Errorf("label not found")
+
+// ErrLabelNotSupported returned by the repos that cannot read a file at a specific label
+var ErrLabelNotSupported = fmt.Errorf("label not supported")
+
+// ErrVersionNotFound returned if no version (<=) of the requested application has been found on the repo
+var ErrVersionNotFound = fmt.Errorf("no version found")
+
+// ErrConflict returned if the application version has been changed since the version the write is based on
+var ErrConflict = fmt.Errorf("conflicting change")
+
+// ErrWriteNotSupported returned if the repo doesn't support writing the configurations
+var ErrWriteNotSupported = fmt.Errorf("write not supported")
+
+// ErrInvalidPath returned if the file path to write is empty or points to a folder
+var ErrInvalidPath = fmt.Errorf("invalid file path")
@@ -0,0 +1,107 @@
+package fsconfigrepo
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"time"
+)
+
+// StartFetchingEvery start scanning the filesystem for changes
+func (cr *FsConfigRepo) StartFetchingEvery(period time.Duration) error {
+	t := time.NewTicker(period)
+	go func() {
+		for {
+			select {
+			case t := <-t.C:
+				logrus.Debugf("Auto scan :%+s", t)
+				err := cr.Fetch()
+				if err != nil {
+					logrus.Errorf("Error scanning the filesystem:%s", err)
+				}
+			case <-cr.fetchCh:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// StopFetching stop the filesystem scan
+func (cr *FsConfigRepo) StopFetching() {
+	cr.fetchCh <- true
+}
+
+// Fetch scan the filesystem and notify the changes
+func (cr *FsConfigRepo) Fetch() error {
+	logrus.Debug("Fetch")
+	newApps, err := cr.loadApps()
+	if err != nil {
+		return err
+	}
+	cr.appsMutex.Lock()
+	changes := configrepo.DetectChanges(appsHashes(cr.Apps), appsHashes(newApps))
+	addChangedPaths(changes, cr.Apps, newApps)
+	cr.Apps = newApps
+	cr.appsMutex.Unlock()
+	if len(changes) > 0 {
+		cr.callChangeHandlers(changes)
+	} else {
+		logrus.Debugf("no changes detected")
+	}
+	cr.updateLastFetch()
+	return nil
+}
+
+func (cr *FsConfigRepo) updateLastFetch() {
+	cr.lastFetchMutex.Lock()
+	defer cr.lastFetchMutex.Unlock()
+	lastFetch := time.Now()
+	cr.lastFetch = &lastFetch
+}
+
+// GetLastFetch return the last scan date
+func (cr *FsConfigRepo) GetLastFetch() *time.Time {
+	cr.lastFetchMutex.Lock()
+	defer cr.lastFetchMutex.Unlock()
+	return cr.lastFetch
+}
+
+// appsHashes returns the folder hash of every application version
+func appsHashes(apps map[string]*app) configrepo.AppsHashes {
+	result := make(configrepo.AppsHashes)
+	for appName, app := range apps {
+		result[appName] = make(map[string]string)
+		for verName, verFolder := range app.Folders {
+			result[appName][verName] = verFolder.Hash
+		}
+	}
+	return result
+}
+
+// addChangedPaths set the files changed between the old and the new folder of every change
+func addChangedPaths(changes []configrepo.Change, oldApps, newApps map[string]*app) {
+	foldersFiles := make(map[string]map[string]string)
+	for _, apps := range []map[string]*app{oldApps, newApps} {
+		for _, app := range apps {
+			for _, verFolder := range app.Folders {
+				foldersFiles[verFolder.Hash] = verFolder.Files
+			}
+		}
+	}
+	for i := range changes {
+		changes[i].ChangedPaths = configrepo.DiffFiles(foldersFiles[changes[i].OldHash], foldersFiles[changes[i].NewHash])
+	}
+}
+
+func (cr *FsConfigRepo) callChangeHandlers(changes []configrepo.Change) {
+	logrus.Debugf("callChangeHandlers: %+v", changes)
+	cr.handlersMutex.Lock()
+	handlers := cr.changesHandlers
+	cr.handlersMutex.Unlock()
+	for _, chHandler := range handlers {
+		for _, change := range changes {
+			chHandler(change)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package fsconfigrepo
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFsConfigRepo_FetchingEvery(t *testing.T) {
+	cfgRepo, err := NewFsConfigRepo(InitRepo(t))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	assert.NoError(t, cfgRepo.StartFetchingEvery(300*time.Millisecond))
+	beforeStartFetching := time.Now()
+	time.Sleep(500 * time.Millisecond)
+	lastFetchTime := cfgRepo.GetLastFetch()
+	assert.NotNil(t, lastFetchTime)
+	assert.True(t, lastFetchTime.After(beforeStartFetching))
+	cfgRepo.StopFetching()
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, lastFetchTime.Equal(*cfgRepo.GetLastFetch()))
+}
+
+func TestFsConfigRepo_Fetch(t *testing.T) {
+	rootPath := InitRepo(t)
+	cfgRepo, err := NewFsConfigRepo(rootPath)
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+
+	var changesMutex sync.Mutex
+	changes := make([]configrepo.ApplicationVersion, 0)
+	var changedPaths []string
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changesMutex.Lock()
+		defer changesMutex.Unlock()
+		changes = append(changes, change.ApplicationVersion)
+		changedPaths = change.ChangedPaths
+	})
+
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Empty(t, changes)
+
+	prop3Val := uuid.New().String()
+	writeFile(t, rootPath, "app1", "1.0.0", "config.yml", "prop3: "+prop3Val)
+
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Equal(t, prop3Val, getConfigYml(t, cfgRepo, "app1", "1.0.0")["prop3"])
+	assert.Equal(t, []configrepo.ApplicationVersion{{AppName: "app1", AppVersion: "1.0.0"}}, changes)
+	assert.Equal(t, []string{"config.yml"}, changedPaths)
+}
+
+func TestFsConfigRepo_Fetch_NewApplication(t *testing.T) {
+	rootPath := InitRepo(t)
+	cfgRepo, err := NewFsConfigRepo(rootPath)
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+
+	var changedApp configrepo.ApplicationVersion
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changedApp = change.ApplicationVersion
+	})
+	appName := uuid.New().String()
+	writeFile(t, rootPath, "app2", "2.0.0", "config.yml", "appName: "+appName)
+
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Equal(t, appName, getConfigYml(t, cfgRepo, "app2", "2.0.0")["appName"])
+	assert.Equal(t, "app2", changedApp.AppName)
+	assert.Equal(t, "2.0.0", changedApp.AppVersion)
+}
@@ -0,0 +1,49 @@
+package fsconfigrepo
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// getNearestFolder retrieve the nearest (<=) application version folder available on the filesystem
+func (cr *FsConfigRepo) getNearestFolder(targetApp *configrepo.ApplicationVersion) (*folder, error) {
+	cr.appsMutex.RLock()
+	defer cr.appsMutex.RUnlock()
+	app, appFound := cr.Apps[targetApp.AppName]
+	if !appFound {
+		return nil, configrepo.ErrApplicationNotFound
+	}
+	nearestVersion, err := configrepo.FindNearestVersion(app.Versions, targetApp.AppVersion)
+	if err != nil {
+		return nil, err
+	}
+	return app.Folders[nearestVersion.Original()], nil
+}
+
+// GetFile retrieve a file from the filesystem
+func (cr *FsConfigRepo) GetFile(targetApp *configrepo.ApplicationVersion, filePath string) (*configrepo.RepoFile, error) {
+	log := logrus.WithField("method", "GetFile").WithField("targetApp", targetApp).WithField("path", filePath)
+	if targetApp.Label != "" {
+		// the folders have no history
+		return nil, configrepo.ErrLabelNotSupported
+	}
+	versionFolder, err := cr.getNearestFolder(targetApp)
+	if err != nil {
+		return nil, err
+	}
+	// cleaning the path as absolute to avoid to escape from the version folder
+	flPath := filepath.Join(versionFolder.Path, filepath.FromSlash(path.Clean("/"+filePath)))
+	content, err := ioutil.ReadFile(flPath)
+	if err != nil {
+		log.Errorf("Error reading the file:%s", err)
+		if os.IsNotExist(err) {
+			return nil, configrepo.ErrFileNotFound
+		}
+		return nil, err
+	}
+	return &configrepo.RepoFile{Version: versionFolder.Hash, Content: content}, nil
+}
@@ -0,0 +1,61 @@
+package fsconfigrepo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+func getConfigYml(t *testing.T, cfgRepo configrepo.Repo, appName, targetVersion string) map[string]interface{} {
+	app := configrepo.NewApplicationVersion(appName, targetVersion)
+	cfgFl, err := cfgRepo.GetFile(app, "config.yml")
+	assert.NoError(t, err)
+	configContent := make(map[string]interface{})
+	assert.NoError(t, yaml.Unmarshal(cfgFl.Content, configContent))
+	return configContent
+}
+
+func TestFsConfigRepo_GetFile(t *testing.T) {
+	cfgRepo, err := NewFsConfigRepo(InitRepo(t))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	tests := []struct {
+		name            string
+		version         string
+		expectedVersion string
+	}{
+		{"version 1.0.0", "1.0.0", "1.0.0"},
+		{"version 1.0.1", "1.0.1", "1.0.1"},
+		{"version 5.0.0", "5.0.0", "1.0.1"},
+		{"version 10.0.0", "10.0.0", "6.0.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configContent := getConfigYml(t, cfgRepo, "app1", test.version)
+			assert.Equal(t, "dev", configContent["environment"])
+			assert.Equal(t, test.expectedVersion, configContent["ver"])
+		})
+	}
+}
+
+func TestFsConfigRepo_GetFile_NotFound(t *testing.T) {
+	cfgRepo, err := NewFsConfigRepo(InitRepo(t))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("not-exist-app", "v1.0.0"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrApplicationNotFound))
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v0.0.1"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrVersionNotFound))
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "notExisting.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "../1.0.1/config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", "app1/v1.0.0"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrLabelNotSupported))
+}
@@ -0,0 +1,93 @@
+package fsconfigrepo
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"os"
+	"sync"
+	"time"
+)
+
+type app struct {
+	Name     string
+	Folders  map[string]*folder
+	Versions []*version.Version
+}
+
+type folder struct {
+	Path string
+	Hash string
+	// Files the content hash of every file of the folder (relative path -> hash)
+	Files map[string]string
+}
+
+func newApp(name string) *app {
+	return &app{name, make(map[string]*folder), make([]*version.Version, 0)}
+}
+
+// FsConfigRepo represent a configuration repository stored on a plain directory tree (<root>/<appName>/<version>/...)
+//
+// the files are always read from the disk, the changes are detected (and notified) on every Fetch
+type FsConfigRepo struct {
+	rootPath        string
+	Apps            map[string]*app
+	appsMutex       sync.RWMutex
+	fetchCh         chan bool
+	lastFetch       *time.Time
+	lastFetchMutex  sync.Mutex
+	changesHandlers []configrepo.OnChangeHandler
+	handlersMutex   sync.Mutex
+}
+
+// NewFsConfigRepo instantiate a new filesystem configuration repository
+func NewFsConfigRepo(rootPath string) (configrepo.Repo, error) {
+	log := logrus.WithField("rootPath", rootPath)
+	log.Info("New Fs Config Repo")
+	info, err := os.Stat(rootPath)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", rootPath)
+	}
+	return &FsConfigRepo{
+		rootPath:        rootPath,
+		Apps:            make(map[string]*app),
+		fetchCh:         make(chan bool),
+		lastFetch:       nil,
+		changesHandlers: make([]configrepo.OnChangeHandler, 0),
+	}, nil
+}
+
+// Init initialize the filesystem repository
+func (cr *FsConfigRepo) Init() error {
+	apps, err := cr.loadApps()
+	if err != nil {
+		return err
+	}
+	cr.appsMutex.Lock()
+	defer cr.appsMutex.Unlock()
+	cr.Apps = apps
+	return nil
+}
+
+// GetAppsVersions returns a appName-> list of version
+func (cr *FsConfigRepo) GetAppsVersions() map[string][]*version.Version {
+	cr.appsMutex.RLock()
+	defer cr.appsMutex.RUnlock()
+	result := make(map[string][]*version.Version)
+	for appName, app := range cr.Apps {
+		result[appName] = app.Versions
+	}
+	return result
+}
+
+// AddOnChangeHandler add a new change handler to the filesystem repo
+func (cr *FsConfigRepo) AddOnChangeHandler(handler configrepo.OnChangeHandler) {
+	cr.handlersMutex.Lock()
+	defer cr.handlersMutex.Unlock()
+	cr.changesHandlers = append(cr.changesHandlers, handler)
+}
@@ -0,0 +1,38 @@
+package fsconfigrepo
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testBasicPath = fmt.Sprintf("%s/vecosy_fs_tests", os.TempDir())
+
+func TestMain(m *testing.M) {
+	logrus.SetLevel(logrus.DebugLevel)
+	retCode := m.Run()
+	_ = os.RemoveAll(testBasicPath)
+	os.Exit(retCode)
+}
+
+// InitRepo create a new filesystem repo with app1 (v1.0.0,v1.0.1,v6.0.0)
+func InitRepo(t *testing.T) string {
+	rootPath := filepath.Join(testBasicPath, uuid.New().String())
+	for _, ver := range []string{"1.0.0", "1.0.1", "6.0.0"} {
+		writeFile(t, rootPath, "app1", ver, "config.yml", fmt.Sprintf("environment: dev\nver: %s", ver))
+		writeFile(t, rootPath, "app1", ver, "dev/config.yml", "environment: dev")
+	}
+	writeFile(t, rootPath, "app1", "notAVersion", "config.yml", "environment: dev")
+	return rootPath
+}
+
+func writeFile(t *testing.T, rootPath, appName, appVersion, filePath, content string) {
+	flPath := filepath.Join(rootPath, appName, appVersion, filepath.FromSlash(filePath))
+	assert.NoError(t, os.MkdirAll(filepath.Dir(flPath), 0755))
+	assert.NoError(t, ioutil.WriteFile(flPath, []byte(content), 0644))
+}
@@ -0,0 +1,126 @@
+package fsconfigrepo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func (cr *FsConfigRepo) loadApps() (map[string]*app, error) {
+	newApps := make(map[string]*app)
+	appFolders, err := ioutil.ReadDir(cr.rootPath)
+	if err != nil {
+		logrus.Errorf("Error reading the root folder:%s", err)
+		return nil, err
+	}
+	for _, appFolder := range appFolders {
+		if !appFolder.IsDir() {
+			continue
+		}
+		err = cr.loadAppVersions(appFolder.Name(), newApps)
+		if err != nil {
+			logrus.Errorf("Error loading app:%s err:%s", appFolder.Name(), err)
+			return nil, err
+		}
+	}
+
+	for appName, app := range newApps {
+		logrus.Debugf("sorting app:%s versions", appName)
+		sort.Sort(version.Collection(app.Versions))
+		utils.ReverseVersion(app.Versions)
+		logrus.Infof("app:%s Sorted Versions:%+v", appName, app.Versions)
+	}
+	return newApps, nil
+}
+
+func (cr *FsConfigRepo) loadAppVersions(appName string, apps map[string]*app) error {
+	appPath := filepath.Join(cr.rootPath, appName)
+	versionFolders, err := ioutil.ReadDir(appPath)
+	if err != nil {
+		return err
+	}
+	for _, versionFolder := range versionFolders {
+		if !versionFolder.IsDir() {
+			continue
+		}
+		appStrVersion := versionFolder.Name()
+		appVersion, err := version.NewVersion(appStrVersion)
+		if err != nil {
+			logrus.Warnf("Invalid application version:%s err:%s", appStrVersion, err)
+			continue
+		}
+		if _, appFound := apps[appName]; !appFound {
+			apps[appName] = newApp(appName)
+		}
+		if duplicated := findVersion(apps[appName].Versions, appVersion); duplicated != nil {
+			// the folders are read in name order, the first one is kept (i.e. 1.0.0 wins over v1.0.0)
+			logrus.Warnf("Ignoring the app:%s folder:%s, same version of the folder:%s", appName, appStrVersion, duplicated.Original())
+			continue
+		}
+		versionPath := filepath.Join(appPath, appStrVersion)
+		hash, files, err := hashFolder(versionPath)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("appName:%s appVersion:%s hash:%s", appName, appStrVersion, hash)
+		apps[appName].Versions = append(apps[appName].Versions, appVersion)
+		apps[appName].Folders[appStrVersion] = &folder{Path: versionPath, Hash: hash, Files: files}
+	}
+	return nil
+}
+
+// findVersion returns the version equal to the target one (whatever its original string is), nil if none
+func findVersion(versions []*version.Version, target *version.Version) *version.Version {
+	for _, ver := range versions {
+		if ver.Equal(target) {
+			return ver
+		}
+	}
+	return nil
+}
+
+// hashFolder calculate a hash of the folder paths and contents, used as file version and to detect changes
+//
+// every relative path is NUL terminated and followed by the (fixed length) content hash so different folders never
+// produce the same input. The content hash of every file (relative path -> hash) is returned as well to detect the changed files
+func hashFolder(folderPath string) (string, map[string]string, error) {
+	hash := sha1.New()
+	files := make(map[string]string)
+	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(folderPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		fl, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer fl.Close()
+		flHash := sha1.New()
+		if _, err = io.Copy(flHash, fl); err != nil {
+			return err
+		}
+		_, _ = io.WriteString(hash, relPath+"\x00")
+		_, _ = hash.Write(flHash.Sum(nil))
+		files[relPath] = hex.EncodeToString(flHash.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), files, nil
+}
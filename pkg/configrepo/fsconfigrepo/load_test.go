@@ -0,0 +1,69 @@
+package fsconfigrepo
+
+import (
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFsConfigRepo(t *testing.T) {
+	rootPath := InitRepo(t)
+	cfgRepo, err := NewFsConfigRepo(rootPath)
+	assert.NoError(t, err)
+	assert.NotNil(t, cfgRepo)
+	assert.NoError(t, cfgRepo.Init())
+	assert.Contains(t, cfgRepo.GetAppsVersions(), "app1")
+	v100, err := version.NewVersion("1.0.0")
+	assert.NoError(t, err)
+	v101, err := version.NewVersion("1.0.1")
+	assert.NoError(t, err)
+	v600, err := version.NewVersion("6.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 3)
+	assert.Equal(t, cfgRepo.GetAppsVersions()["app1"][0], v600)
+	assert.Equal(t, cfgRepo.GetAppsVersions()["app1"][1], v101)
+	assert.Equal(t, cfgRepo.GetAppsVersions()["app1"][2], v100)
+}
+
+func TestNewFsConfigRepo_DuplicatedVersion(t *testing.T) {
+	rootPath := InitRepo(t)
+	writeFile(t, rootPath, "app1", "v1.0.0", "config.yml", "environment: dev\nver: v1.0.0")
+	cfgRepo, err := NewFsConfigRepo(rootPath)
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	// the folders with the same version are loaded once, the first one in name order is kept
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 3)
+	cfgFile, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "environment: dev\nver: 1.0.0", string(cfgFile.Content))
+}
+
+func TestNewFsConfigRepo_NotExistingFolder(t *testing.T) {
+	cfgRepo, err := NewFsConfigRepo(testBasicPath + "/notExistingFolder")
+	assert.Error(t, err)
+	assert.Nil(t, cfgRepo)
+}
+
+func TestHashFolder(t *testing.T) {
+	hashOf := func(files map[string]string) string {
+		folderPath := filepath.Join(testBasicPath, uuid.New().String())
+		for flPath, content := range files {
+			assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(folderPath, flPath)), os.ModePerm))
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(folderPath, flPath), []byte(content), 0644))
+		}
+		hash, _, err := hashFolder(folderPath)
+		assert.NoError(t, err)
+		return hash
+	}
+	// moving bytes between a path and its content changes the hash
+	assert.NotEqual(t, hashOf(map[string]string{"a.yml": "b: c"}), hashOf(map[string]string{"a.ymlb": ": c"}))
+	assert.NotEqual(t,
+		hashOf(map[string]string{"a.yml": "b.yml", "c.yml": ""}),
+		hashOf(map[string]string{"a.yml": "", "b.ymlc.yml": ""}))
+	assert.Equal(t, hashOf(map[string]string{"a.yml": "b: c"}), hashOf(map[string]string{"a.yml": "b: c"}))
+}
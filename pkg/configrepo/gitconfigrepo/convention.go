@@ -0,0 +1,105 @@
+package gitconfigrepo
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"regexp"
+	"strings"
+)
+
+const (
+	appGroup     = "app"
+	versionGroup = "version"
+)
+
+// DefaultRefPattern the default `<anything>/<app>/<version>` reference pattern
+const DefaultRefPattern = `.*/(?P<app>[a-z|A-Z|0-9|\-|.]*)/(?P<version>[a-z|A-Z|0-9|\-|.]*)`
+
+// RefConvention represent the mapping between the git references and the application versions
+//
+// every pattern has to define the named groups `app` and `version`
+type RefConvention struct {
+	LocalBranch  *regexp.Regexp
+	RemoteBranch *regexp.Regexp
+	Tag          *regexp.Regexp
+	// VersionPrefix will be removed from the matched version (i.e. `release-` for `config/app1/release-1.2`)
+	VersionPrefix string
+}
+
+// DefaultRefConvention use the DefaultRefPattern for all the reference types
+var DefaultRefConvention = &RefConvention{
+	LocalBranch:  regexp.MustCompile(DefaultRefPattern),
+	RemoteBranch: regexp.MustCompile(DefaultRefPattern),
+	Tag:          regexp.MustCompile(DefaultRefPattern),
+}
+
+// NewRefConvention create a new reference convention, an empty pattern will be replaced by the DefaultRefPattern
+func NewRefConvention(localBranchPattern, remoteBranchPattern, tagPattern, versionPrefix string) (*RefConvention, error) {
+	var err error
+	convention := &RefConvention{VersionPrefix: versionPrefix}
+	convention.LocalBranch, err = compileRefPattern(localBranchPattern)
+	if err != nil {
+		return nil, err
+	}
+	convention.RemoteBranch, err = compileRefPattern(remoteBranchPattern)
+	if err != nil {
+		return nil, err
+	}
+	convention.Tag, err = compileRefPattern(tagPattern)
+	if err != nil {
+		return nil, err
+	}
+	return convention, nil
+}
+
+func compileRefPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		pattern = DefaultRefPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]bool)
+	for _, group := range re.SubexpNames() {
+		groups[group] = true
+	}
+	if !groups[appGroup] || !groups[versionGroup] {
+		return nil, fmt.Errorf("%w:%s", ErrInvalidRefPattern, pattern)
+	}
+	return re, nil
+}
+
+// Parse returns the application version identified by the reference name
+func (c *RefConvention) Parse(refName plumbing.ReferenceName) (*configrepo.ApplicationVersion, error) {
+	var re *regexp.Regexp
+	switch {
+	case refName.IsBranch():
+		re = c.LocalBranch
+	case refName.IsRemote():
+		re = c.RemoteBranch
+	case refName.IsTag():
+		re = c.Tag
+	default:
+		return nil, fmt.Errorf("%w:%s", ErrReferenceNotMatching, refName)
+	}
+	matches := re.FindStringSubmatch(refName.String())
+	if matches == nil {
+		return nil, fmt.Errorf("%w:%s", ErrReferenceNotMatching, refName)
+	}
+	app := &configrepo.ApplicationVersion{}
+	for i, group := range re.SubexpNames() {
+		switch group {
+		case appGroup:
+			app.AppName = matches[i]
+		case versionGroup:
+			app.AppVersion = strings.TrimPrefix(matches[i], c.VersionPrefix)
+		}
+	}
+	if _, err := version.NewVersion(app.AppVersion); app.AppName == "" || err != nil {
+		return nil, fmt.Errorf("%w:%s invalid application version %+v", ErrReferenceNotMatching, refName, app)
+	}
+	return app, nil
+}
@@ -0,0 +1,91 @@
+package gitconfigrepo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefConvention_Parse(t *testing.T) {
+	convention, err := NewRefConvention(
+		`refs/heads/config/(?P<app>[a-zA-Z0-9\-]+)/(?P<version>[a-zA-Z0-9\-.]+)`,
+		`refs/remotes/origin/config/(?P<app>[a-zA-Z0-9\-]+)/(?P<version>[a-zA-Z0-9\-.]+)`,
+		`refs/tags/(?P<app>[a-zA-Z0-9]+)-(?P<version>v[0-9.]+)`,
+		"release-",
+	)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		refName  string
+		expected *configrepo.ApplicationVersion
+	}{
+		{"refs/tags/app1-v1.2.0", configrepo.NewApplicationVersion("app1", "v1.2.0")},
+		{"refs/heads/config/app1/release-1.2", configrepo.NewApplicationVersion("app1", "1.2")},
+		{"refs/remotes/origin/config/app-2/release-2.0.1", configrepo.NewApplicationVersion("app-2", "2.0.1")},
+		{"refs/heads/app1/1.0.0", nil},
+		{"refs/heads/config/app1/notAVersion", nil},
+		{"refs/tags/app1/v1.0.1", nil},
+		{"refs/notes/app1/v1.0.1", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.refName, func(t *testing.T) {
+			app, err := convention.Parse(plumbing.ReferenceName(test.refName))
+			if test.expected == nil {
+				assert.True(t, errors.Is(err, ErrReferenceNotMatching))
+				assert.Nil(t, app)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, app)
+			}
+		})
+	}
+}
+
+func TestNewRefConvention_InvalidPattern(t *testing.T) {
+	_, err := NewRefConvention(`refs/heads/(?P<app>.*)/(.*)`, "", "", "")
+	assert.True(t, errors.Is(err, ErrInvalidRefPattern))
+	_, err = NewRefConvention("", "", `refs/tags/(?P<app`, "")
+	assert.Error(t, err)
+	convention, err := NewRefConvention("", "", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultRefPattern, convention.Tag.String())
+}
+
+func TestConfigRepo_WithRefConvention(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	convention, err := NewRefConvention("", "", `refs/tags/(?P<app>[a-zA-Z0-9]+)-(?P<version>v[0-9.]+)`, "")
+	assert.NoError(t, err)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithRefConvention(convention))
+	assert.NoError(t, err)
+	gitRepo := cfgRepo.(*GitConfigRepo)
+
+	errorsMutex := sync.Mutex{}
+	receivedErrors := make([]error, 0)
+	gitRepo.AddErrorListener(func(err error) {
+		errorsMutex.Lock()
+		defer errorsMutex.Unlock()
+		receivedErrors = append(receivedErrors, err)
+	})
+	assert.NoError(t, cfgRepo.Init())
+
+	// the refs/tags/app1/v1.0.1 tag doesn't follow the convention anymore
+	branch, err := gitRepo.GetNearestBranch(configrepo.NewApplicationVersion("app1", "v5.0.0"))
+	assert.NoError(t, err)
+	assert.Contains(t, branch.Name().String(), "app1/v1.0.0")
+	assert.True(t, gitRepo.MatchReference("refs/heads/app1/v1.0.0"))
+	assert.False(t, gitRepo.MatchReference("refs/tags/app1/v1.0.1"))
+	assert.True(t, gitRepo.MatchReference("refs/tags/app1-v1.0.1"))
+
+	assert.Eventually(t, func() bool {
+		errorsMutex.Lock()
+		defer errorsMutex.Unlock()
+		return len(receivedErrors) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, errors.Is(receivedErrors[0], ErrReferenceNotMatching))
+	assert.Contains(t, receivedErrors[0].Error(), "refs/tags/app1/v1.0.1")
+}
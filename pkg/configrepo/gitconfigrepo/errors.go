@@ -1,5 +1,16 @@
 package gitconfigrepo
 
+import "errors"
+
+// ErrReferenceNotMatching reported (through the error listeners) for every reference that doesn't follow the RefConvention
+var ErrReferenceNotMatching = errors.New("reference not matching the convention")
+
+// ErrInvalidRefPattern returned if a reference pattern doesn't define the `app` and `version` named groups
+var ErrInvalidRefPattern = errors.New("invalid reference pattern, the named groups app and version are mandatory")
+
+// ErrUntrustedCommit reported (through the error listeners) for every application version head not signed by a trusted key
+var ErrUntrustedCommit = errors.New("untrusted commit")
+
 func (cr *GitConfigRepo) pushError(err error) {
 	if err != nil {
 		cr.errorsCh <- err
@@ -8,6 +19,8 @@ func (cr *GitConfigRepo) pushError(err error) {
 
 // AddErrorListener add an error handler to the git repo
 func (cr *GitConfigRepo) AddErrorListener(fn ErrorHandlerFn) {
+	cr.errorHandlersMutex.Lock()
+	defer cr.errorHandlersMutex.Unlock()
 	cr.errorHandlers = append(cr.errorHandlers, fn)
 }
 
@@ -15,7 +28,11 @@ func (cr *GitConfigRepo) errorHandlerManager() {
 	go func() {
 		for {
 			err := <-cr.errorsCh
-			for _, errFn := range cr.errorHandlers {
+			cr.errorHandlersMutex.RLock()
+			errorHandlers := make([]ErrorHandlerFn, len(cr.errorHandlers))
+			copy(errorHandlers, cr.errorHandlers)
+			cr.errorHandlersMutex.RUnlock()
+			for _, errFn := range errorHandlers {
 				errFn(err)
 			}
 		}
@@ -6,6 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"strings"
 	"time"
 )
 
@@ -33,37 +35,154 @@ func (cr *GitConfigRepo) StopFetching() {
 }
 
 // Fetch fetch from the remote git repo
+//
+// the remote references are listed first: the objects are fetched only if they differ from the local ones
+// and the remote branches (and the tags) removed from the remote repo are pruned (see pruneReferences).
+// Concurrent fetches are serialized, the readers keep using the previous snapshot until the new one is ready
 func (cr *GitConfigRepo) Fetch() error {
 	logrus.Debug("Fetch")
-	if cr.cloneOpts != nil {
+	cr.fetchMutex.Lock()
+	defer cr.fetchMutex.Unlock()
+	if cr.cloneOpts == nil {
+		return fmt.Errorf("cannot pull:no remote information found")
+	}
+	remote, err := cr.repo.Remote(cr.remoteName())
+	if err != nil {
+		return err
+	}
+	remoteRefs, err := remote.List(&git.ListOptions{Auth: cr.cloneOpts.Auth})
+	if err != nil {
+		logrus.Errorf("Error listing the remote references :%s", err)
+		return err
+	}
+	advertised := cr.advertisedReferences(remoteRefs)
+	pruned, err := cr.pruneReferences(advertised)
+	if err != nil {
+		logrus.Errorf("Error pruning the references :%s", err)
+		return err
+	}
+	fetched := false
+	if !cr.isUpToDate(advertised) {
 		fetchOpts := &git.FetchOptions{Auth: cr.cloneOpts.Auth, Force: true, Tags: git.AllTags}
-		err := cr.repo.Fetch(fetchOpts)
-		if err != nil {
-			if !errors.Is(err, git.NoErrAlreadyUpToDate) {
-				logrus.Errorf("Error fetching :%s", err)
-				return err
-			}
-			logrus.Debug("already up to date")
-		} else {
-			newApps, err := cr.loadApps()
-			if err != nil {
-				return err
-			}
-			changes := detectChanges(cr.Apps, newApps)
-			if len(changes) > 0 {
-				cr.Apps = newApps
-				cr.callChangeHandlers(changes)
-			} else {
-				logrus.Debugf("no changes detected")
-			}
+		err = cr.repo.Fetch(fetchOpts)
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			logrus.Errorf("Error fetching :%s", err)
+			return err
 		}
+		fetched = err == nil
+	}
+	cr.recordRemoteTags(advertised)
+	if !fetched && !pruned {
+		logrus.Debug("already up to date")
 	} else {
-		return fmt.Errorf("cannot pull:no remote information found")
+		err = cr.reload()
+		if err != nil {
+			return err
+		}
 	}
 	cr.updateLastFetch()
 	return nil
 }
 
+// reload replace the current snapshot and notify the changes, the caller has to hold the fetchMutex
+func (cr *GitConfigRepo) reload() error {
+	newSnapshot, err := cr.newSnapshot()
+	if err != nil {
+		return err
+	}
+	changes := configrepo.DetectChanges(appsHashes(cr.snapshot().apps), appsHashes(newSnapshot.apps))
+	changes = append(changes, sharedChanges(cr.snapshot(), newSnapshot)...)
+	if len(changes) > 0 {
+		snap, err := newSnapshot.reader()
+		if err != nil {
+			return err
+		}
+		snap.addChangedPaths(changes)
+		snap.close()
+		cr.current.Store(newSnapshot)
+		cr.callChangeHandlers(changes)
+	} else {
+		logrus.Debugf("no changes detected")
+	}
+	return nil
+}
+
+// advertisedReferences returns the hash of the remote branches (as local remote references, i.e. refs/remotes/origin/app1/v1.0.0)
+// and of the tags advertised by the remote repo
+func (cr *GitConfigRepo) advertisedReferences(remoteRefs []*plumbing.Reference) map[plumbing.ReferenceName]plumbing.Hash {
+	advertised := make(map[plumbing.ReferenceName]plumbing.Hash)
+	for _, ref := range remoteRefs {
+		if ref.Type() != plumbing.HashReference {
+			continue
+		}
+		switch {
+		case ref.Name().IsBranch():
+			advertised[plumbing.NewRemoteReferenceName(cr.remoteName(), ref.Name().Short())] = ref.Hash()
+		case ref.Name().IsTag():
+			advertised[ref.Name()] = ref.Hash()
+		}
+	}
+	return advertised
+}
+
+// isUpToDate returns true if every advertised reference is already present locally with the same hash
+func (cr *GitConfigRepo) isUpToDate(advertised map[plumbing.ReferenceName]plumbing.Hash) bool {
+	for name, hash := range advertised {
+		ref, err := cr.repo.Storer.Reference(name)
+		if err != nil || ref.Hash() != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneReferences removes the remote branches (refs/remotes/<remote>/...) and the tags fetched from the remote repo
+// that are not advertised anymore, the local branches and the local tags are left untouched.
+//
+// a tag is considered fetched from the remote repo if it has been advertised by the previous listing (or cloned)
+// and it still points to the same object
+func (cr *GitConfigRepo) pruneReferences(advertised map[plumbing.ReferenceName]plumbing.Hash) (bool, error) {
+	remotePrefix := fmt.Sprintf("refs/remotes/%s/", cr.remoteName())
+	staleRefs := make([]plumbing.ReferenceName, 0)
+	refs, err := cr.repo.References()
+	if err != nil {
+		return false, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if _, found := advertised[name]; found || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		if strings.HasPrefix(name.String(), remotePrefix) {
+			staleRefs = append(staleRefs, name)
+		} else if remoteHash, fromRemote := cr.remoteTags[name]; fromRemote && remoteHash == ref.Hash() {
+			staleRefs = append(staleRefs, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, name := range staleRefs {
+		logrus.Infof("removing the reference %s", name)
+		err = cr.repo.Storer.RemoveReference(name)
+		if err != nil {
+			return false, err
+		}
+	}
+	return len(staleRefs) > 0, nil
+}
+
+// recordRemoteTags keep the tags advertised by the remote repo (see pruneReferences)
+func (cr *GitConfigRepo) recordRemoteTags(advertised map[plumbing.ReferenceName]plumbing.Hash) {
+	cr.remoteTags = make(map[plumbing.ReferenceName]plumbing.Hash)
+	for name, hash := range advertised {
+		if name.IsTag() {
+			cr.remoteTags[name] = hash
+		}
+	}
+}
+
 func (cr *GitConfigRepo) updateLastFetch() {
 	cr.lastFetchMutex.Lock()
 	defer cr.lastFetchMutex.Unlock()
@@ -78,30 +197,25 @@ func (cr *GitConfigRepo) GetLastFetch() *time.Time {
 	return cr.lastFetch
 }
 
-func detectChanges(oldApps, newApps map[string]*app) []configrepo.ApplicationVersion {
-	changes := make([]configrepo.ApplicationVersion, 0)
-	for newAppName, newApp := range newApps {
-		if oldApp, alreadyExist := oldApps[newAppName]; alreadyExist {
-			// checking references
-			for verName, verRef := range newApp.Branches {
-				if oldVer, oldVerExist := oldApp.Branches[verName]; !oldVerExist || oldVer.Hash().String() != verRef.Hash().String() {
-					logrus.Debugf("new version or different branch hash detected oldVersionExist version:%s", verName)
-					changes = append(changes, configrepo.ApplicationVersion{AppName: newAppName, AppVersion: verName})
-				}
-			}
-		} else {
-			logrus.Infof("new application detected:%s", newAppName)
-			for _, newAppVersion := range newApp.Versions {
-				changes = append(changes, configrepo.ApplicationVersion{AppName: newAppName, AppVersion: newAppVersion.String()})
-			}
+// appsHashes returns the commit hash of every application version
+func appsHashes(apps map[string]*app) configrepo.AppsHashes {
+	result := make(configrepo.AppsHashes)
+	for appName, app := range apps {
+		result[appName] = make(map[string]string)
+		for verName, verRef := range app.Branches {
+			result[appName][verName] = verRef.Hash().String()
 		}
 	}
-	return changes
+	return result
 }
 
-func (cr *GitConfigRepo) callChangeHandlers(changes []configrepo.ApplicationVersion) {
+func (cr *GitConfigRepo) callChangeHandlers(changes []configrepo.Change) {
 	logrus.Debugf("callChangeHandlers: %+v", changes)
-	for _, chHandler := range cr.changesHandlers {
+	cr.handlersMutex.RLock()
+	handlers := make([]configrepo.OnChangeHandler, len(cr.changesHandlers))
+	copy(handlers, cr.changesHandlers)
+	cr.handlersMutex.RUnlock()
+	for _, chHandler := range handlers {
 		for _, change := range changes {
 			chHandler(change)
 		}
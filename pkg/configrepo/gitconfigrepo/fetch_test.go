@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"sync"
 	"testing"
 	"time"
 )
@@ -37,8 +39,10 @@ func TestConfigRepo_Fetch(t *testing.T) {
 	assert.NoError(t, cfgRepo.Init())
 
 	var changedApp configrepo.ApplicationVersion
-	cfgRepo.AddOnChangeHandler(func(changedApplication configrepo.ApplicationVersion) {
-		changedApp = changedApplication
+	var changedPaths []string
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changedApp = change.ApplicationVersion
+		changedPaths = change.ChangedPaths
 	})
 
 	prop3Val := uuid.New().String()
@@ -53,7 +57,7 @@ func TestConfigRepo_Fetch(t *testing.T) {
 	assert.Equal(t, prop3Val, configContent["prop3"])
 	assert.Equal(t, "app1", changedApp.AppName)
 	assert.Equal(t, "v1.0.0", changedApp.AppVersion)
-
+	assert.Equal(t, []string{"config.yml"}, changedPaths)
 }
 
 func TestConfigRepo_Fetch_NewVersion(t *testing.T) {
@@ -90,3 +94,103 @@ func TestConfigRepo_Fetch_NewApplication(t *testing.T) {
 	configContent := getConfigYml(t, cfgRepo, "app2", "v2.0.0")
 	assert.Equal(t, appName, configContent["appName"])
 }
+
+func TestConfigRepo_ConcurrentFetchAndRead(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	gitRepo := cfgRepo.(*GitConfigRepo)
+
+	stop := make(chan bool)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					fl, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+					assert.NoError(t, err)
+					assert.NotEmpty(t, fl.Content)
+					_, err = gitRepo.GetNearestBranch(configrepo.NewApplicationVersion("app1", "v5.0.0"))
+					assert.NoError(t, err)
+					assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 3)
+					cfgRepo.GetLastFetch()
+					cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {})
+					gitRepo.AddErrorListener(func(err error) {})
+				}
+			}
+		}()
+	}
+
+	assert.NoError(t, cfgRepo.Fetch())
+	prop3Val := uuid.New().String()
+	editAndPush(t, remoteRepo, "app1", "v1.0.0", "app1", "v1.0.0", "config.yml", "added prop3", []byte(fmt.Sprintf("prop3: %s", prop3Val)))
+	fetchWg := sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		fetchWg.Add(1)
+		go func() {
+			defer fetchWg.Done()
+			assert.NoError(t, cfgRepo.Fetch())
+		}()
+	}
+	fetchWg.Wait()
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, prop3Val, getConfigYml(t, cfgRepo, "app1", "v1.0.0")["prop3"])
+}
+
+func TestConfigRepo_Fetch_RemovedVersions(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	gitRepo := cfgRepo.(*GitConfigRepo)
+	v100, err := gitRepo.repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, "app1/v1.0.0"), false)
+	assert.NoError(t, err)
+	// a local tag is never pruned
+	localTag := plumbing.NewHashReference(plumbing.NewTagReferenceName("app1/v2.0.0"), v100.Hash())
+	assert.NoError(t, gitRepo.repo.Storer.SetReference(localTag))
+	assert.NoError(t, cfgRepo.Init())
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 4)
+	changes := make([]configrepo.Change, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change)
+	})
+	v101, err := gitRepo.GetNearestBranch(configrepo.NewApplicationVersion("app1", "v1.0.1"))
+	assert.NoError(t, err)
+
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Storer.RemoveReference(plumbing.NewTagReferenceName("app1/v1.0.1")))
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Equal(t, []configrepo.Change{{
+		ApplicationVersion: configrepo.ApplicationVersion{AppName: "app1", AppVersion: "v1.0.1"},
+		Kind:               configrepo.VersionRemoved,
+		OldHash:            v101.Hash().String(),
+		NewHash:            v100.Hash().String(),
+		ChangedPaths:       []string{"config.yml"},
+	}}, changes)
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 3)
+	assert.Equal(t, "1.0.0", getConfigYml(t, cfgRepo, "app1", "v1.0.1")["ver"])
+
+	// the remote branch is pruned, the local branch (created by the clone) is left untouched
+	changes = changes[:0]
+	assert.NoError(t, remote.Storer.RemoveReference(plumbing.NewBranchReferenceName("app1/v6.0.0")))
+	assert.NoError(t, cfgRepo.Fetch())
+	_, err = gitRepo.repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, "app1/v6.0.0"), false)
+	assert.Error(t, err)
+	_, err = gitRepo.repo.Reference(plumbing.NewBranchReferenceName("app1/v6.0.0"), false)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, "6.0.0", getConfigYml(t, cfgRepo, "app1", "v10.0.0")["ver"])
+	_, err = gitRepo.repo.Reference(localTag.Name(), false)
+	assert.NoError(t, err)
+
+	changes = changes[:0]
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Empty(t, changes)
+}
@@ -1,45 +1,81 @@
 package gitconfigrepo
 
 import (
-	"fmt"
-	"github.com/hashicorp/go-version"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"io/ioutil"
 )
 
 // GetNearestBranch retrieve the nearest (<=) application version available on the git repo
 func (cr *GitConfigRepo) GetNearestBranch(targetApp *configrepo.ApplicationVersion) (*plumbing.Reference, error) {
-	app, appFound := cr.Apps[targetApp.AppName]
-	if !appFound {
-		return nil, configrepo.ErrApplicationNotFound
-	}
-	constraint, err := version.NewConstraint(fmt.Sprintf("<=%s", targetApp.AppVersion))
+	return cr.snapshot().nearestBranch(targetApp)
+}
+
+// GetLabelCommit resolve the application label (branch, tag or commit hash) to a commit
+//
+// the commit has to be reachable from one of the application branches or tags
+func (cr *GitConfigRepo) GetLabelCommit(targetApp *configrepo.ApplicationVersion) (*object.Commit, error) {
+	snap, err := cr.snapshot().reader()
 	if err != nil {
 		return nil, err
 	}
-	for _, chkVer := range app.Versions {
-		if constraint.Check(chkVer) {
-			return app.Branches[chkVer.Original()], nil
-		}
-	}
-	return nil, fmt.Errorf("no branch found for target chkVer:%s", targetApp.AppVersion)
+	defer snap.close()
+	return snap.labelCommit(targetApp, cr.remoteName())
 }
 
 // GetFile retrieve a file from the git repo
 func (cr *GitConfigRepo) GetFile(targetApp *configrepo.ApplicationVersion, path string) (*configrepo.RepoFile, error) {
 	log := logrus.WithField("method", "GetFile").WithField("targetApp", targetApp).WithField("path", path)
-	branchRef, err := cr.GetNearestBranch(targetApp)
+	snap, err := cr.snapshot().reader()
 	if err != nil {
+		log.Errorf("Error opening the git repository:%s", err)
 		return nil, err
 	}
-	commit, err := cr.repo.CommitObject(branchRef.Hash())
+	defer snap.close()
+	commit, err := snap.appCommit(targetApp, cr.remoteName())
 	if err != nil {
 		log.Errorf("Error getting the commit object:%s", err)
 		return nil, err
 	}
 	log.Debugf("found commit: %s", commit.Hash.String())
+	return readFile(commit, path, log)
+}
+
+// GetSharedFile retrieve a file from the shared branch of the application version (see WithSharedBranch)
+//
+// without label the file is read from the head of the shared branch, with a label it's read from the shared commit
+// that was the head of the shared branch when the labelled commit was committed (see snapshotReader.sharedLabelCommit)
+func (cr *GitConfigRepo) GetSharedFile(targetApp *configrepo.ApplicationVersion, path string) (*configrepo.RepoFile, error) {
+	log := logrus.WithField("method", "GetSharedFile").WithField("targetApp", targetApp).WithField("path", path)
+	snap, err := cr.snapshot().reader()
+	if err != nil {
+		log.Errorf("Error opening the git repository:%s", err)
+		return nil, err
+	}
+	defer snap.close()
+	branchRef, err := snap.sharedBranch(targetApp.AppVersion)
+	if err != nil {
+		log.Debugf("no shared branch found:%s", err)
+		return nil, err
+	}
+	var commit *object.Commit
+	if targetApp.Label != "" {
+		commit, err = snap.sharedLabelCommit(targetApp, branchRef, cr.remoteName())
+	} else {
+		commit, err = snap.refCommit(branchRef)
+	}
+	if err != nil {
+		log.Errorf("Error getting the commit object:%s", err)
+		return nil, err
+	}
+	log.Debugf("found shared commit: %s", commit.Hash.String())
+	return readFile(commit, path, log)
+}
+
+func readFile(commit *object.Commit, path string, log *logrus.Entry) (*configrepo.RepoFile, error) {
 	tree, err := commit.Tree()
 	if err != nil {
 		log.Errorf("Error getting the tree:%s", err)
@@ -49,6 +85,9 @@ func (cr *GitConfigRepo) GetFile(targetApp *configrepo.ApplicationVersion, path
 	fl, err := tree.File(path)
 	if err != nil {
 		log.Errorf("Error getting the file:%s", err)
+		if errors.Is(err, object.ErrFileNotFound) {
+			return nil, configrepo.ErrFileNotFound
+		}
 		return nil, err
 	}
 	flReader, err := fl.Reader()
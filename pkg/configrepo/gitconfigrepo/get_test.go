@@ -1,9 +1,15 @@
 package gitconfigrepo
 
 import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/yaml.v2"
+	"sync"
 	"testing"
 )
 
@@ -104,3 +110,99 @@ func TestConfigRepo_GetFile_AppNotFound(t *testing.T) {
 	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("not-exist-app", "v1.0.0"), "config.yml")
 	assert.Error(t, err)
 }
+
+func TestConfigRepo_GetFile_FileNotFound(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NotNil(t, cfgRepo)
+	assert.NoError(t, cfgRepo.Init())
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "notExisting.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+}
+
+func TestConfigRepo_GetFile_Label(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+	oldFile, err := cfgRepo.GetFile(app, "config.yml")
+	assert.NoError(t, err)
+	prop3Val := uuid.New().String()
+
+	editAndPush(t, remoteRepo, "app1", "v1.0.0", "app1", "v1.0.0", "config.yml", "added prop3", []byte(fmt.Sprintf("prop3: %s", prop3Val)))
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.Equal(t, prop3Val, getConfigYml(t, cfgRepo, "app1", "v1.0.0")["prop3"])
+
+	oldFileAtLabel, err := cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", oldFile.Version), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, oldFile, oldFileAtLabel)
+
+	tests := []struct {
+		name            string
+		label           string
+		expectedVersion string
+	}{
+		{"branch", "app1/v6.0.0", "6.0.0"},
+		{"tag", "app1/v1.0.1", "1.0.1"},
+		{"ancestor", "app1/v1.0.0~1", "1.0.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fl, err := cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", test.label), "config.yml")
+			assert.NoError(t, err)
+			configContent := make(map[string]interface{})
+			assert.NoError(t, yaml.Unmarshal(fl.Content, configContent))
+			assert.Equal(t, test.expectedVersion, configContent["ver"])
+		})
+	}
+}
+
+func TestConfigRepo_GetFile_LabelNotFound(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	editAndPush(t, remoteRepo, "app1", "v1.0.0", "app2", "v1.0.0", "config.yml", "app2 config", []byte("ver: app2"))
+	assert.NoError(t, cfgRepo.Fetch())
+	app2File, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app2", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+
+	for _, label := range []string{"notExisting", "app2/v1.0.0", app2File.Version} {
+		t.Run(label, func(t *testing.T) {
+			_, err := cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", label), "config.yml")
+			assert.True(t, errors.Is(err, configrepo.ErrLabelNotFound))
+		})
+	}
+}
+
+func TestConfigRepo_GetFile_Concurrent(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	gitRepo := cfgRepo.(*GitConfigRepo)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+			if i%2 == 0 {
+				app = configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", "app1/v1.0.1")
+			}
+			_, err := cfgRepo.GetFile(app, "config.yml")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// the label resolution is cached by the snapshot
+	cachedHash, cached := gitRepo.snapshot().labels.Load(labelKey{appName: "app1", label: "app1/v1.0.1"})
+	assert.True(t, cached)
+	fl, err := cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", "app1/v1.0.1"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, cachedHash.(plumbing.Hash).String(), fl.Version)
+}
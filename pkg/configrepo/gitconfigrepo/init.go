@@ -7,6 +7,7 @@ import (
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,27 +21,58 @@ func newApp(name string) *app {
 	return &app{name, make(map[string]*plumbing.Reference), make([]*version.Version, 0)}
 }
 
+func (a *app) removeVersion(verName string) {
+	delete(a.Branches, verName)
+	versions := make([]*version.Version, 0, len(a.Versions))
+	for _, ver := range a.Versions {
+		if ver.Original() != verName {
+			versions = append(versions, ver)
+		}
+	}
+	a.Versions = versions
+}
+
 // ErrorHandlerFn represent an error handler function
 type ErrorHandlerFn func(err error)
 
+// Option represent a GitConfigRepo configuration option
+type Option func(cr *GitConfigRepo)
+
+// WithRefConvention set the reference to application version convention (default: DefaultRefConvention)
+func WithRefConvention(convention *RefConvention) Option {
+	return func(cr *GitConfigRepo) {
+		cr.refConvention = convention
+	}
+}
+
 // GitConfigRepo represent a git configuration repository
 type GitConfigRepo struct {
-	repo            *git.Repository
-	Apps            map[string]*app
-	fetchCh         chan bool
-	lastFetch       *time.Time
-	lastFetchMutex  sync.Mutex
-	cloneOpts       *git.CloneOptions
-	errorsCh        chan error
-	errorHandlers   []ErrorHandlerFn
-	changesHandlers []configrepo.OnChangeHandler
+	repo               *git.Repository
+	localPath          string
+	current            atomic.Value
+	fetchMutex         sync.Mutex
+	fetchCh            chan bool
+	lastFetch          *time.Time
+	lastFetchMutex     sync.Mutex
+	cloneOpts          *git.CloneOptions
+	errorsCh           chan error
+	errorHandlers      []ErrorHandlerFn
+	errorHandlersMutex sync.RWMutex
+	changesHandlers    []configrepo.OnChangeHandler
+	handlersMutex      sync.RWMutex
+	refConvention      *RefConvention
+	verifier           *signatureVerifier
+	shared             *sharedBranch
+	// remoteTags the tags advertised by the remote repo at the last fetch (see pruneReferences)
+	remoteTags map[plumbing.ReferenceName]plumbing.Hash
 }
 
 // NewGitConfigRepo instantiate a new GIT configuration repository
-func NewGitConfigRepo(localPath string, cloneOpts *git.CloneOptions) (configrepo.Repo, error) {
+func NewGitConfigRepo(localPath string, cloneOpts *git.CloneOptions, opts ...Option) (configrepo.Repo, error) {
 	log := logrus.WithField("localPath", localPath)
 	log.Info("New Config Repo")
 	repo, err := git.PlainOpen(localPath)
+	cloned := false
 	if err == git.ErrRepositoryNotExists {
 		log.Warn("no repo found")
 		if cloneOpts != nil {
@@ -48,41 +80,80 @@ func NewGitConfigRepo(localPath string, cloneOpts *git.CloneOptions) (configrepo
 			cloneOpts.NoCheckout = true
 			log.Infof("cloning it from :%+v", cloneOpts)
 			repo, err = git.PlainClone(localPath, false, cloneOpts)
+			cloned = true
 		}
 	}
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	return &GitConfigRepo{
+	remoteTags, err := clonedTags(repo, cloned)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	cr := &GitConfigRepo{
 		repo:            repo,
-		Apps:            make(map[string]*app),
+		localPath:       localPath,
 		fetchCh:         make(chan bool),
 		lastFetch:       nil,
 		cloneOpts:       cloneOpts,
 		errorsCh:        make(chan error),
 		errorHandlers:   make([]ErrorHandlerFn, 0),
 		changesHandlers: make([]configrepo.OnChangeHandler, 0),
-	}, nil
+		refConvention:   DefaultRefConvention,
+		remoteTags:      remoteTags,
+	}
+	cr.current.Store(&snapshot{apps: make(map[string]*app), repos: &repoPool{localPath: localPath}})
+	for _, opt := range opts {
+		opt(cr)
+	}
+	return cr, nil
+}
+
+// clonedTags returns the tags of a repository just cloned (they come from the remote repo), none for an existing repository
+func clonedTags(repo *git.Repository, cloned bool) (map[plumbing.ReferenceName]plumbing.Hash, error) {
+	result := make(map[plumbing.ReferenceName]plumbing.Hash)
+	if !cloned {
+		return result, nil
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		result[ref.Name()] = ref.Hash()
+		return nil
+	})
+	return result, err
 }
 
 // Init initialize the git repository
-func (cr *GitConfigRepo) Init() (err error) {
+func (cr *GitConfigRepo) Init() error {
 	cr.errorHandlerManager()
-	cr.Apps, err = cr.loadApps()
-	return
+	cr.fetchMutex.Lock()
+	defer cr.fetchMutex.Unlock()
+	snap, err := cr.newSnapshot()
+	if err != nil {
+		return err
+	}
+	cr.current.Store(snap)
+	return nil
 }
 
 // GetAppsVersions returns a appName-> list of version
 func (cr *GitConfigRepo) GetAppsVersions() map[string][]*version.Version {
 	result := make(map[string][]*version.Version)
-	for app, branch := range cr.Apps {
-		result[app] = branch.Versions
+	for appName, app := range cr.snapshot().apps {
+		result[appName] = make([]*version.Version, len(app.Versions))
+		copy(result[appName], app.Versions)
 	}
 	return result
 }
 
 // AddOnChangeHandler add a new change handler to the git repo
 func (cr *GitConfigRepo) AddOnChangeHandler(handler configrepo.OnChangeHandler) {
+	cr.handlersMutex.Lock()
+	defer cr.handlersMutex.Unlock()
 	cr.changesHandlers = append(cr.changesHandlers, handler)
 }
@@ -4,55 +4,75 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
 	"github.com/vecosy/vecosy/v2/internal/utils"
+	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/storer"
-	"regexp"
 	"sort"
 )
 
-var appRe = regexp.MustCompile(`.*/([a-z|A-Z|0-9|\-|.]*)/([a-z|A-Z|0-9|\-|.]*)`)
+// MatchReference returns true if the reference name follows the RefConvention
+//
+// the branches of the remote repository (refs/heads/...) are matched as remote branches
+func (cr *GitConfigRepo) MatchReference(refName string) bool {
+	name := plumbing.ReferenceName(refName)
+	if name.IsBranch() {
+		name = plumbing.NewRemoteReferenceName(cr.remoteName(), name.Short())
+	}
+	_, err := cr.refConvention.Parse(name)
+	return err == nil
+}
+
+func (cr *GitConfigRepo) remoteName() string {
+	if cr.cloneOpts != nil && cr.cloneOpts.RemoteName != "" {
+		return cr.cloneOpts.RemoteName
+	}
+	return git.DefaultRemoteName
+}
 
-func addApp(branchRef *plumbing.Reference, apps map[string]*app) error {
+func (cr *GitConfigRepo) addApp(branchRef *plumbing.Reference, apps map[string]*app) error {
 	logrus.Debugf("analyzing reference :%s", branchRef.Name())
-	branchName := branchRef.Name().String()
-	appMatches := appRe.FindAllStringSubmatch(branchName, 1)
-	if len(appMatches) == 1 && len(appMatches[0]) == 3 {
-		appName := appMatches[0][1]
-		appStrVersion := appMatches[0][2]
-		appVersion, err := version.NewVersion(appStrVersion)
-		if err != nil {
-			logrus.Warnf("Invalid application version:%s err:%s", appVersion, err)
-		} else {
-			logrus.Debugf("appName:%s appVersion:%s", appName, appStrVersion)
-			if _, appFound := apps[appName]; !appFound {
-				apps[appName] = newApp(appName)
-			}
-			if _, alreadyPresent := apps[appName].Branches[appStrVersion]; !alreadyPresent {
-				apps[appName].Versions = append(apps[appName].Versions, appVersion)
-			}
-			apps[appName].Branches[appStrVersion] = branchRef
-		}
-	} else {
-		logrus.Warnf("the branch %s doesn't match with the branch pattern", branchName)
+	if cr.isSharedReference(branchRef.Name()) {
+		logrus.Debugf("%s is a shared branch", branchRef.Name())
+		return nil
+	}
+	refApp, err := cr.refConvention.Parse(branchRef.Name())
+	if err != nil {
+		logrus.Warnf("the reference %s doesn't match with the reference convention", branchRef.Name())
+		cr.pushError(err)
+		return nil
+	}
+	appName := refApp.AppName
+	appStrVersion := refApp.AppVersion
+	appVersion, err := version.NewVersion(appStrVersion)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("appName:%s appVersion:%s", appName, appStrVersion)
+	if _, appFound := apps[appName]; !appFound {
+		apps[appName] = newApp(appName)
+	}
+	if _, alreadyPresent := apps[appName].Branches[appStrVersion]; !alreadyPresent {
+		apps[appName].Versions = append(apps[appName].Versions, appVersion)
 	}
+	apps[appName].Branches[appStrVersion] = branchRef
 	return nil
 }
 
-func (cr *GitConfigRepo) loadApps() (map[string]*app, error) {
+func (cr *GitConfigRepo) loadApps(repo *git.Repository) (map[string]*app, error) {
 	newApps := make(map[string]*app)
-	err := cr.loadAppsFromRemoteBranches(newApps)
+	err := cr.loadAppsFromRemoteBranches(repo, newApps)
 	if err != nil {
 		logrus.Errorf("Error loading apps from remote branches:%s", err)
 		return nil, err
 	}
 
-	err = cr.loadAppsFromLocalBranches(newApps)
+	err = cr.loadAppsFromLocalBranches(repo, newApps)
 	if err != nil {
 		logrus.Errorf("Error loading apps from local branches:%s", err)
 		return nil, err
 	}
 
-	err = cr.loadAppsFromTags(newApps)
+	err = cr.loadAppsFromTags(repo, newApps)
 	if err != nil {
 		logrus.Errorf("Error loading apps from tags:%s", err)
 		return nil, err
@@ -78,32 +98,32 @@ func remoteBranches(s storer.ReferenceStorer) (storer.ReferenceIter, error) {
 	}, refs), nil
 }
 
-func (cr *GitConfigRepo) loadAppsFromRemoteBranches(apps map[string]*app) error {
-	branches, err := remoteBranches(cr.repo.Storer)
+func (cr *GitConfigRepo) loadAppsFromRemoteBranches(repo *git.Repository, apps map[string]*app) error {
+	branches, err := remoteBranches(repo.Storer)
 	if err != nil {
 		return err
 	}
 	return branches.ForEach(func(reference *plumbing.Reference) error {
-		return addApp(reference, apps)
+		return cr.addApp(reference, apps)
 	})
 }
 
-func (cr *GitConfigRepo) loadAppsFromLocalBranches(apps map[string]*app) error {
-	branches, err := cr.repo.Branches()
+func (cr *GitConfigRepo) loadAppsFromLocalBranches(repo *git.Repository, apps map[string]*app) error {
+	branches, err := repo.Branches()
 	if err != nil {
 		return err
 	}
 	return branches.ForEach(func(reference *plumbing.Reference) error {
-		return addApp(reference, apps)
+		return cr.addApp(reference, apps)
 	})
 }
 
-func (cr *GitConfigRepo) loadAppsFromTags(apps map[string]*app) error {
-	tags, err := cr.repo.Tags()
+func (cr *GitConfigRepo) loadAppsFromTags(repo *git.Repository, apps map[string]*app) error {
+	tags, err := repo.Tags()
 	if err != nil {
 		return err
 	}
 	return tags.ForEach(func(reference *plumbing.Reference) error {
-		return addApp(reference, apps)
+		return cr.addApp(reference, apps)
 	})
 }
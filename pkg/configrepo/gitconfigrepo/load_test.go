@@ -24,3 +24,12 @@ func TestNewConfigRepo(t *testing.T) {
 	assert.Equal(t, cfgRepo.GetAppsVersions()["app1"][1], v101)
 	assert.Equal(t, cfgRepo.GetAppsVersions()["app1"][2], v100)
 }
+
+func TestConfigRepo_MatchReference(t *testing.T) {
+	cfgRepo := &GitConfigRepo{refConvention: DefaultRefConvention}
+	assert.True(t, cfgRepo.MatchReference("refs/heads/app1/1.0.0"))
+	assert.True(t, cfgRepo.MatchReference("refs/tags/app1/v1.0.1"))
+	assert.True(t, cfgRepo.MatchReference("refs/remotes/origin/app1/6.0.0"))
+	assert.False(t, cfgRepo.MatchReference("refs/heads/master"))
+	assert.False(t, cfgRepo.MatchReference("refs/heads/feature/notAVersion"))
+}
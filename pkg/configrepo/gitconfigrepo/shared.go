@@ -0,0 +1,169 @@
+package gitconfigrepo
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// VersionPlaceholder is replaced by the version in the shared branch name (i.e. `_common/<version>`)
+const VersionPlaceholder = "<version>"
+
+// unversioned the key of the shared branch without the version placeholder
+const unversioned = ""
+
+type sharedBranch struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// WithSharedBranch merge the files of a shared branch underneath every application (see configrepo.SharedRepo)
+//
+// with the `<version>` placeholder (i.e. `_common/<version>`) every application version shares the nearest (<=) shared version,
+// otherwise (i.e. `shared/main`) the same branch is shared by all the application versions
+func WithSharedBranch(branch string) Option {
+	return func(cr *GitConfigRepo) {
+		parts := strings.SplitN(branch, VersionPlaceholder, 2)
+		pattern := regexp.QuoteMeta(parts[0])
+		if len(parts) == 2 {
+			pattern += `(?P<version>[a-z|A-Z|0-9|\-|.]*)` + regexp.QuoteMeta(parts[1])
+		}
+		cr.shared = &sharedBranch{name: branch, pattern: regexp.MustCompile("^" + pattern + "$")}
+	}
+}
+
+// match returns the shared version of the branch (unversioned if the shared branch has no version placeholder)
+func (b *sharedBranch) match(branchName string) (string, bool) {
+	matches := b.pattern.FindStringSubmatch(branchName)
+	if matches == nil {
+		return "", false
+	}
+	if len(matches) == 1 {
+		return unversioned, true
+	}
+	if _, err := version.NewVersion(matches[1]); err != nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// isSharedReference returns true if the reference is a local or remote shared branch
+func (cr *GitConfigRepo) isSharedReference(refName plumbing.ReferenceName) bool {
+	if cr.shared == nil || !(refName.IsBranch() || refName.IsRemote()) {
+		return false
+	}
+	_, found := cr.shared.match(cr.branchName(refName))
+	return found
+}
+
+// loadShared returns the shared branches as an app, nil if no shared branch has been configured
+func (cr *GitConfigRepo) loadShared(repo *git.Repository) (*app, error) {
+	if cr.shared == nil {
+		return nil, nil
+	}
+	shared := newApp(cr.shared.name)
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	remoteRefs := make([]*plumbing.Reference, 0)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		switch {
+		case ref.Name().IsBranch():
+			cr.addSharedBranch(shared, ref)
+		case ref.Name().IsRemote():
+			remoteRefs = append(remoteRefs, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the remote branches take precedence over the local ones
+	for _, ref := range remoteRefs {
+		cr.addSharedBranch(shared, ref)
+	}
+	sort.Sort(version.Collection(shared.Versions))
+	utils.ReverseVersion(shared.Versions)
+	logrus.Infof("shared branch:%s versions:%+v", cr.shared.name, shared.Versions)
+	return shared, nil
+}
+
+func (cr *GitConfigRepo) addSharedBranch(shared *app, ref *plumbing.Reference) {
+	verName, found := cr.shared.match(cr.branchName(ref.Name()))
+	if !found || ref.Type() != plumbing.HashReference {
+		return
+	}
+	if _, alreadyPresent := shared.Branches[verName]; !alreadyPresent && verName != unversioned {
+		shared.Versions = append(shared.Versions, version.Must(version.NewVersion(verName)))
+	}
+	shared.Branches[verName] = ref
+}
+
+// sharedBranch returns the shared branch of the application version
+func (s *snapshot) sharedBranch(appVersion string) (*plumbing.Reference, error) {
+	if s.shared == nil {
+		return nil, configrepo.ErrFileNotFound
+	}
+	if branchRef, found := s.shared.Branches[unversioned]; found {
+		return branchRef, nil
+	}
+	nearestVersion, err := configrepo.FindNearestVersion(s.shared.Versions, appVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%s", configrepo.ErrFileNotFound, err)
+	}
+	return s.shared.Branches[nearestVersion.Original()], nil
+}
+
+// sharedHash returns the commit hash of the shared branch of the application version, empty if none
+func (s *snapshot) sharedHash(appVersion string) string {
+	branchRef, err := s.sharedBranch(appVersion)
+	if err != nil {
+		return ""
+	}
+	return branchRef.Hash().String()
+}
+
+// sharedChanges returns an update of every application version whose shared branch has been changed,
+// the hashes of these changes are the shared branch ones
+func sharedChanges(oldSnapshot, newSnapshot *snapshot) []configrepo.Change {
+	changes := make([]configrepo.Change, 0)
+	if newSnapshot.shared == nil {
+		return changes
+	}
+	for appName, app := range newSnapshot.apps {
+		for _, ver := range app.Versions {
+			oldApp, appFound := oldSnapshot.apps[appName]
+			if !appFound {
+				continue
+			}
+			if _, verFound := oldApp.Branches[ver.Original()]; !verFound {
+				continue
+			}
+			oldHash := oldSnapshot.sharedHash(ver.Original())
+			newHash := newSnapshot.sharedHash(ver.Original())
+			if oldHash != newHash {
+				changes = append(changes, configrepo.Change{
+					ApplicationVersion: configrepo.ApplicationVersion{AppName: appName, AppVersion: ver.Original()},
+					Kind:               configrepo.VersionUpdated,
+					OldHash:            oldHash,
+					NewHash:            newHash,
+				})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].AppName != changes[j].AppName {
+			return changes[i].AppName < changes[j].AppName
+		}
+		return changes[i].AppVersion < changes[j].AppVersion
+	})
+	return changes
+}
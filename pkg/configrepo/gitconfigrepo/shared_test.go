@@ -0,0 +1,169 @@
+package gitconfigrepo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"testing"
+	"time"
+)
+
+// commitFileOnRemote commit a file to a remote branch, the branch is created (without history) if it doesn't exist
+func commitFileOnRemote(t *testing.T, remoteRepo, branch, flPath, content string) plumbing.Hash {
+	return commitFileOnRemoteAt(t, remoteRepo, branch, flPath, content, editorSignature.When)
+}
+
+func commitFileOnRemoteAt(t *testing.T, remoteRepo, branch, flPath, content string, when time.Time) plumbing.Hash {
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	tree := &object.Tree{}
+	parents := make([]plumbing.Hash, 0)
+	branchRef, err := remote.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err == nil {
+		parent, err := remote.CommitObject(branchRef.Hash())
+		assert.NoError(t, err)
+		tree, err = parent.Tree()
+		assert.NoError(t, err)
+		parents = append(parents, parent.Hash)
+	}
+	blobHash, err := storeBlob(remote.Storer, []byte(content))
+	assert.NoError(t, err)
+	pathParts, err := splitPath(flPath)
+	assert.NoError(t, err)
+	entries, err := updateTree(remote.Storer, tree, pathParts, &blobHash)
+	assert.NoError(t, err)
+	treeHash, err := storeTree(remote.Storer, entries)
+	assert.NoError(t, err)
+	signature := *editorSignature
+	signature.When = when
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "shared change",
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+	obj := remote.Storer.NewEncodedObject()
+	assert.NoError(t, commit.Encode(obj))
+	hash, err := remote.Storer.SetEncodedObject(obj)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), hash)))
+	return hash
+}
+
+func TestConfigRepo_SharedBranch_Versioned(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	commitFileOnRemote(t, remoteRepo, "_common/v1.0.0", "config.yml", "shared: v1")
+	commitFileOnRemote(t, remoteRepo, "_common/v5.0.0", "config.yml", "shared: v5")
+	commitFileOnRemote(t, remoteRepo, "_common/v5.0.0", "dev/config.yml", "shared: v5-dev")
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSharedBranch("_common/<version>"))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	sharedRepo := cfgRepo.(configrepo.SharedRepo)
+
+	assert.NotContains(t, cfgRepo.GetAppsVersions(), "_common")
+	tests := []struct {
+		appVersion string
+		path       string
+		expected   string
+	}{
+		{"v1.0.0", "config.yml", "shared: v1"},
+		{"v1.0.1", "config.yml", "shared: v1"},
+		{"v6.0.0", "config.yml", "shared: v5"},
+		{"v6.0.0", "dev/config.yml", "shared: v5-dev"},
+	}
+	for _, test := range tests {
+		sharedFile, err := sharedRepo.GetSharedFile(configrepo.NewApplicationVersion("app1", test.appVersion), test.path)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(sharedFile.Content))
+	}
+	_, err = sharedRepo.GetSharedFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "dev/config.yml")
+	assert.Equal(t, configrepo.ErrFileNotFound, err)
+	_, err = sharedRepo.GetSharedFile(configrepo.NewApplicationVersion("app1", "v0.1.0"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+
+	// the application files take precedence
+	appFile, err := configrepo.GetFileOrShared(cfgRepo, configrepo.NewApplicationVersion("app1", "v6.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "shared: v5", string(appFile.Content))
+	sharedFile, err := configrepo.GetFileOrShared(cfgRepo, configrepo.NewApplicationVersion("app1", "v6.0.0"), "dev/config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared: v5-dev", string(sharedFile.Content))
+}
+
+func TestConfigRepo_SharedBranch_Changes(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	commitFileOnRemote(t, remoteRepo, "shared/main", "config.yml", "shared: main")
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSharedBranch("shared/main"))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	sharedRepo := cfgRepo.(configrepo.SharedRepo)
+	changes := make([]configrepo.Change, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change)
+	})
+
+	assert.NotContains(t, cfgRepo.GetAppsVersions(), "shared")
+	sharedFile, err := sharedRepo.GetSharedFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared: main", string(sharedFile.Content))
+
+	newHash := commitFileOnRemote(t, remoteRepo, "shared/main", "int/config.yml", "shared: int")
+	assert.NoError(t, cfgRepo.Fetch())
+	// every version of every application is notified
+	assert.Len(t, changes, 3)
+	for _, change := range changes {
+		assert.Equal(t, "app1", change.AppName)
+		assert.Equal(t, configrepo.VersionUpdated, change.Kind)
+		assert.Equal(t, sharedFile.Version, change.OldHash)
+		assert.Equal(t, newHash.String(), change.NewHash)
+		assert.Equal(t, []string{"int/config.yml"}, change.ChangedPaths)
+		assert.False(t, merger.AffectsEnvironment(cfgRepo, change, "dev"))
+	}
+	sharedFile, err = sharedRepo.GetSharedFile(configrepo.NewApplicationVersion("app1", "v6.0.0"), "int/config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "shared: int", string(sharedFile.Content))
+}
+
+func TestConfigRepo_SharedBranch_Label(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	now := time.Now()
+	v1Hash := commitFileOnRemoteAt(t, remoteRepo, "shared/main", "config.yml", "shared: v1", now.Add(time.Hour))
+	appV1Hash := commitFileOnRemoteAt(t, remoteRepo, "app1/v1.0.0", "app.yml", "app: v1", now.Add(2*time.Hour))
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSharedBranch("shared/main"))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	sharedRepo := cfgRepo.(configrepo.SharedRepo)
+
+	v2Hash := commitFileOnRemoteAt(t, remoteRepo, "shared/main", "config.yml", "shared: v2", now.Add(3*time.Hour))
+	appV2Hash := commitFileOnRemoteAt(t, remoteRepo, "app1/v1.0.0", "app.yml", "app: v2", now.Add(4*time.Hour))
+	assert.NoError(t, cfgRepo.Fetch())
+	// the shared files are read as they were when the labelled commit was committed
+	tests := []struct {
+		label   string
+		content string
+		version plumbing.Hash
+	}{
+		{label: "", content: "shared: v2", version: v2Hash},
+		{label: appV1Hash.String(), content: "shared: v1", version: v1Hash},
+		{label: appV2Hash.String(), content: "shared: v2", version: v2Hash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			for i := 0; i < 2; i++ { // the second read uses the cached shared commit
+				sharedFile, err := sharedRepo.GetSharedFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", tt.label), "config.yml")
+				assert.NoError(t, err)
+				assert.Equal(t, tt.content, string(sharedFile.Content))
+				assert.Equal(t, tt.version.String(), sharedFile.Version)
+			}
+		})
+	}
+
+	// the labelled commit is older than the shared branch
+	_, err = sharedRepo.GetSharedFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", "app1/v1.0.0~2"), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrFileNotFound))
+}
@@ -0,0 +1,155 @@
+package gitconfigrepo
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"strings"
+	"sync"
+)
+
+// UntrustedPolicy define what is served when the head of an application version is not trusted
+type UntrustedPolicy int
+
+const (
+	// KeepLastTrusted keep serving the last trusted commit of the application version (if any),
+	// without a previously served commit (i.e. after a restart) the newest trusted commit of the branch first-parent history is served
+	KeepLastTrusted UntrustedPolicy = iota
+	// FailClosed stop serving the application version until a trusted commit is pushed
+	FailClosed
+)
+
+type signatureVerifier struct {
+	keyRing string
+	policy  UntrustedPolicy
+	// trustedCommits the newest trusted commit found walking an untrusted branch head (plumbing.ReferenceName -> trustedCommit)
+	trustedCommits sync.Map
+}
+
+// trustedCommit the newest trusted commit (plumbing.ZeroHash if none) of the first-parent history of a branch head
+type trustedCommit struct {
+	head    plumbing.Hash
+	trusted plumbing.Hash
+}
+
+// WithSignatureVerification serve only the application versions whose head commit is signed by one of the keys of the armored keyring
+//
+// the untrusted heads are reported through the error listeners (ErrUntrustedCommit) and managed according to the policy
+func WithSignatureVerification(armoredKeyRing string, policy UntrustedPolicy) Option {
+	return func(cr *GitConfigRepo) {
+		cr.verifier = &signatureVerifier{keyRing: armoredKeyRing, policy: policy}
+	}
+}
+
+// ValidateKeyRing returns an error if the armored keyring cannot be parsed
+func ValidateKeyRing(armoredKeyRing string) error {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKeyRing))
+	if err != nil {
+		return err
+	}
+	if len(keyRing) == 0 {
+		return fmt.Errorf("no keys found in the keyring")
+	}
+	return nil
+}
+
+// verify returns an ErrUntrustedCommit error if the commit is not signed by one of the keyring keys
+func (v *signatureVerifier) verify(commit *object.Commit) error {
+	if commit.PGPSignature == "" {
+		return fmt.Errorf("%w:%s is not signed", ErrUntrustedCommit, commit.Hash)
+	}
+	entity, err := commit.Verify(v.keyRing)
+	if err != nil {
+		return fmt.Errorf("%w:%s %s", ErrUntrustedCommit, commit.Hash, err)
+	}
+	for _, identity := range entity.Identities {
+		logrus.Debugf("commit %s signed by %s", commit.Hash, identity.Name)
+	}
+	return nil
+}
+
+// verifyApps removes (or replace with the previous trusted one) every application version (and shared branch) with an untrusted head
+func (cr *GitConfigRepo) verifyApps(newSnapshot *snapshot) error {
+	snap, err := newSnapshot.reader()
+	if err != nil {
+		return err
+	}
+	defer snap.close()
+	previous := cr.snapshot()
+	for appName, app := range snap.apps {
+		cr.verifyApp(snap, app, previous.apps[appName])
+		if len(app.Versions) == 0 {
+			delete(snap.apps, appName)
+		}
+	}
+	if snap.shared != nil {
+		cr.verifyApp(snap, snap.shared, previous.shared)
+	}
+	return nil
+}
+
+// verifyApp removes (or replace with the previous trusted one) every branch with an untrusted head
+func (cr *GitConfigRepo) verifyApp(snap *snapshotReader, app *app, previousApp *app) {
+	for verName, branchRef := range app.Branches {
+		commit, err := snap.refCommit(branchRef)
+		if err == nil {
+			err = cr.verifier.verify(commit)
+		} else {
+			err = fmt.Errorf("%w:%s %s", ErrUntrustedCommit, branchRef.Name(), err)
+		}
+		if err == nil {
+			continue
+		}
+		logrus.Warnf("rejecting %s %s head:%s", app.Name, verName, err)
+		cr.pushError(err)
+		if cr.verifier.policy == KeepLastTrusted {
+			if trustedRef := cr.lastTrustedRef(snap, previousApp, verName, branchRef); trustedRef != nil {
+				logrus.Warnf("keep serving the last trusted commit %s of %s %s", trustedRef.Hash(), app.Name, verName)
+				app.Branches[verName] = trustedRef
+				continue
+			}
+		}
+		app.removeVersion(verName)
+	}
+}
+
+// lastTrustedRef returns the trusted commit served by the previous snapshot or, if none (i.e. after a restart),
+// the newest trusted commit of the branch first-parent history (the merged-in commits are never served).
+// The walk result is cached per branch head. It returns nil if no trusted commit has been found
+func (cr *GitConfigRepo) lastTrustedRef(snap *snapshotReader, previousApp *app, verName string, branchRef *plumbing.Reference) *plumbing.Reference {
+	if previousApp != nil {
+		if previousRef, verFound := previousApp.Branches[verName]; verFound {
+			return previousRef
+		}
+	}
+	if cached, found := cr.verifier.trustedCommits.Load(branchRef.Name()); found && cached.(trustedCommit).head == branchRef.Hash() {
+		return trustedHashRef(branchRef.Name(), cached.(trustedCommit).trusted)
+	}
+	commit, err := snap.refCommit(branchRef)
+	for err == nil && cr.verifier.verify(commit) != nil {
+		if commit.NumParents() == 0 {
+			commit = nil
+			break
+		}
+		commit, err = commit.Parent(0)
+	}
+	if err != nil {
+		logrus.Warnf("Error walking the %s history:%s", branchRef.Name(), err)
+		return nil
+	}
+	trusted := plumbing.ZeroHash
+	if commit != nil {
+		trusted = commit.Hash
+	}
+	cr.verifier.trustedCommits.Store(branchRef.Name(), trustedCommit{head: branchRef.Hash(), trusted: trusted})
+	return trustedHashRef(branchRef.Name(), trusted)
+}
+
+func trustedHashRef(name plumbing.ReferenceName, trusted plumbing.Hash) *plumbing.Reference {
+	if trusted.IsZero() {
+		return nil
+	}
+	return plumbing.NewHashReference(name, trusted)
+}
@@ -0,0 +1,201 @@
+package gitconfigrepo
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newSigningKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("Config Editor", "", "editor@cfg.local", nil)
+	assert.NoError(t, err)
+	armoredKey := &bytes.Buffer{}
+	w, err := armor.Encode(armoredKey, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(w))
+	assert.NoError(t, w.Close())
+	return entity, armoredKey.String()
+}
+
+// commitOnRemote add an empty commit (same tree of the parent) to a remote branch, signed if signKey is not nil
+func commitOnRemote(t *testing.T, remoteRepo, branch string, signKey *openpgp.Entity) plumbing.Hash {
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	branchRef, err := remote.Reference(plumbing.NewBranchReferenceName(branch), true)
+	assert.NoError(t, err)
+	parent, err := remote.CommitObject(branchRef.Hash())
+	assert.NoError(t, err)
+	commit := &object.Commit{
+		Author:       *editorSignature,
+		Committer:    *editorSignature,
+		Message:      "empty commit",
+		TreeHash:     parent.TreeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash},
+	}
+	if signKey != nil {
+		encoded := &plumbing.MemoryObject{}
+		assert.NoError(t, commit.Encode(encoded))
+		reader, err := encoded.Reader()
+		assert.NoError(t, err)
+		signature := &bytes.Buffer{}
+		assert.NoError(t, openpgp.ArmoredDetachSign(signature, signKey, reader, nil))
+		commit.PGPSignature = signature.String()
+	}
+	obj := remote.Storer.NewEncodedObject()
+	assert.NoError(t, commit.Encode(obj))
+	hash, err := remote.Storer.SetEncodedObject(obj)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Storer.SetReference(plumbing.NewHashReference(branchRef.Name(), hash)))
+	return hash
+}
+
+func TestConfigRepo_SignatureVerification_FailClosed(t *testing.T) {
+	signKey, armoredKeyRing := newSigningKey(t)
+	wrongKey, _ := newSigningKey(t)
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSignatureVerification(armoredKeyRing, FailClosed))
+	assert.NoError(t, err)
+	gitRepo := cfgRepo.(*GitConfigRepo)
+
+	errorsMutex := sync.Mutex{}
+	receivedErrors := make([]error, 0)
+	gitRepo.AddErrorListener(func(err error) {
+		errorsMutex.Lock()
+		defer errorsMutex.Unlock()
+		receivedErrors = append(receivedErrors, err)
+	})
+	assert.NoError(t, cfgRepo.Init())
+
+	// none of the initial commits are signed
+	assert.Empty(t, cfgRepo.GetAppsVersions())
+	assert.Eventually(t, func() bool {
+		errorsMutex.Lock()
+		defer errorsMutex.Unlock()
+		return len(receivedErrors) == 3
+	}, time.Second, 10*time.Millisecond)
+	for _, err := range receivedErrors {
+		assert.True(t, errors.Is(err, ErrUntrustedCommit))
+	}
+
+	signedHash := commitOnRemote(t, remoteRepo, "app1/v1.0.0", signKey)
+	assert.NoError(t, cfgRepo.Fetch())
+	cfgFile, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v6.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, signedHash.String(), cfgFile.Version)
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 1)
+
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", wrongKey)
+	assert.NoError(t, cfgRepo.Fetch())
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.Equal(t, configrepo.ErrApplicationNotFound, err)
+}
+
+func TestConfigRepo_SignatureVerification_KeepLastTrusted(t *testing.T) {
+	signKey, armoredKeyRing := newSigningKey(t)
+	localRepo, remoteRepo := InitRepos(t)
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	unsignedRef, err := remote.Reference(plumbing.NewBranchReferenceName("app1/v1.0.0"), true)
+	assert.NoError(t, err)
+	signedHash := commitOnRemote(t, remoteRepo, "app1/v1.0.0", signKey)
+
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSignatureVerification(armoredKeyRing, KeepLastTrusted))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	changes := make([]configrepo.Change, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change)
+	})
+
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", nil)
+	assert.NoError(t, cfgRepo.Fetch())
+	cfgFile, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, signedHash.String(), cfgFile.Version)
+	assert.Empty(t, changes)
+
+	// the unsigned ancestors cannot be served through labels
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", unsignedRef.Hash().String()), "config.yml")
+	assert.True(t, errors.Is(err, configrepo.ErrLabelNotFound))
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersionAtLabel("app1", "v1.0.0", signedHash.String()), "config.yml")
+	assert.NoError(t, err)
+}
+
+func TestConfigRepo_SignatureVerification_KeepLastTrusted_History(t *testing.T) {
+	signKey, armoredKeyRing := newSigningKey(t)
+	localRepo, remoteRepo := InitRepos(t)
+	signedHash := commitOnRemote(t, remoteRepo, "app1/v1.0.0", signKey)
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", nil)
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", nil)
+
+	// no previous snapshot (i.e. a restart): the newest trusted commit of the branch history is served
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSignatureVerification(armoredKeyRing, KeepLastTrusted))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	cfgFile, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, signedHash.String(), cfgFile.Version)
+
+	// the versions without trusted commits are not served, v6.0.0 falls back to v1.0.0
+	cfgFile, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v6.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, signedHash.String(), cfgFile.Version)
+	assert.Len(t, cfgRepo.GetAppsVersions()["app1"], 1)
+}
+
+// mergeOnRemote add an unsigned merge commit (same tree of the branch head) of the other commit to a remote branch
+func mergeOnRemote(t *testing.T, remoteRepo, branch string, other plumbing.Hash) plumbing.Hash {
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	branchRef, err := remote.Reference(plumbing.NewBranchReferenceName(branch), true)
+	assert.NoError(t, err)
+	parent, err := remote.CommitObject(branchRef.Hash())
+	assert.NoError(t, err)
+	commit := &object.Commit{
+		Author:       *editorSignature,
+		Committer:    *editorSignature,
+		Message:      "merge commit",
+		TreeHash:     parent.TreeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash, other},
+	}
+	obj := remote.Storer.NewEncodedObject()
+	assert.NoError(t, commit.Encode(obj))
+	hash, err := remote.Storer.SetEncodedObject(obj)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Storer.SetReference(plumbing.NewHashReference(branchRef.Name(), hash)))
+	return hash
+}
+
+func TestConfigRepo_SignatureVerification_KeepLastTrusted_FirstParent(t *testing.T) {
+	signKey, armoredKeyRing := newSigningKey(t)
+	localRepo, remoteRepo := InitRepos(t)
+	signedHash := commitOnRemote(t, remoteRepo, "app1/v1.0.0", signKey)
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), signedHash)))
+	featureHash := commitOnRemote(t, remoteRepo, "feature", signKey)
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", nil)
+	headHash := mergeOnRemote(t, remoteRepo, "app1/v1.0.0", featureHash)
+
+	// the signed commits merged in from other branches are never served
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSignatureVerification(armoredKeyRing, KeepLastTrusted))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	cfgFile, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, signedHash.String(), cfgFile.Version)
+
+	// the walk result is cached per branch head
+	cached, found := cfgRepo.(*GitConfigRepo).verifier.trustedCommits.Load(plumbing.NewRemoteReferenceName("origin", "app1/v1.0.0"))
+	assert.True(t, found)
+	assert.Equal(t, trustedCommit{head: headHash, trusted: signedHash}, cached)
+}
@@ -0,0 +1,319 @@
+package gitconfigrepo
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"sort"
+	"sync"
+)
+
+// snapshot is an immutable view of the repo applications, it's atomically replaced on every change
+//
+// the go-git storage is not safe for concurrent use: every reader borrows a git repository instance from the snapshot pool (see reader),
+// so the reads run concurrently while the fetch works on a different instance
+type snapshot struct {
+	apps     map[string]*app
+	shared   *app
+	repos    *repoPool
+	verifier *signatureVerifier
+	// labels the resolved label commits (labelKey -> plumbing.Hash), only the trusted and reachable ones are cached
+	labels sync.Map
+	// sharedLabels the shared commits of the labelled commits (sharedLabelKey -> plumbing.Hash, plumbing.ZeroHash if none)
+	sharedLabels sync.Map
+}
+
+// repoPool the git repository instances of a snapshot, they are opened after the snapshot references have been loaded
+// so they see all the objects of the snapshot
+type repoPool struct {
+	localPath string
+	mutex     sync.Mutex
+	free      []*git.Repository
+}
+
+func newRepoPool(localPath string, repo *git.Repository) *repoPool {
+	return &repoPool{localPath: localPath, free: []*git.Repository{repo}}
+}
+
+// get returns an idle instance or opens a new one
+func (p *repoPool) get() (*git.Repository, error) {
+	p.mutex.Lock()
+	if idle := len(p.free); idle > 0 {
+		repo := p.free[idle-1]
+		p.free = p.free[:idle-1]
+		p.mutex.Unlock()
+		return repo, nil
+	}
+	p.mutex.Unlock()
+	return git.PlainOpen(p.localPath)
+}
+
+func (p *repoPool) put(repo *git.Repository) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.free = append(p.free, repo)
+}
+
+// snapshotReader a snapshot with a git repository instance used by a single goroutine
+type snapshotReader struct {
+	*snapshot
+	repo *git.Repository
+}
+
+// reader borrows a git repository instance of the snapshot, the reader has to be closed when the git objects are not used anymore
+func (s *snapshot) reader() (*snapshotReader, error) {
+	repo, err := s.repos.get()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotReader{snapshot: s, repo: repo}, nil
+}
+
+// close gives the git repository instance back to the snapshot
+func (r *snapshotReader) close() {
+	r.repos.put(r.repo)
+}
+
+func (cr *GitConfigRepo) newSnapshot() (*snapshot, error) {
+	repo, err := git.PlainOpen(cr.localPath)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := cr.loadApps(repo)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := cr.loadShared(repo)
+	if err != nil {
+		return nil, err
+	}
+	snap := &snapshot{apps: apps, shared: shared, repos: newRepoPool(cr.localPath, repo), verifier: cr.verifier}
+	if cr.verifier != nil {
+		err = cr.verifyApps(snap)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
+
+// snapshot returns the current snapshot
+func (cr *GitConfigRepo) snapshot() *snapshot {
+	return cr.current.Load().(*snapshot)
+}
+
+func (s *snapshot) nearestBranch(targetApp *configrepo.ApplicationVersion) (*plumbing.Reference, error) {
+	app, appFound := s.apps[targetApp.AppName]
+	if !appFound {
+		return nil, configrepo.ErrApplicationNotFound
+	}
+	nearestVersion, err := configrepo.FindNearestVersion(app.Versions, targetApp.AppVersion)
+	if err != nil {
+		return nil, err
+	}
+	return app.Branches[nearestVersion.Original()], nil
+}
+
+// appCommit returns the head of the nearest application version or the labelled commit
+func (s *snapshotReader) appCommit(targetApp *configrepo.ApplicationVersion, remoteName string) (*object.Commit, error) {
+	if targetApp.Label != "" {
+		return s.labelCommit(targetApp, remoteName)
+	}
+	branchRef, err := s.nearestBranch(targetApp)
+	if err != nil {
+		return nil, err
+	}
+	return s.refCommit(branchRef)
+}
+
+// refCommit returns the commit pointed by the reference (annotated tags included)
+func (s *snapshotReader) refCommit(ref *plumbing.Reference) (*object.Commit, error) {
+	commit, err := s.repo.CommitObject(ref.Hash())
+	if err == nil {
+		return commit, nil
+	}
+	tag, tagErr := s.repo.TagObject(ref.Hash())
+	if tagErr != nil {
+		return nil, err
+	}
+	return tag.Commit()
+}
+
+// labelCommit resolve the application label to a commit reachable from one of the application branches or tags,
+// the resolution is cached for the snapshot lifetime
+func (s *snapshotReader) labelCommit(targetApp *configrepo.ApplicationVersion, remoteName string) (*object.Commit, error) {
+	app, appFound := s.apps[targetApp.AppName]
+	if !appFound {
+		return nil, configrepo.ErrApplicationNotFound
+	}
+	key := labelKey{appName: targetApp.AppName, label: targetApp.Label}
+	if cachedHash, cached := s.labels.Load(key); cached {
+		return s.repo.CommitObject(cachedHash.(plumbing.Hash))
+	}
+	commit, err := s.findLabelCommit(app, targetApp, remoteName)
+	if err != nil {
+		return nil, err
+	}
+	s.labels.Store(key, commit.Hash)
+	return commit, nil
+}
+
+type labelKey struct {
+	appName string
+	label   string
+}
+
+func (s *snapshotReader) findLabelCommit(app *app, targetApp *configrepo.ApplicationVersion, remoteName string) (*object.Commit, error) {
+	labelHash, err := s.resolveLabel(targetApp.Label, remoteName)
+	if err != nil {
+		logrus.Warnf("Error resolving the label %s:%s", targetApp.Label, err)
+		return nil, fmt.Errorf("%w:%s", configrepo.ErrLabelNotFound, targetApp.Label)
+	}
+	for _, branchRef := range app.Branches {
+		reachable, err := s.isReachable(*labelHash, branchRef)
+		if err != nil {
+			return nil, err
+		}
+		if reachable {
+			return s.trustedLabelCommit(targetApp, *labelHash)
+		}
+	}
+	return nil, fmt.Errorf("%w:%s is not part of the %s history", configrepo.ErrLabelNotFound, targetApp.Label, targetApp.AppName)
+}
+
+type sharedLabelKey struct {
+	branch plumbing.ReferenceName
+	label  plumbing.Hash
+}
+
+// sharedLabelCommit returns the commit of the shared branch at the time of the labelled application commit:
+// the newest first-parent commit of the shared branch committed before (or with) the labelled commit, ErrFileNotFound if none
+func (s *snapshotReader) sharedLabelCommit(targetApp *configrepo.ApplicationVersion, branchRef *plumbing.Reference, remoteName string) (*object.Commit, error) {
+	labelCommit, err := s.labelCommit(targetApp, remoteName)
+	if err != nil {
+		return nil, err
+	}
+	key := sharedLabelKey{branch: branchRef.Name(), label: labelCommit.Hash}
+	if cachedHash, cached := s.sharedLabels.Load(key); cached {
+		if cachedHash.(plumbing.Hash).IsZero() {
+			return nil, configrepo.ErrFileNotFound
+		}
+		return s.repo.CommitObject(cachedHash.(plumbing.Hash))
+	}
+	commit, err := s.refCommit(branchRef)
+	for err == nil && commit.Committer.When.After(labelCommit.Committer.When) {
+		if commit.NumParents() == 0 {
+			s.sharedLabels.Store(key, plumbing.ZeroHash)
+			return nil, configrepo.ErrFileNotFound
+		}
+		commit, err = commit.Parent(0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.sharedLabels.Store(key, commit.Hash)
+	return commit, nil
+}
+
+// trustedLabelCommit returns the labelled commit, the untrusted commits are not found if the signature verification is enabled
+func (s *snapshotReader) trustedLabelCommit(targetApp *configrepo.ApplicationVersion, labelHash plumbing.Hash) (*object.Commit, error) {
+	commit, err := s.repo.CommitObject(labelHash)
+	if err != nil || s.verifier == nil {
+		return commit, err
+	}
+	err = s.verifier.verify(commit)
+	if err != nil {
+		logrus.Warnf("rejecting the label %s:%s", targetApp.Label, err)
+		return nil, fmt.Errorf("%w:%s is not trusted", configrepo.ErrLabelNotFound, targetApp.Label)
+	}
+	return commit, nil
+}
+
+// resolveLabel resolve a label giving the precedence to the remote branches and the tags
+func (s *snapshotReader) resolveLabel(label, remoteName string) (*plumbing.Hash, error) {
+	candidates := []plumbing.Revision{
+		plumbing.Revision(plumbing.NewRemoteReferenceName(remoteName, label)),
+		plumbing.Revision(plumbing.NewTagReferenceName(label)),
+	}
+	for _, candidate := range candidates {
+		if hash, err := s.repo.ResolveRevision(candidate); err == nil {
+			return hash, nil
+		}
+	}
+	return s.repo.ResolveRevision(plumbing.Revision(label))
+}
+
+// isReachable returns true if the commit is the reference commit or one of its ancestors
+func (s *snapshotReader) isReachable(hash plumbing.Hash, ref *plumbing.Reference) (bool, error) {
+	head, err := s.refCommit(ref)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	err = object.NewCommitPreorderIter(head, nil, nil).ForEach(func(commit *object.Commit) error {
+		if commit.Hash == hash {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return found, err
+}
+
+// commitTree returns the tree of a commit (annotated tags included), nil for an empty hash
+func (s *snapshotReader) commitTree(hash string) (*object.Tree, error) {
+	if hash == "" {
+		return nil, nil
+	}
+	commit, err := s.refCommit(plumbing.NewHashReference("", plumbing.NewHash(hash)))
+	if err != nil {
+		return nil, err
+	}
+	return commit.Tree()
+}
+
+// changedPaths returns the files changed between two commits
+func (s *snapshotReader) changedPaths(oldHash, newHash string) ([]string, error) {
+	oldTree, err := s.commitTree(oldHash)
+	if err != nil {
+		return nil, err
+	}
+	newTree, err := s.commitTree(newHash)
+	if err != nil {
+		return nil, err
+	}
+	treeChanges, err := object.DiffTree(oldTree, newTree)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]bool)
+	for _, treeChange := range treeChanges {
+		for _, name := range []string{treeChange.From.Name, treeChange.To.Name} {
+			if name != "" {
+				paths[name] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(paths))
+	for path := range paths {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// addChangedPaths set the changed paths of every change, they are left unknown (nil) in case of errors
+func (s *snapshotReader) addChangedPaths(changes []configrepo.Change) {
+	for i := range changes {
+		paths, err := s.changedPaths(changes[i].OldHash, changes[i].NewHash)
+		if err != nil {
+			logrus.Warnf("Error calculating the changed paths of %+v:%s", changes[i].ApplicationVersion, err)
+			continue
+		}
+		changes[i].ChangedPaths = paths
+	}
+}
@@ -0,0 +1,282 @@
+package gitconfigrepo
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pushReference temporary reference used to push the new commits
+const pushReference = plumbing.ReferenceName("refs/vecosy/push")
+
+// WriteFile commit a file to the application version branch and push it to the remote repo
+func (cr *GitConfigRepo) WriteFile(targetApp *configrepo.ApplicationVersion, path string, content []byte, opts *configrepo.WriteOptions) (string, error) {
+	if content == nil {
+		content = []byte{}
+	}
+	return cr.commitAndPush(targetApp, path, content, opts)
+}
+
+// DeleteFile commit the removal of a file to the application version branch and push it to the remote repo
+func (cr *GitConfigRepo) DeleteFile(targetApp *configrepo.ApplicationVersion, path string, opts *configrepo.WriteOptions) (string, error) {
+	return cr.commitAndPush(targetApp, path, nil, opts)
+}
+
+// commitAndPush commit the file content (nil to remove it) on top of the application version head
+//
+// the push is never forced, a head changed on the remote repo is reported as configrepo.ErrConflict.
+// The writes are not supported with the signature verification (see WithSignatureVerification) because the commits are not signed
+func (cr *GitConfigRepo) commitAndPush(targetApp *configrepo.ApplicationVersion, flPath string, content []byte, opts *configrepo.WriteOptions) (string, error) {
+	log := logrus.WithField("method", "commitAndPush").WithField("targetApp", targetApp).WithField("path", flPath)
+	cr.fetchMutex.Lock()
+	defer cr.fetchMutex.Unlock()
+	if cr.cloneOpts == nil {
+		return "", fmt.Errorf("%w:no remote information found", configrepo.ErrWriteNotSupported)
+	}
+	if cr.verifier != nil {
+		return "", fmt.Errorf("%w:the commits cannot be signed while the signature verification is enabled", configrepo.ErrWriteNotSupported)
+	}
+	pathParts, err := splitPath(flPath)
+	if err != nil {
+		return "", err
+	}
+	branchRef, err := cr.snapshot().exactBranch(targetApp)
+	if err != nil {
+		return "", err
+	}
+	if branchRef.Name().IsTag() {
+		return "", fmt.Errorf("%w:%s is a tag, only the branches can be changed", configrepo.ErrWriteNotSupported, branchRef.Name())
+	}
+	head := branchRef.Hash()
+	if opts.ExpectedVersion != "" && opts.ExpectedVersion != head.String() {
+		return "", fmt.Errorf("%w:the current version is %s", configrepo.ErrConflict, head)
+	}
+
+	parent, err := cr.repo.CommitObject(head)
+	if err != nil {
+		return "", err
+	}
+	treeHash, err := cr.updateCommitTree(parent, pathParts, content)
+	if err != nil {
+		return "", err
+	}
+	if treeHash == parent.TreeHash {
+		log.Info("nothing changed")
+		return head.String(), nil
+	}
+	commitHash, err := cr.storeCommit(parent, treeHash, opts)
+	if err != nil {
+		return "", err
+	}
+	branchName := cr.branchName(branchRef.Name())
+	err = cr.push(commitHash, branchName)
+	if err != nil {
+		log.Errorf("Error pushing %s:%s", commitHash, err)
+		return "", err
+	}
+	log.Infof("%s pushed to %s", commitHash, branchName)
+
+	err = cr.repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewRemoteReferenceName(cr.remoteName(), branchName), commitHash))
+	if err != nil {
+		return "", err
+	}
+	return commitHash.String(), cr.reload()
+}
+
+// exactBranch returns the reference of the application version (the nearest version is not considered)
+func (s *snapshot) exactBranch(targetApp *configrepo.ApplicationVersion) (*plumbing.Reference, error) {
+	app, appFound := s.apps[targetApp.AppName]
+	if !appFound {
+		return nil, configrepo.ErrApplicationNotFound
+	}
+	targetVersion, err := version.NewVersion(targetApp.AppVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, ver := range app.Versions {
+		if ver.Equal(targetVersion) {
+			return app.Branches[ver.Original()], nil
+		}
+	}
+	return nil, fmt.Errorf("%w:%s %s doesn't exist", configrepo.ErrVersionNotFound, targetApp.AppName, targetApp.AppVersion)
+}
+
+// branchName returns the branch name of the remote repo (i.e. app1/v1.0.0)
+func (cr *GitConfigRepo) branchName(refName plumbing.ReferenceName) string {
+	if refName.IsBranch() {
+		return refName.Short()
+	}
+	return strings.TrimPrefix(refName.String(), fmt.Sprintf("refs/remotes/%s/", cr.remoteName()))
+}
+
+func splitPath(flPath string) ([]string, error) {
+	cleanPath := strings.Trim(path.Clean("/"+flPath), "/")
+	if cleanPath == "" {
+		return nil, fmt.Errorf("%w:%s", configrepo.ErrInvalidPath, flPath)
+	}
+	return strings.Split(cleanPath, "/"), nil
+}
+
+func (cr *GitConfigRepo) push(commitHash plumbing.Hash, branchName string) error {
+	err := cr.repo.Storer.SetReference(plumbing.NewHashReference(pushReference, commitHash))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cr.repo.Storer.RemoveReference(pushReference)
+	}()
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", pushReference, plumbing.NewBranchReferenceName(branchName)))
+	err = cr.repo.Push(&git.PushOptions{RemoteName: cr.remoteName(), RefSpecs: []config.RefSpec{refSpec}, Auth: cr.cloneOpts.Auth})
+	// go-git doesn't define an error for the rejected updates
+	if err != nil && strings.Contains(err.Error(), "non-fast-forward") {
+		return fmt.Errorf("%w:%s has been changed on the remote repo", configrepo.ErrConflict, branchName)
+	}
+	return err
+}
+
+func (cr *GitConfigRepo) storeCommit(parent *object.Commit, treeHash plumbing.Hash, opts *configrepo.WriteOptions) (plumbing.Hash, error) {
+	signature := object.Signature{Name: opts.AuthorName, Email: opts.AuthorEmail, When: time.Now()}
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      opts.Message,
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash},
+	}
+	obj := cr.repo.Storer.NewEncodedObject()
+	err := commit.Encode(obj)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return cr.repo.Storer.SetEncodedObject(obj)
+}
+
+// updateCommitTree store the commit tree with the file changed and returns the new tree hash
+func (cr *GitConfigRepo) updateCommitTree(commit *object.Commit, pathParts []string, content []byte) (plumbing.Hash, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	var blobHash *plumbing.Hash
+	if content != nil {
+		hash, err := storeBlob(cr.repo.Storer, content)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		blobHash = &hash
+	}
+	entries, err := updateTree(cr.repo.Storer, tree, pathParts, blobHash)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return storeTree(cr.repo.Storer, entries)
+}
+
+// updateTree returns the tree entries with the file set to the blob (removed if the blob is nil), the changed sub-trees are stored
+func updateTree(s storer.EncodedObjectStorer, tree *object.Tree, pathParts []string, blobHash *plumbing.Hash) ([]object.TreeEntry, error) {
+	name := pathParts[0]
+	entries := make([]object.TreeEntry, 0, len(tree.Entries)+1)
+	var current *object.TreeEntry
+	for i := range tree.Entries {
+		if tree.Entries[i].Name == name {
+			current = &tree.Entries[i]
+			continue
+		}
+		entries = append(entries, tree.Entries[i])
+	}
+	isFolder := current != nil && current.Mode == filemode.Dir
+
+	if len(pathParts) == 1 {
+		switch {
+		case isFolder:
+			return nil, fmt.Errorf("%w:%s is a folder", configrepo.ErrInvalidPath, name)
+		case blobHash != nil:
+			mode := filemode.Regular
+			if current != nil {
+				mode = current.Mode
+			}
+			entries = append(entries, object.TreeEntry{Name: name, Mode: mode, Hash: *blobHash})
+		case current == nil:
+			return nil, configrepo.ErrFileNotFound
+		}
+		return sortTreeEntries(entries), nil
+	}
+
+	subTree := &object.Tree{}
+	if isFolder {
+		var err error
+		subTree, err = object.GetTree(s, current.Hash)
+		if err != nil {
+			return nil, err
+		}
+	} else if blobHash == nil {
+		return nil, configrepo.ErrFileNotFound
+	} else if current != nil {
+		return nil, fmt.Errorf("%w:%s is a file", configrepo.ErrInvalidPath, name)
+	}
+	subEntries, err := updateTree(s, subTree, pathParts[1:], blobHash)
+	if err != nil {
+		return nil, err
+	}
+	// git doesn't track empty folders
+	if len(subEntries) > 0 {
+		subTreeHash, err := storeTree(s, subEntries)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: subTreeHash})
+	}
+	return sortTreeEntries(entries), nil
+}
+
+// sortTreeEntries sort the entries following the git order (the folders are compared with a trailing slash)
+func sortTreeEntries(entries []object.TreeEntry) []object.TreeEntry {
+	sortName := func(entry object.TreeEntry) string {
+		if entry.Mode == filemode.Dir {
+			return entry.Name + "/"
+		}
+		return entry.Name
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return sortName(entries[i]) < sortName(entries[j])
+	})
+	return entries
+}
+
+func storeTree(s storer.EncodedObjectStorer, entries []object.TreeEntry) (plumbing.Hash, error) {
+	obj := s.NewEncodedObject()
+	err := (&object.Tree{Entries: entries}).Encode(obj)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
+
+func storeBlob(s storer.EncodedObjectStorer, content []byte) (plumbing.Hash, error) {
+	obj := s.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	_, err = w.Write(content)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	err = w.Close()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
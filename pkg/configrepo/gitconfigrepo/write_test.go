@@ -0,0 +1,125 @@
+package gitconfigrepo
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"testing"
+)
+
+func newWriteOptions(expectedVersion string) *configrepo.WriteOptions {
+	return &configrepo.WriteOptions{
+		AuthorName:      editorSignature.Name,
+		AuthorEmail:     editorSignature.Email,
+		Message:         "changed by vecosy",
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+func TestConfigRepo_WriteFile(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	writer := cfgRepo.(configrepo.Writer)
+	changes := make([]configrepo.Change, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change)
+	})
+
+	app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+	currentFile, err := cfgRepo.GetFile(app, "config.yml")
+	assert.NoError(t, err)
+	newVersion, err := writer.WriteFile(app, "config.yml", []byte("prop: written"), newWriteOptions(currentFile.Version))
+	assert.NoError(t, err)
+	assert.NotEqual(t, currentFile.Version, newVersion)
+	assert.Equal(t, "written", getConfigYml(t, cfgRepo, "app1", "v1.0.0")["prop"])
+	assert.Len(t, changes, 1)
+	assert.Equal(t, []string{"config.yml"}, changes[0].ChangedPaths)
+
+	remote, err := git.PlainOpen(remoteRepo)
+	assert.NoError(t, err)
+	remoteBranch, err := remote.Reference(plumbing.NewBranchReferenceName("app1/v1.0.0"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, newVersion, remoteBranch.Hash().String())
+	remoteCommit, err := remote.CommitObject(remoteBranch.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, editorSignature.Name, remoteCommit.Author.Name)
+	assert.Equal(t, "changed by vecosy", remoteCommit.Message)
+	assert.Equal(t, currentFile.Version, remoteCommit.ParentHashes[0].String())
+
+	// nested files
+	_, err = writer.WriteFile(app, "int/new/config.yml", []byte("prop: int"), newWriteOptions(""))
+	assert.NoError(t, err)
+	intFile, err := cfgRepo.GetFile(app, "int/new/config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "prop: int", string(intFile.Content))
+	assert.Equal(t, "written", getConfigYml(t, cfgRepo, "app1", "v1.0.0")["prop"])
+
+	_, err = writer.DeleteFile(app, "int/new/config.yml", newWriteOptions(intFile.Version))
+	assert.NoError(t, err)
+	_, err = cfgRepo.GetFile(app, "int/new/config.yml")
+	assert.Equal(t, configrepo.ErrFileNotFound, err)
+	_, err = writer.DeleteFile(app, "int/new/config.yml", newWriteOptions(""))
+	assert.Equal(t, configrepo.ErrFileNotFound, err)
+}
+
+func TestConfigRepo_WriteFile_Conflict(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	writer := cfgRepo.(configrepo.Writer)
+	app := configrepo.NewApplicationVersion("app1", "v1.0.0")
+	currentFile, err := cfgRepo.GetFile(app, "config.yml")
+	assert.NoError(t, err)
+
+	_, err = writer.WriteFile(app, "config.yml", []byte("prop: written"), newWriteOptions("0000000000000000000000000000000000000000"))
+	assert.True(t, errors.Is(err, configrepo.ErrConflict))
+
+	// the branch has been changed on the remote repo but not fetched yet
+	commitOnRemote(t, remoteRepo, "app1/v1.0.0", nil)
+	_, err = writer.WriteFile(app, "config.yml", []byte("prop: written"), newWriteOptions(currentFile.Version))
+	assert.True(t, errors.Is(err, configrepo.ErrConflict))
+
+	assert.NoError(t, cfgRepo.Fetch())
+	_, err = writer.WriteFile(app, "config.yml", []byte("prop: written"), newWriteOptions(""))
+	assert.NoError(t, err)
+	assert.Equal(t, "written", getConfigYml(t, cfgRepo, "app1", "v1.0.0")["prop"])
+}
+
+func TestConfigRepo_WriteFile_InvalidTarget(t *testing.T) {
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	writer := cfgRepo.(configrepo.Writer)
+
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app1", "v1.0.1"), "config.yml", []byte{}, newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrWriteNotSupported))
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app1", "v2.0.0"), "config.yml", []byte{}, newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrVersionNotFound))
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app2", "v1.0.0"), "config.yml", []byte{}, newWriteOptions(""))
+	assert.Equal(t, configrepo.ErrApplicationNotFound, err)
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml/sub.yml", []byte{}, newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrInvalidPath))
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "/", []byte{}, newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrInvalidPath))
+}
+
+func TestConfigRepo_WriteFile_SignatureVerification(t *testing.T) {
+	_, armoredKeyRing := newSigningKey(t)
+	localRepo, remoteRepo := InitRepos(t)
+	cfgRepo, err := NewGitConfigRepo(localRepo, &git.CloneOptions{URL: remoteRepo}, WithSignatureVerification(armoredKeyRing, FailClosed))
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.Init())
+	writer := cfgRepo.(configrepo.Writer)
+
+	// the written commits wouldn't be signed
+	_, err = writer.WriteFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml", []byte("prop: written"), newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrWriteNotSupported))
+	_, err = writer.DeleteFile(configrepo.NewApplicationVersion("app1", "v1.0.0"), "config.yml", newWriteOptions(""))
+	assert.True(t, errors.Is(err, configrepo.ErrWriteNotSupported))
+}
@@ -15,6 +15,8 @@ type RepoFile struct {
 type ApplicationVersion struct {
 	AppName    string
 	AppVersion string
+	// Label optional revision (branch, tag or commit hash) to read instead of the application version head
+	Label string
 }
 
 // NewApplicationVersion create a new ApplicationVersion (name+version) instance
@@ -25,8 +27,17 @@ func NewApplicationVersion(name, version string) *ApplicationVersion {
 	}
 }
 
-// OnChangeHandler function handler executed on every repo changes
-type OnChangeHandler func(changedApplication ApplicationVersion)
+// NewApplicationVersionAtLabel create a new ApplicationVersion that will be read at a specific label (branch, tag or commit hash)
+func NewApplicationVersionAtLabel(name, version, label string) *ApplicationVersion {
+	return &ApplicationVersion{
+		AppName:    name,
+		AppVersion: version,
+		Label:      label,
+	}
+}
+
+// OnChangeHandler function handler executed for every changed application version
+type OnChangeHandler func(change Change)
 
 // Repo represent a config repository
 type Repo interface {
@@ -39,3 +50,33 @@ type Repo interface {
 	StopFetching()
 	AddOnChangeHandler(handler OnChangeHandler)
 }
+
+// ReferenceMatcher is implemented by the repos that follow a reference naming convention (i.e. git branches and tags)
+type ReferenceMatcher interface {
+	// MatchReference returns true if the reference (i.e. refs/heads/app1/1.0.0) represent an application version
+	MatchReference(refName string) bool
+}
+
+// WriteOptions represent the author and the concurrency check of a configuration change
+type WriteOptions struct {
+	AuthorName  string
+	AuthorEmail string
+	Message     string
+	// ExpectedVersion the version (i.e. commit hash) the change is based on, ErrConflict is returned if the application version has been changed meanwhile (no check if empty)
+	ExpectedVersion string
+}
+
+// Writer is implemented by the repos that can persist the configuration changes
+type Writer interface {
+	// WriteFile create or replace a file of an existing application version and returns the new version
+	WriteFile(app *ApplicationVersion, path string, content []byte, opts *WriteOptions) (string, error)
+	// DeleteFile remove a file of an existing application version and returns the new version
+	DeleteFile(app *ApplicationVersion, path string, opts *WriteOptions) (string, error)
+}
+
+// SharedRepo is implemented by the repos with shared files merged underneath every application (i.e. a `_common/<version>` branch)
+type SharedRepo interface {
+	// GetSharedFile retrieve a shared file inherited by the application version, ErrFileNotFound if the application has no shared file with that path.
+	// The shared files of a labelled application version are read as they were when the labelled commit was committed
+	GetSharedFile(app *ApplicationVersion, path string) (*RepoFile, error)
+}
@@ -0,0 +1,88 @@
+package memconfigrepo
+
+import (
+	"github.com/vecosy/vecosy/v2/internal/validation"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"path"
+)
+
+// AddVersion add an (empty) application version, the application will be created if it doesn't exist
+func (cr *MemConfigRepo) AddVersion(appName, appVersion string) error {
+	err := validation.ValidateApplicationVersion(configrepo.NewApplicationVersion(appName, appVersion))
+	if err != nil {
+		return err
+	}
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	cr.draftVersion(appName, appVersion)
+	return nil
+}
+
+// SetFile add or replace a file on an application version, the application version will be created if it doesn't exist
+func (cr *MemConfigRepo) SetFile(appName, appVersion, filePath string, content []byte) error {
+	err := validation.ValidateApplicationVersion(configrepo.NewApplicationVersion(appName, appVersion))
+	if err != nil {
+		return err
+	}
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	fileContent := make([]byte, len(content))
+	copy(fileContent, content)
+	cr.draftVersion(appName, appVersion)[cleanPath(filePath)] = fileContent
+	return nil
+}
+
+// RemoveFile remove a file from an application version
+func (cr *MemConfigRepo) RemoveFile(appName, appVersion, filePath string) error {
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	files, found := cr.draft[appName][appVersion]
+	if !found {
+		return configrepo.ErrApplicationNotFound
+	}
+	flPath := cleanPath(filePath)
+	if _, found := files[flPath]; !found {
+		return configrepo.ErrFileNotFound
+	}
+	delete(files, flPath)
+	return nil
+}
+
+// RemoveVersion remove an application version with all its files
+func (cr *MemConfigRepo) RemoveVersion(appName, appVersion string) error {
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	if _, found := cr.draft[appName][appVersion]; !found {
+		return configrepo.ErrApplicationNotFound
+	}
+	delete(cr.draft[appName], appVersion)
+	if len(cr.draft[appName]) == 0 {
+		delete(cr.draft, appName)
+	}
+	return nil
+}
+
+// RemoveApp remove an application with all its versions
+func (cr *MemConfigRepo) RemoveApp(appName string) error {
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	if _, found := cr.draft[appName]; !found {
+		return configrepo.ErrApplicationNotFound
+	}
+	delete(cr.draft, appName)
+	return nil
+}
+
+func (cr *MemConfigRepo) draftVersion(appName, appVersion string) map[string][]byte {
+	if _, found := cr.draft[appName]; !found {
+		cr.draft[appName] = make(map[string]map[string][]byte)
+	}
+	if _, found := cr.draft[appName][appVersion]; !found {
+		cr.draft[appName][appVersion] = make(map[string][]byte)
+	}
+	return cr.draft[appName][appVersion]
+}
+
+func cleanPath(filePath string) string {
+	return path.Clean("/" + filePath)[1:]
+}
@@ -0,0 +1,38 @@
+package memconfigrepo
+
+import (
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+)
+
+func (cr *MemConfigRepo) getNearestVersion(targetApp *configrepo.ApplicationVersion) (*appVersion, error) {
+	cr.appsMutex.RLock()
+	defer cr.appsMutex.RUnlock()
+	app, appFound := cr.apps[targetApp.AppName]
+	if !appFound {
+		return nil, configrepo.ErrApplicationNotFound
+	}
+	nearestVersion, err := configrepo.FindNearestVersion(app.Versions, targetApp.AppVersion)
+	if err != nil {
+		return nil, err
+	}
+	return app.Folders[nearestVersion.Original()], nil
+}
+
+// GetFile retrieve a published file from the in-memory repo
+func (cr *MemConfigRepo) GetFile(targetApp *configrepo.ApplicationVersion, filePath string) (*configrepo.RepoFile, error) {
+	if targetApp.Label != "" {
+		// only the last published snapshot is kept
+		return nil, configrepo.ErrLabelNotSupported
+	}
+	appVer, err := cr.getNearestVersion(targetApp)
+	if err != nil {
+		return nil, err
+	}
+	content, found := appVer.Files[cleanPath(filePath)]
+	if !found {
+		return nil, configrepo.ErrFileNotFound
+	}
+	result := &configrepo.RepoFile{Version: appVer.Hash, Content: make([]byte, len(content))}
+	copy(result.Content, content)
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package memconfigrepo
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"sync"
+	"time"
+)
+
+type appVersion struct {
+	Version *version.Version
+	Files   map[string][]byte
+	Hash    string
+}
+
+type app struct {
+	Name     string
+	Folders  map[string]*appVersion
+	Versions []*version.Version
+}
+
+// MemConfigRepo represent an in-memory configuration repository
+//
+// the changes made by SetFile/RemoveFile/RemoveVersion/RemoveApp are served and notified only after a Publish (or Fetch)
+type MemConfigRepo struct {
+	draft           map[string]map[string]map[string][]byte
+	draftMutex      sync.Mutex
+	apps            map[string]*app
+	appsMutex       sync.RWMutex
+	publishMutex    sync.Mutex
+	fetchCh         chan bool
+	lastFetch       *time.Time
+	lastFetchMutex  sync.Mutex
+	changesHandlers []configrepo.OnChangeHandler
+	handlersMutex   sync.Mutex
+}
+
+// NewMemConfigRepo instantiate a new empty in-memory configuration repository
+func NewMemConfigRepo() *MemConfigRepo {
+	return &MemConfigRepo{
+		draft:           make(map[string]map[string]map[string][]byte),
+		apps:            make(map[string]*app),
+		fetchCh:         make(chan bool),
+		changesHandlers: make([]configrepo.OnChangeHandler, 0),
+	}
+}
+
+// Init publish the files added before the initialization
+func (cr *MemConfigRepo) Init() error {
+	cr.publishMutex.Lock()
+	defer cr.publishMutex.Unlock()
+	cr.appsMutex.Lock()
+	defer cr.appsMutex.Unlock()
+	cr.apps = cr.buildApps()
+	return nil
+}
+
+// GetAppsVersions returns a appName-> list of version
+func (cr *MemConfigRepo) GetAppsVersions() map[string][]*version.Version {
+	cr.appsMutex.RLock()
+	defer cr.appsMutex.RUnlock()
+	result := make(map[string][]*version.Version)
+	for appName, app := range cr.apps {
+		result[appName] = app.Versions
+	}
+	return result
+}
+
+// AddOnChangeHandler add a new change handler to the in-memory repo
+func (cr *MemConfigRepo) AddOnChangeHandler(handler configrepo.OnChangeHandler) {
+	cr.handlersMutex.Lock()
+	defer cr.handlersMutex.Unlock()
+	cr.changesHandlers = append(cr.changesHandlers, handler)
+}
@@ -0,0 +1,34 @@
+package memconfigrepo
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// InitRepo create a new in-memory repo with app1 (v1.0.0,v1.0.1,v6.0.0)
+func InitRepo(t *testing.T) *MemConfigRepo {
+	cfgRepo := NewMemConfigRepo()
+	for _, ver := range []string{"1.0.0", "1.0.1", "6.0.0"} {
+		assert.NoError(t, cfgRepo.SetFile("app1", ver, "config.yml", []byte(fmt.Sprintf("environment: dev\nver: %s", ver))))
+		assert.NoError(t, cfgRepo.SetFile("app1", ver, "dev/config.yml", []byte("environment: dev")))
+	}
+	assert.NoError(t, cfgRepo.Init())
+	return cfgRepo
+}
+
+func TestNewMemConfigRepo(t *testing.T) {
+	cfgRepo := InitRepo(t)
+	versions := cfgRepo.GetAppsVersions()["app1"]
+	assert.Len(t, versions, 3)
+	assert.Equal(t, "6.0.0", versions[0].Original())
+	assert.Equal(t, "1.0.1", versions[1].Original())
+	assert.Equal(t, "1.0.0", versions[2].Original())
+}
+
+func TestMemConfigRepo_AddVersion_Invalid(t *testing.T) {
+	cfgRepo := NewMemConfigRepo()
+	assert.Error(t, cfgRepo.AddVersion("app1", "notAVersion"))
+	assert.Error(t, cfgRepo.AddVersion("", "1.0.0"))
+	assert.Error(t, cfgRepo.SetFile("app1", "notAVersion", "config.yml", []byte{}))
+}
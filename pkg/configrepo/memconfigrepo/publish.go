@@ -0,0 +1,168 @@
+package memconfigrepo
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"io"
+	"sort"
+	"time"
+)
+
+// Publish make the pending changes visible and notify them to the change handlers
+//
+// the handlers are called after the publication, so they can read the repository and publish again
+func (cr *MemConfigRepo) Publish() {
+	changes := cr.publishDraft()
+	if len(changes) > 0 {
+		cr.callChangeHandlers(changes)
+	} else {
+		logrus.Debugf("no changes detected")
+	}
+}
+
+// publishDraft replace the published applications with a copy of the draft and returns the changes
+func (cr *MemConfigRepo) publishDraft() []configrepo.Change {
+	cr.publishMutex.Lock()
+	defer cr.publishMutex.Unlock()
+	newApps := cr.buildApps()
+	cr.appsMutex.Lock()
+	defer cr.appsMutex.Unlock()
+	changes := configrepo.DetectChanges(appsHashes(cr.apps), appsHashes(newApps))
+	addChangedPaths(changes, cr.apps, newApps)
+	cr.apps = newApps
+	return changes
+}
+
+// StartFetchingEvery start publishing the pending changes periodically
+func (cr *MemConfigRepo) StartFetchingEvery(period time.Duration) error {
+	t := time.NewTicker(period)
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				_ = cr.Fetch()
+			case <-cr.fetchCh:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// StopFetching stop the periodic publishing
+func (cr *MemConfigRepo) StopFetching() {
+	cr.fetchCh <- true
+}
+
+// Fetch publish the pending changes
+func (cr *MemConfigRepo) Fetch() error {
+	cr.Publish()
+	cr.updateLastFetch()
+	return nil
+}
+
+func (cr *MemConfigRepo) updateLastFetch() {
+	cr.lastFetchMutex.Lock()
+	defer cr.lastFetchMutex.Unlock()
+	lastFetch := time.Now()
+	cr.lastFetch = &lastFetch
+}
+
+// GetLastFetch return the last fetch date
+func (cr *MemConfigRepo) GetLastFetch() *time.Time {
+	cr.lastFetchMutex.Lock()
+	defer cr.lastFetchMutex.Unlock()
+	return cr.lastFetch
+}
+
+// buildApps create an immutable copy of the draft
+func (cr *MemConfigRepo) buildApps() map[string]*app {
+	cr.draftMutex.Lock()
+	defer cr.draftMutex.Unlock()
+	newApps := make(map[string]*app)
+	for appName, versions := range cr.draft {
+		newApp := &app{Name: appName, Folders: make(map[string]*appVersion), Versions: make([]*version.Version, 0)}
+		for strVersion, files := range versions {
+			ver, err := version.NewVersion(strVersion)
+			if err != nil {
+				logrus.Warnf("Invalid application version:%s err:%s", strVersion, err)
+				continue
+			}
+			filesCopy := make(map[string][]byte)
+			for flPath, content := range files {
+				filesCopy[flPath] = content
+			}
+			newApp.Versions = append(newApp.Versions, ver)
+			newApp.Folders[strVersion] = &appVersion{Version: ver, Files: filesCopy, Hash: hashFiles(filesCopy)}
+		}
+		sort.Sort(version.Collection(newApp.Versions))
+		utils.ReverseVersion(newApp.Versions)
+		newApps[appName] = newApp
+	}
+	return newApps
+}
+
+// hashFiles calculate a hash of the files paths and contents, every path is NUL terminated and followed by the
+// (fixed length) content hash so different file sets never produce the same input
+func hashFiles(files map[string][]byte) string {
+	paths := make([]string, 0, len(files))
+	for flPath := range files {
+		paths = append(paths, flPath)
+	}
+	sort.Strings(paths)
+	hash := sha1.New()
+	for _, flPath := range paths {
+		flHash := sha1.Sum(files[flPath])
+		_, _ = io.WriteString(hash, flPath+"\x00")
+		_, _ = hash.Write(flHash[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// appsHashes returns the content hash of every application version
+func appsHashes(apps map[string]*app) configrepo.AppsHashes {
+	result := make(configrepo.AppsHashes)
+	for appName, app := range apps {
+		result[appName] = make(map[string]string)
+		for verName, appVer := range app.Folders {
+			result[appName][verName] = appVer.Hash
+		}
+	}
+	return result
+}
+
+// addChangedPaths set the files changed between the old and the new content of every change
+func addChangedPaths(changes []configrepo.Change, oldApps, newApps map[string]*app) {
+	versionsFiles := make(map[string]map[string]string)
+	for _, apps := range []map[string]*app{oldApps, newApps} {
+		for _, app := range apps {
+			for _, appVer := range app.Folders {
+				files := make(map[string]string)
+				for flPath, content := range appVer.Files {
+					files[flPath] = string(content)
+				}
+				versionsFiles[appVer.Hash] = files
+			}
+		}
+	}
+	for i := range changes {
+		changes[i].ChangedPaths = configrepo.DiffFiles(versionsFiles[changes[i].OldHash], versionsFiles[changes[i].NewHash])
+	}
+}
+
+func (cr *MemConfigRepo) callChangeHandlers(changes []configrepo.Change) {
+	logrus.Debugf("callChangeHandlers: %+v", changes)
+	cr.handlersMutex.Lock()
+	handlers := cr.changesHandlers
+	cr.handlersMutex.Unlock()
+	for _, chHandler := range handlers {
+		for _, change := range changes {
+			chHandler(change)
+		}
+	}
+}
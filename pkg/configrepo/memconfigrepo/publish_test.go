@@ -0,0 +1,129 @@
+package memconfigrepo
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/merger"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemConfigRepo_Publish(t *testing.T) {
+	cfgRepo := InitRepo(t)
+	changes := make([]configrepo.ApplicationVersion, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change.ApplicationVersion)
+	})
+
+	assert.NoError(t, cfgRepo.SetFile("app1", "1.0.0", "config.yml", []byte("prop3: value3")))
+	assert.Nil(t, getConfigYml(t, cfgRepo, "app1", "1.0.0")["prop3"])
+	cfgRepo.Publish()
+	assert.Equal(t, "value3", getConfigYml(t, cfgRepo, "app1", "1.0.0")["prop3"])
+	assert.Equal(t, []configrepo.ApplicationVersion{{AppName: "app1", AppVersion: "1.0.0"}}, changes)
+
+	changes = changes[:0]
+	cfgRepo.Publish()
+	assert.Empty(t, changes)
+
+	assert.NoError(t, cfgRepo.SetFile("app2", "2.0.0", "config.yml", []byte("prop: app2")))
+	assert.NoError(t, cfgRepo.Fetch())
+	assert.NotNil(t, cfgRepo.GetLastFetch())
+	assert.Equal(t, "app2", getConfigYml(t, cfgRepo, "app2", "2.0.0")["prop"])
+	assert.Equal(t, []configrepo.ApplicationVersion{{AppName: "app2", AppVersion: "2.0.0"}}, changes)
+}
+
+func TestMemConfigRepo_Publish_FromHandler(t *testing.T) {
+	cfgRepo := InitRepo(t)
+	changes := make([]configrepo.ApplicationVersion, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change.ApplicationVersion)
+		if change.AppName == "app1" {
+			// a handler can publish a follow-up change without deadlocking
+			assert.NoError(t, cfgRepo.SetFile("app2", "2.0.0", "config.yml", []byte("prop: app2")))
+			cfgRepo.Publish()
+		}
+	})
+
+	assert.NoError(t, cfgRepo.SetFile("app1", "1.0.0", "config.yml", []byte("prop3: value3")))
+	done := make(chan bool)
+	go func() {
+		cfgRepo.Publish()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.FailNow(t, "publish deadlocked")
+	}
+	assert.Equal(t, "app2", getConfigYml(t, cfgRepo, "app2", "2.0.0")["prop"])
+	assert.Equal(t, []configrepo.ApplicationVersion{{AppName: "app1", AppVersion: "1.0.0"}, {AppName: "app2", AppVersion: "2.0.0"}}, changes)
+}
+
+func TestMemConfigRepo_Remove(t *testing.T) {
+	cfgRepo := InitRepo(t)
+	changes := make([]configrepo.Change, 0)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {
+		changes = append(changes, change)
+	})
+	v101File, err := cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "1.0.1"), "config.yml")
+	assert.NoError(t, err)
+	assert.NoError(t, cfgRepo.RemoveFile("app1", "1.0.0", "dev/config.yml"))
+	assert.NoError(t, cfgRepo.RemoveVersion("app1", "6.0.0"))
+	cfgRepo.Publish()
+	_, err = cfgRepo.GetFile(configrepo.NewApplicationVersion("app1", "1.0.0"), "dev/config.yml")
+	assert.Equal(t, configrepo.ErrFileNotFound, err)
+	assert.Equal(t, "1.0.1", getConfigYml(t, cfgRepo, "app1", "10.0.0")["ver"])
+	assert.Len(t, changes, 2)
+	assert.Equal(t, configrepo.VersionUpdated, changes[0].Kind)
+	assert.Equal(t, "1.0.0", changes[0].AppVersion)
+	assert.Equal(t, []string{"dev/config.yml"}, changes[0].ChangedPaths)
+	assert.False(t, merger.AffectsEnvironment(cfgRepo, changes[0], "int"))
+	assert.Equal(t, configrepo.VersionRemoved, changes[1].Kind)
+	assert.Equal(t, "6.0.0", changes[1].AppVersion)
+	assert.Equal(t, v101File.Version, changes[1].NewHash)
+
+	changes = changes[:0]
+	assert.NoError(t, cfgRepo.RemoveApp("app1"))
+	assert.Equal(t, configrepo.ErrApplicationNotFound, cfgRepo.RemoveApp("app1"))
+	cfgRepo.Publish()
+	assert.NotContains(t, cfgRepo.GetAppsVersions(), "app1")
+	assert.Len(t, changes, 2)
+	for _, change := range changes {
+		assert.Equal(t, configrepo.VersionRemoved, change.Kind)
+		assert.Empty(t, change.NewHash)
+	}
+}
+
+func TestMemConfigRepo_Concurrency(t *testing.T) {
+	cfgRepo := InitRepo(t)
+	cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {})
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, cfgRepo.SetFile("app1", "1.0.0", "config.yml", []byte(fmt.Sprintf("ver: %d", i))))
+			cfgRepo.Publish()
+			cfgRepo.AddOnChangeHandler(func(change configrepo.Change) {})
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := cfgRepo.GetFile(app, "config.yml")
+			assert.NoError(t, err)
+			assert.Contains(t, cfgRepo.GetAppsVersions(), "app1")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHashFiles(t *testing.T) {
+	// moving bytes between a path and its content changes the hash
+	assert.NotEqual(t, hashFiles(map[string][]byte{"a.yml": []byte("b: c")}), hashFiles(map[string][]byte{"a.ymlb": []byte(": c")}))
+	assert.NotEqual(t,
+		hashFiles(map[string][]byte{"a.yml": []byte("b.yml"), "c.yml": []byte("")}),
+		hashFiles(map[string][]byte{"a.yml": []byte(""), "b.ymlc.yml": []byte("")}))
+	assert.Equal(t, hashFiles(map[string][]byte{"a.yml": []byte("b: c")}), hashFiles(map[string][]byte{"a.yml": []byte("b: c")}))
+}
@@ -0,0 +1,16 @@
+package configrepo
+
+import "errors"
+
+// GetFileOrShared retrieve a file of the application version, if the application doesn't contain it the shared file is returned (if the repo is a SharedRepo)
+func GetFileOrShared(repo Repo, app *ApplicationVersion, path string) (*RepoFile, error) {
+	file, err := repo.GetFile(app, path)
+	if !errors.Is(err, ErrFileNotFound) {
+		return file, err
+	}
+	sharedRepo, isShared := repo.(SharedRepo)
+	if !isShared {
+		return nil, err
+	}
+	return sharedRepo.GetSharedFile(app, path)
+}
@@ -0,0 +1,20 @@
+package configrepo
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-version"
+)
+
+// FindNearestVersion retrieve the nearest (<=) version from a list of versions sorted in descending order
+func FindNearestVersion(versions []*version.Version, targetVersion string) (*version.Version, error) {
+	constraint, err := version.NewConstraint(fmt.Sprintf("<=%s", targetVersion))
+	if err != nil {
+		return nil, err
+	}
+	for _, chkVer := range versions {
+		if constraint.Check(chkVer) {
+			return chkVer, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for target version:%s", ErrVersionNotFound, targetVersion)
+}
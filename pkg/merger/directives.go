@@ -0,0 +1,391 @@
+package merger
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// merge directives, declared by the yaml tags (i.e. `features: !append [f3]`) or by the manifest merge rules
+const (
+	// DeleteDirective deletes the key (or, in a list merged by key, the matching item)
+	DeleteDirective = "delete"
+	// AppendDirective appends the list items to the existing list
+	AppendDirective = "append"
+	// PrependDirective prepends the list items to the existing list
+	PrependDirective = "prepend"
+	// MergeDirective merges the list items by a key (i.e. `merge:name`), the items that don't match are appended
+	MergeDirective = "merge"
+)
+
+// ErrInvalidDirective returned if a merge directive is unknown or it's applied to an unsupported value
+var ErrInvalidDirective = fmt.Errorf("invalid merge directive")
+
+type directive struct {
+	name string
+	// key the item key of the merge directive
+	key string
+}
+
+func (d *directive) String() string {
+	if d.name == MergeDirective {
+		return fmt.Sprintf("%s:%s", d.name, d.key)
+	}
+	return d.name
+}
+
+func parseDirective(value string) (*directive, error) {
+	parts := strings.SplitN(value, ":", 2)
+	switch {
+	case parts[0] == MergeDirective && len(parts) == 2 && parts[1] != "":
+		return &directive{name: MergeDirective, key: parts[1]}, nil
+	case len(parts) == 1 && (value == DeleteDirective || value == AppendDirective || value == PrependDirective):
+		return &directive{name: value}, nil
+	default:
+		return nil, fmt.Errorf("%w:%s", ErrInvalidDirective, value)
+	}
+}
+
+// directiveValue a source value tagged by a merge directive
+type directiveValue struct {
+	directive *directive
+	value     interface{}
+}
+
+// parseYAML parse the first document of a yaml source (see parseYAMLDocuments)
+func parseYAML(content []byte) (map[interface{}]interface{}, error) {
+	documents, err := parseYAMLDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	return documents[0], nil
+}
+
+// parseYAMLDocuments parse every document (separated by `---`) of a yaml source, an empty source has a single empty document.
+// The values tagged by a merge directive are wrapped by a directiveValue and the ones tagged by a source tag (see IncludeTag) by an includeValue
+func parseYAMLDocuments(content []byte) ([]map[interface{}]interface{}, error) {
+	documents := make([]map[interface{}]interface{}, 0, 1)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	// the tags are dropped by yaml.v2, they are read from the yaml.v3 document tree
+	nodeDecoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	for {
+		config := make(map[interface{}]interface{})
+		err := decoder.Decode(config)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		node := &yamlv3.Node{}
+		err = nodeDecoder.Decode(node)
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range node.Content {
+			err = applyTags(config, root, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		documents = append(documents, config)
+	}
+	if len(documents) == 0 {
+		documents = append(documents, make(map[interface{}]interface{}))
+	}
+	return documents, nil
+}
+
+// applyTags wraps the config values whose node has a local tag (i.e. `!append` or `!include`)
+func applyTags(config map[interface{}]interface{}, node *yamlv3.Node, path []interface{}) error {
+	if len(path) > 0 && strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+		tag := strings.TrimPrefix(node.Tag, "!")
+		if isIncludeTag(tag) {
+			if node.Kind != yamlv3.ScalarNode {
+				return fmt.Errorf("line %d: !%s requires a scalar value", node.Line, tag)
+			}
+			wrapValue(config, path, func(value interface{}) interface{} {
+				return &includeValue{tag: tag, arg: node.Value, line: node.Line}
+			})
+			return nil
+		}
+		tagDirective, err := parseDirective(tag)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		wrapValue(config, path, func(value interface{}) interface{} {
+			return &directiveValue{directive: tagDirective, value: value}
+		})
+	}
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childPath := append(append(make([]interface{}, 0, len(path)+1), path...), node.Content[i].Value)
+			if err := applyTags(config, node.Content[i+1], childPath); err != nil {
+				return err
+			}
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			childPath := append(append(make([]interface{}, 0, len(path)+1), path...), i)
+			if err := applyTags(config, item, childPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wrapValue replace the config value at the path (map keys and list indexes) with its wrapper (i.e. a directiveValue)
+func wrapValue(config map[interface{}]interface{}, path []interface{}, wrap func(value interface{}) interface{}) {
+	var parent interface{} = config
+	for i, step := range path {
+		var value interface{}
+		var set func(interface{})
+		switch typedParent := unwrap(parent).(type) {
+		case map[interface{}]interface{}:
+			key, found := findKey(typedParent, step)
+			if !found {
+				return
+			}
+			value = typedParent[key]
+			set = func(v interface{}) { typedParent[key] = v }
+		case []interface{}:
+			index, isIndex := step.(int)
+			if !isIndex || index >= len(typedParent) {
+				return
+			}
+			value = typedParent[index]
+			set = func(v interface{}) { typedParent[index] = v }
+		default:
+			return
+		}
+		if i == len(path)-1 {
+			set(wrap(value))
+			return
+		}
+		parent = value
+	}
+}
+
+// findKey returns the map key matching the yaml key (yaml.v2 decodes the keys as int, bool, ...)
+func findKey(config map[interface{}]interface{}, yamlKey interface{}) (interface{}, bool) {
+	if _, found := config[yamlKey]; found {
+		return yamlKey, true
+	}
+	for key := range config {
+		if fmt.Sprint(key) == fmt.Sprint(yamlKey) {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func unwrap(value interface{}) interface{} {
+	if dirValue, isDirective := value.(*directiveValue); isDirective {
+		return dirValue.value
+	}
+	return value
+}
+
+// mergeConfig merge the src configuration over the dst one, the values override the existing ones and the maps are merged recursively
+// unless a merge directive (yaml tag or manifest rule) is declared for the key
+func mergeConfig(dst, src map[interface{}]interface{}, path string, rules *MergeRules) error {
+	for key, srcValue := range src {
+		keyPath := fmt.Sprint(key)
+		if path != "" {
+			keyPath = path + "." + keyPath
+		}
+		keyDirective := rules.listDirective(keyPath)
+		if dirValue, isDirective := srcValue.(*directiveValue); isDirective {
+			keyDirective = dirValue.directive
+			srcValue = dirValue.value
+		}
+		if (keyDirective != nil && keyDirective.name == DeleteDirective) || (srcValue == nil && rules.StrictNull) {
+			delete(dst, key)
+			continue
+		}
+		if srcValue == nil {
+			continue
+		}
+		value, err := mergeValue(dst[key], srcValue, keyPath, keyDirective, rules)
+		if err != nil {
+			return err
+		}
+		dst[key] = value
+	}
+	return nil
+}
+
+func mergeValue(dstValue, srcValue interface{}, path string, valueDirective *directive, rules *MergeRules) (interface{}, error) {
+	switch typedSrc := srcValue.(type) {
+	case map[interface{}]interface{}:
+		if valueDirective != nil {
+			return nil, fmt.Errorf("%w:%s %s is applicable to the lists only", ErrInvalidDirective, path, valueDirective)
+		}
+		dstMap, isMap := dstValue.(map[interface{}]interface{})
+		if !isMap {
+			dstMap = make(map[interface{}]interface{})
+		}
+		return dstMap, mergeConfig(dstMap, typedSrc, path, rules)
+	case []interface{}:
+		dstList, _ := dstValue.([]interface{})
+		if valueDirective != nil && valueDirective.name == MergeDirective {
+			return mergeByKey(dstList, typedSrc, path, valueDirective.key, rules)
+		}
+		items, err := resolveItems(typedSrc, path, rules)
+		if err != nil {
+			return nil, err
+		}
+		if valueDirective == nil {
+			return items, nil
+		}
+		switch valueDirective.name {
+		case AppendDirective:
+			return append(append(make([]interface{}, 0, len(dstList)+len(items)), dstList...), items...), nil
+		case PrependDirective:
+			return append(items, dstList...), nil
+		}
+	default:
+		if valueDirective == nil {
+			return srcValue, nil
+		}
+	}
+	return nil, fmt.Errorf("%w:%s %s is applicable to the lists only", ErrInvalidDirective, path, valueDirective)
+}
+
+// resolveItems returns a copy of the list items without the directives
+func resolveItems(items []interface{}, path string, rules *MergeRules) ([]interface{}, error) {
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		if dirValue, isDirective := item.(*directiveValue); isDirective {
+			return nil, fmt.Errorf("%w:%s[%d] %s is applicable to the lists merged by key only", ErrInvalidDirective, path, i, dirValue.directive)
+		}
+		value, err := mergeValue(nil, item, path, nil, rules)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+// mergeByKey merge the items with the same key value, the other ones are appended
+// an item tagged by !delete removes the matching item
+func mergeByKey(dstList, srcItems []interface{}, path, key string, rules *MergeRules) ([]interface{}, error) {
+	result := append(make([]interface{}, 0, len(dstList)+len(srcItems)), dstList...)
+	for i, item := range srcItems {
+		deleteItem := false
+		if dirValue, isDirective := item.(*directiveValue); isDirective {
+			if dirValue.directive.name != DeleteDirective {
+				return nil, fmt.Errorf("%w:%s[%d] %s is applicable to the lists only", ErrInvalidDirective, path, i, dirValue.directive)
+			}
+			deleteItem = true
+			item = dirValue.value
+		}
+		index := indexByKey(result, key, item)
+		switch {
+		case deleteItem:
+			if index >= 0 {
+				result = append(result[:index], result[index+1:]...)
+			}
+		case index >= 0:
+			value, err := mergeValue(result[index], item, path, nil, rules)
+			if err != nil {
+				return nil, err
+			}
+			result[index] = value
+		default:
+			value, err := mergeValue(nil, item, path, nil, rules)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// indexByKey returns the index of the list item with the same key value of the item, -1 if none
+func indexByKey(list []interface{}, key string, item interface{}) int {
+	itemMap, isMap := item.(map[interface{}]interface{})
+	if !isMap {
+		return -1
+	}
+	id, found := itemMap[key]
+	if !found {
+		return -1
+	}
+	for i, listItem := range list {
+		if listMap, isMap := listItem.(map[interface{}]interface{}); isMap {
+			if listID, found := listMap[key]; found && reflect.DeepEqual(id, listID) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// hasDirectives returns true if a value of the configuration (or of its lists) is tagged by a merge directive
+func hasDirectives(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case *directiveValue:
+		return true
+	case map[interface{}]interface{}:
+		for _, child := range typedValue {
+			if hasDirectives(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range typedValue {
+			if hasDirectives(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stripDirectives returns the configuration without the directives, the deleted keys are removed
+// and the unresolved source tags are replaced by their argument
+func stripDirectives(config map[interface{}]interface{}) map[interface{}]interface{} {
+	result := make(map[interface{}]interface{}, len(config))
+	for key, value := range config {
+		if dirValue, isDirective := value.(*directiveValue); isDirective {
+			if dirValue.directive.name == DeleteDirective {
+				continue
+			}
+			value = dirValue.value
+		}
+		result[key] = stripValue(value)
+	}
+	return result
+}
+
+func stripValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case *includeValue:
+		return typedValue.arg
+	case map[interface{}]interface{}:
+		return stripDirectives(typedValue)
+	case []interface{}:
+		result := make([]interface{}, 0, len(typedValue))
+		for _, item := range typedValue {
+			if dirValue, isDirective := item.(*directiveValue); isDirective {
+				if dirValue.directive.name == DeleteDirective {
+					continue
+				}
+				item = dirValue.value
+			}
+			result = append(result, stripValue(item))
+		}
+		return result
+	default:
+		return value
+	}
+}
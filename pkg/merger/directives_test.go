@@ -0,0 +1,138 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+const directivesBaseConfig = `
+db:
+  url: jdbc:db
+  user: admin
+log: info
+features: [f1, f2]
+servers:
+  - name: s1
+    port: 8080
+  - name: s2
+    port: 8081
+`
+
+func TestSmartConfigMerger_Merge_Directives(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		profile  string
+		expected map[interface{}]interface{}
+		deleted  []string
+	}{
+		{"delete", "", "db:\n  user: !delete\nlog: !delete", map[interface{}]interface{}{
+			"db":       map[interface{}]interface{}{"url": "jdbc:db"},
+			"features": []interface{}{"f1", "f2"},
+		}, []string{"log"}},
+		{"null without strict mode", "", "log: ~", map[interface{}]interface{}{
+			"db":  map[interface{}]interface{}{"url": "jdbc:db", "user": "admin"},
+			"log": "info",
+		}, nil},
+		{"null with strict mode", "merge:\n  strictNull: true", "log: ~\ndb: {user: ~}", map[interface{}]interface{}{
+			"db": map[interface{}]interface{}{"url": "jdbc:db"},
+		}, []string{"log"}},
+		{"append", "", "features: !append [f3]", map[interface{}]interface{}{
+			"features": []interface{}{"f1", "f2", "f3"},
+		}, nil},
+		{"prepend", "", "features: !prepend [f0]", map[interface{}]interface{}{
+			"features": []interface{}{"f0", "f1", "f2"},
+		}, nil},
+		{"replace", "", "features: [f3]", map[interface{}]interface{}{
+			"features": []interface{}{"f3"},
+		}, nil},
+		{"manifest rule", "merge:\n  lists:\n    features: append", "features: [f3]", map[interface{}]interface{}{
+			"features": []interface{}{"f1", "f2", "f3"},
+		}, nil},
+		{"tag over manifest rule", "merge:\n  lists:\n    features: append", "features: !prepend [f0]", map[interface{}]interface{}{
+			"features": []interface{}{"f0", "f1", "f2"},
+		}, nil},
+		{"merge by key", "", "servers: !merge:name\n  - name: s2\n    port: 9091\n  - !delete {name: s1}\n  - name: s3\n    port: 9092", map[interface{}]interface{}{
+			"servers": []interface{}{
+				map[interface{}]interface{}{"name": "s2", "port": 9091},
+				map[interface{}]interface{}{"name": "s3", "port": 9092},
+			},
+		}, nil},
+		{"nested manifest rule", "merge:\n  lists:\n    servers: merge:name", "servers:\n  - name: s1\n    host: h1", map[interface{}]interface{}{
+			"servers": []interface{}{
+				map[interface{}]interface{}{"name": "s1", "port": 8080, "host": "h1"},
+				map[interface{}]interface{}{"name": "s2", "port": 8081},
+			},
+		}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := memconfigrepo.NewMemConfigRepo()
+			assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte(directivesBaseConfig)))
+			assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte(test.profile)))
+			if test.manifest != "" {
+				assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte(test.manifest)))
+			}
+			assert.NoError(t, repo.Init())
+			config, err := SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{"dev"})
+			assert.NoError(t, err)
+			for key, value := range test.expected {
+				assert.Equal(t, value, config[key], key)
+			}
+			for _, deleted := range test.deleted {
+				assert.NotContains(t, config, deleted)
+			}
+		})
+	}
+}
+
+func TestSmartConfigMerger_Merge_Directives_Parents(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("base-service", "1.0.0", "config.yml", []byte(directivesBaseConfig)))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte("extends: base-service/1.0.0")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("features: !append [f3]\ndb: !delete")))
+	assert.NoError(t, repo.Init())
+	config, err := SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"f1", "f2", "f3"}, config["features"])
+	assert.NotContains(t, config, "db")
+}
+
+func TestMerge_InvalidDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		content  string
+		expected error
+	}{
+		{"unknown tag", "", "log: !unknown info", ErrInvalidSource},
+		{"scalar append", "", "log: !append info", ErrInvalidDirective},
+		{"map prepend", "", "db: !prepend {url: jdbc:db}", ErrInvalidDirective},
+		{"delete item of a replaced list", "", "features: [!delete f1]", ErrInvalidDirective},
+		{"invalid manifest rule", "merge:\n  lists:\n    features: merge", "log: info", ErrInvalidManifest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := memconfigrepo.NewMemConfigRepo()
+			assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte(test.content)))
+			if test.manifest != "" {
+				assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte(test.manifest)))
+			}
+			assert.NoError(t, repo.Init())
+			_, err := SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{})
+			assert.True(t, errors.Is(err, test.expected), err)
+		})
+	}
+}
+
+func TestParseSource_StripDirectives(t *testing.T) {
+	config, err := ParseSource("config.yml", []byte("log: !delete\nfeatures: !append [f1]\nservers: !merge:name [!delete {name: s1}, {name: s2}]"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"features": []interface{}{"f1"},
+		"servers":  []interface{}{map[interface{}]interface{}{"name": "s2"}},
+	}, config)
+}
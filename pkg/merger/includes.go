@@ -0,0 +1,170 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// source tags resolved by mergeFiles, the file paths are relative to the branch root of the merged file
+const (
+	// IncludeTag inlines another source file of the same branch (i.e. `db: !include common/db.yml`)
+	IncludeTag = "include"
+	// FileTag inlines the content of a raw file of the same branch as a string (i.e. `ca: !file certs/ca.pem`)
+	FileTag = "file"
+	// EnvTag reads a server environment variable, only the variables of the allow list can be read (see SetEnvAllowList)
+	EnvTag = "env"
+)
+
+// ErrInvalidInclude returned if a source tag cannot be resolved (missing file, invalid path or environment variable not allowed)
+var ErrInvalidInclude = fmt.Errorf("invalid include")
+
+// ErrIncludeCycle returned if a file includes itself (directly or through the included files)
+var ErrIncludeCycle = fmt.Errorf("include cycle")
+
+var envAllowList = struct {
+	sync.RWMutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// SetEnvAllowList set the environment variables that can be read by the `!env` tag (none by default)
+func SetEnvAllowList(names []string) {
+	envAllowList.Lock()
+	defer envAllowList.Unlock()
+	envAllowList.names = make(map[string]bool, len(names))
+	for _, name := range names {
+		envAllowList.names[name] = true
+	}
+}
+
+func isEnvAllowed(name string) bool {
+	envAllowList.RLock()
+	defer envAllowList.RUnlock()
+	return envAllowList.names[name]
+}
+
+func isIncludeTag(tag string) bool {
+	return tag == IncludeTag || tag == FileTag || tag == EnvTag
+}
+
+// includeValue a source value tagged by a source tag, resolved by an includeResolver
+type includeValue struct {
+	tag  string
+	arg  string
+	line int
+}
+
+// includeResolver resolves the source tags of the files read from the same branch
+type includeResolver struct {
+	// origin the branch description used by the logs (i.e. app1/1.0.0)
+	origin  string
+	getFile func(filePath string) (*configrepo.RepoFile, error)
+}
+
+func newAppIncludeResolver(repo configrepo.Repo, app *configrepo.ApplicationVersion) *includeResolver {
+	return &includeResolver{
+		origin:  fmt.Sprintf("%s/%s", app.AppName, app.AppVersion),
+		getFile: func(filePath string) (*configrepo.RepoFile, error) { return repo.GetFile(app, filePath) },
+	}
+}
+
+func newSharedIncludeResolver(repo configrepo.SharedRepo, app *configrepo.ApplicationVersion) *includeResolver {
+	return &includeResolver{
+		origin:  "shared",
+		getFile: func(filePath string) (*configrepo.RepoFile, error) { return repo.GetSharedFile(app, filePath) },
+	}
+}
+
+// resolve replaces the tagged values of a source file, chain contains the file path and the files including it
+func (r *includeResolver) resolve(config map[interface{}]interface{}, chain []string) error {
+	for key, value := range config {
+		resolved, err := r.resolveValue(value, chain)
+		if err != nil {
+			return err
+		}
+		config[key] = resolved
+	}
+	return nil
+}
+
+func (r *includeResolver) resolveValue(value interface{}, chain []string) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case *includeValue:
+		return r.resolveInclude(typedValue, chain)
+	case *directiveValue:
+		resolved, err := r.resolveValue(typedValue.value, chain)
+		if err != nil {
+			return nil, err
+		}
+		typedValue.value = resolved
+		return typedValue, nil
+	case map[interface{}]interface{}:
+		return typedValue, r.resolve(typedValue, chain)
+	case []interface{}:
+		for i, item := range typedValue {
+			resolved, err := r.resolveValue(item, chain)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[i] = resolved
+		}
+		return typedValue, nil
+	default:
+		return value, nil
+	}
+}
+
+func (r *includeResolver) resolveInclude(value *includeValue, chain []string) (interface{}, error) {
+	includer := chain[len(chain)-1]
+	if value.tag == EnvTag {
+		if !isEnvAllowed(value.arg) {
+			return nil, fmt.Errorf("%w:%s line %d the environment variable %s is not allowed", ErrInvalidInclude, includer, value.line, value.arg)
+		}
+		envValue, found := os.LookupEnv(value.arg)
+		if !found {
+			return nil, fmt.Errorf("%w:%s line %d the environment variable %s is not defined", ErrInvalidInclude, includer, value.line, value.arg)
+		}
+		logrus.Debugf("reading the environment variable %s in %s:%s", value.arg, r.origin, includer)
+		return envValue, nil
+	}
+	filePath, err := includePath(value.arg)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%s line %d %s", ErrInvalidInclude, includer, value.line, err)
+	}
+	file, err := r.getFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%s line %d %s", ErrInvalidInclude, includer, value.line, err)
+	}
+	if value.tag == FileTag {
+		logrus.Debugf("inlining the file %s (version:%s) in %s:%s", filePath, file.Version, r.origin, includer)
+		return string(file.Content), nil
+	}
+	for _, chainPath := range chain {
+		if chainPath == filePath {
+			return nil, fmt.Errorf("%w:%s", ErrIncludeCycle, strings.Join(append(chain, filePath), " -> "))
+		}
+	}
+	logrus.Debugf("including the file %s (version:%s) in %s:%s", filePath, file.Version, r.origin, strings.Join(chain, " -> "))
+	includedConfig, err := parseSource(filePath, file.Content)
+	if err != nil {
+		return nil, err
+	}
+	childChain := append(append(make([]string, 0, len(chain)+1), chain...), filePath)
+	return includedConfig, r.resolve(includedConfig, childChain)
+}
+
+// includePath returns the cleaned file path of a tag (relative to the branch root), the paths outside the branch are not allowed
+func includePath(tagPath string) (string, error) {
+	cleanPath := path.Clean(strings.TrimPrefix(strings.TrimSpace(tagPath), "/"))
+	if cleanPath == "." {
+		return "", fmt.Errorf("empty file path")
+	}
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", fmt.Errorf("the file path %s is outside the branch", tagPath)
+	}
+	return cleanPath, nil
+}
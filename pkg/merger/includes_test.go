@@ -0,0 +1,87 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"os"
+	"testing"
+)
+
+func TestSmartConfigMerger_Merge_Includes(t *testing.T) {
+	assert.NoError(t, os.Setenv("VECOSY_TEST_REGION", "eu-west"))
+	defer os.Unsetenv("VECOSY_TEST_REGION")
+	SetEnvAllowList([]string{"VECOSY_TEST_REGION"})
+	defer SetEnvAllowList(nil)
+
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte(`
+db: !include common/db.yml
+tls:
+  ca: !file certs/ca.pem
+region: !env VECOSY_TEST_REGION
+features: [f1]
+`)))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "common/db.yml", []byte("host: localhost\nuser: !include /common/user.json")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "common/user.json", []byte(`{"name": "admin"}`)))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "common/features.yml", []byte("features: !append [f2]")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "certs/ca.pem", []byte("-----BEGIN CERTIFICATE-----\n")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("extra: !include common/features.yml")))
+	assert.NoError(t, repo.Init())
+
+	config, err := SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"db": map[interface{}]interface{}{
+			"host": "localhost",
+			"user": map[interface{}]interface{}{"name": "admin"},
+		},
+		"tls":      map[interface{}]interface{}{"ca": "-----BEGIN CERTIFICATE-----\n"},
+		"region":   "eu-west",
+		"features": []interface{}{"f1"},
+		"extra":    map[interface{}]interface{}{"features": []interface{}{"f2"}},
+	}, config)
+}
+
+func TestSmartConfigMerger_Merge_Includes_Errors(t *testing.T) {
+	assert.NoError(t, os.Setenv("VECOSY_TEST_SECRET", "secret"))
+	defer os.Unsetenv("VECOSY_TEST_SECRET")
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected error
+		contains string
+	}{
+		{"missing file", map[string]string{"config.yml": "db: !include missing.yml"}, ErrInvalidInclude, "config.yml line 1"},
+		{"outside the branch", map[string]string{"config.yml": "ca: !file ../other/ca.pem"}, ErrInvalidInclude, "outside the branch"},
+		{"env not allowed", map[string]string{"config.yml": "secret: !env VECOSY_TEST_SECRET"}, ErrInvalidInclude, "VECOSY_TEST_SECRET is not allowed"},
+		{"not a scalar", map[string]string{"config.yml": "db: !include [a.yml]"}, ErrInvalidSource, "line 1: !include requires a scalar value"},
+		{"self include", map[string]string{"config.yml": "db: !include config.yml"}, ErrIncludeCycle, "config.yml -> config.yml"},
+		{"cycle", map[string]string{
+			"config.yml": "db: !include a.yml",
+			"a.yml":      "b: !include b.yml",
+			"b.yml":      "a: !include a.yml",
+		}, ErrIncludeCycle, "config.yml -> a.yml -> b.yml -> a.yml"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := memconfigrepo.NewMemConfigRepo()
+			for filePath, content := range test.files {
+				assert.NoError(t, repo.SetFile("app1", "1.0.0", filePath, []byte(content)))
+			}
+			assert.NoError(t, repo.Init())
+			_, err := SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{})
+			assert.True(t, errors.Is(err, test.expected), "unexpected error:%s", err)
+			assert.Contains(t, err.Error(), test.contains)
+		})
+	}
+}
+
+func TestParseSource_Includes(t *testing.T) {
+	// the source tags are not resolved out of mergeFiles
+	config, err := ParseSource("config.yml", []byte("db: !include common/db.yml\nregion: !env REGION"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"db": "common/db.yml", "region": "REGION"}, config)
+}
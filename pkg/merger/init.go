@@ -0,0 +1,95 @@
+package merger
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+)
+
+// mergeFiles merge the application layers (see Layers), the sources of every layer (see Manifest.LayoutOf) are merged in order
+// applying the merge rules of the layer manifest, the origins of the keys are recorded by the provenance (if not nil)
+func mergeFiles(repo configrepo.Repo, app *configrepo.ApplicationVersion, strategy string, defaultLayout *Layout, profiles []string, provenance Provenance) (map[interface{}]interface{}, error) {
+	layers, err := resolveLayers(repo, app)
+	if err != nil {
+		return nil, err
+	}
+	finalConfig := make(map[interface{}]interface{})
+	for i, layer := range layers {
+		// the shared files are underneath the lowest layer only
+		err := mergeLayerFiles(finalConfig, repo, layer, layer.manifest.LayoutOf(strategy, defaultLayout), profiles, i == 0, provenance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return finalConfig, nil
+}
+
+// mergeLayerFiles merge the layout sources (file paths without extension) of an application layer in order,
+// every variant (see Layout.Variants) of an application source is merged over the related shared variants (see configrepo.SharedRepo)
+func mergeLayerFiles(finalConfig map[interface{}]interface{}, repo configrepo.Repo, layer *layer, layout *Layout, profiles []string, withShared bool, provenance Provenance) error {
+	app := layer.app
+	rules := &layer.manifest.Merge
+	sharedRepo, isShared := repo.(configrepo.SharedRepo)
+	for _, source := range layout.Sources(app.AppName, profiles) {
+		if isShared && withShared {
+			for _, configFilePath := range layout.Variants(source) {
+				sharedFile, err := sharedRepo.GetSharedFile(app, configFilePath)
+				if err != nil {
+					logMissingFile("shared file", configFilePath, err)
+					continue
+				}
+				err = mergeFile(finalConfig, sharedFile, configFilePath, profiles, rules, newSharedIncludeResolver(sharedRepo, app), provenance)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		for _, configFilePath := range layout.Variants(source) {
+			profileFile, err := repo.GetFile(app, configFilePath)
+			if err != nil {
+				if isApplicationError(err) {
+					return err
+				}
+				logMissingFile("file", configFilePath, err)
+				continue
+			}
+			err = mergeFile(finalConfig, profileFile, configFilePath, profiles, rules, newAppIncludeResolver(repo, app), provenance)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func logMissingFile(kind, configFilePath string, err error) {
+	if errors.Is(err, configrepo.ErrFileNotFound) {
+		logrus.Debugf("%s not found:%s", kind, configFilePath)
+	} else {
+		logrus.Warnf("Error getting %s:%s, err:%s", kind, configFilePath, err)
+	}
+}
+
+// mergeFile merge the active documents of a source file (see activeDocuments) over the final configuration in order,
+// their source tags are resolved by the resolver of its branch
+func mergeFile(finalConfig map[interface{}]interface{}, file *configrepo.RepoFile, configFilePath string, profiles []string, rules *MergeRules, resolver *includeResolver, provenance Provenance) error {
+	logrus.Debugf("merging the file %s (version:%s) of %s", configFilePath, file.Version, resolver.origin)
+	documents, _, err := activeDocuments(configFilePath, file.Content, profiles)
+	if err != nil {
+		return err
+	}
+	for _, doc := range documents {
+		err = resolver.resolve(doc.Config, []string{configFilePath})
+		if err != nil {
+			return err
+		}
+		if provenance != nil {
+			provenance.record(doc.Config, "", Origin{App: resolver.origin, File: configFilePath, Version: file.Version}, rules)
+		}
+		err = mergeConfig(finalConfig, doc.Config, "", rules)
+		if err != nil {
+			return errors.Wrapf(err, "Error merging the file:%s, err:%s", configFilePath, err)
+		}
+	}
+	return nil
+}
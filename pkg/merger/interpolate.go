@@ -0,0 +1,211 @@
+package merger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPlaceholderCycle returned if a placeholder references itself (directly or through other placeholders)
+var ErrPlaceholderCycle = fmt.Errorf("placeholder cycle")
+
+// ErrInvalidPlaceholder returned if a placeholder is not closed or it doesn't contain a key
+var ErrInvalidPlaceholder = fmt.Errorf("invalid placeholder")
+
+const placeholderPrefix = "${"
+const placeholderSuffix = "}"
+
+// Interpolate returns a copy of the merged configuration with the placeholders resolved:
+// `${some.key}` is replaced by the value of the key and `${some.key:default}` falls back to the default if the key is missing.
+// Like spring the placeholders of the missing keys without a default are left untouched (i.e. `${HOME}`),
+// only the cycles (ErrPlaceholderCycle) and the malformed placeholders (ErrInvalidPlaceholder) are errors
+//
+// the keys are dotted paths of the configuration, the list items are referenced by index (i.e. `${servers[0].name}`),
+// a value made only of a placeholder keeps the type of the referenced value
+func Interpolate(config map[interface{}]interface{}) (map[interface{}]interface{}, error) {
+	in := &interpolator{config: config}
+	result, err := in.resolveValue(config, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[interface{}]interface{}), nil
+}
+
+// InterpolateText returns the text with the placeholders resolved by the configuration keys (see Interpolate),
+// like spring-cloud-config the malformed placeholders are left untouched as well (i.e. `${` in a shell script).
+// Only the keys referenced by the text are resolved, the configuration doesn't need to be interpolated first
+func InterpolateText(text string, config map[interface{}]interface{}) (string, error) {
+	in := &interpolator{config: config, lenient: true}
+	result, err := in.resolveString(text, "")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result), nil
+}
+
+type interpolator struct {
+	config map[interface{}]interface{}
+	// chain the keys that are being resolved by the placeholders
+	chain []string
+	// lenient leaves the not closed placeholders and the ones without key untouched
+	lenient bool
+}
+
+func (in *interpolator) resolveValue(value interface{}, path string) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(typedValue))
+		for key, child := range typedValue {
+			childPath := fmt.Sprint(key)
+			if path != "" {
+				childPath = path + "." + childPath
+			}
+			resolved, err := in.resolveValue(child, childPath)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			resolved, err := in.resolveValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	case string:
+		return in.resolveString(typedValue, path)
+	default:
+		return value, nil
+	}
+}
+
+// resolveString replace the placeholders of a string value at the path
+func (in *interpolator) resolveString(value, path string) (interface{}, error) {
+	start := strings.Index(value, placeholderPrefix)
+	if start < 0 {
+		return value, nil
+	}
+	// a value made only of a placeholder keeps the type of the referenced value
+	if start == 0 && placeholderEnd(value, start) == len(value)-1 {
+		return in.resolvePlaceholder(value[len(placeholderPrefix):len(value)-1], path)
+	}
+	var result strings.Builder
+	for start >= 0 {
+		end := placeholderEnd(value, start)
+		if end < 0 && in.lenient {
+			break
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("%w:%s not closed placeholder in %q", ErrInvalidPlaceholder, path, value)
+		}
+		resolved, err := in.resolvePlaceholder(value[start+len(placeholderPrefix):end], path)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(value[:start])
+		result.WriteString(fmt.Sprint(resolved))
+		value = value[end+1:]
+		start = strings.Index(value, placeholderPrefix)
+	}
+	result.WriteString(value)
+	return result.String(), nil
+}
+
+// placeholderEnd returns the index of the suffix closing the placeholder at the start index, -1 if it's not closed
+func placeholderEnd(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], placeholderPrefix):
+			depth++
+			i++
+		case strings.HasPrefix(value[i:], placeholderSuffix):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// resolvePlaceholder returns the value of a placeholder expression (key or key:default) referenced at the path
+func (in *interpolator) resolvePlaceholder(expression, path string) (interface{}, error) {
+	key, defaultValue, hasDefault := expression, "", false
+	if separator := defaultSeparator(expression); separator >= 0 {
+		key, defaultValue, hasDefault = expression[:separator], expression[separator+1:], true
+	}
+	if key == "" && in.lenient {
+		return placeholderPrefix + expression + placeholderSuffix, nil
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%w:%s ${%s}", ErrInvalidPlaceholder, path, expression)
+	}
+	value, found := lookup(in.config, key)
+	if !found {
+		if hasDefault {
+			return in.resolveString(defaultValue, path)
+		}
+		return placeholderPrefix + expression + placeholderSuffix, nil
+	}
+	for _, resolving := range in.chain {
+		if resolving == key {
+			return nil, fmt.Errorf("%w:%s -> %s", ErrPlaceholderCycle, strings.Join(in.chain, " -> "), key)
+		}
+	}
+	in.chain = append(in.chain, key)
+	defer func() { in.chain = in.chain[:len(in.chain)-1] }()
+	return in.resolveValue(value, key)
+}
+
+// defaultSeparator returns the index of the separator between the key and the default value, -1 if there is no default
+func defaultSeparator(expression string) int {
+	nested := strings.Index(expression, placeholderPrefix)
+	separator := strings.Index(expression, ":")
+	if nested >= 0 && nested < separator {
+		return -1
+	}
+	return separator
+}
+
+// lookup returns the value of a dotted key (i.e. `db.url` or `servers[0].name`)
+func lookup(config map[interface{}]interface{}, key string) (interface{}, bool) {
+	var current interface{} = config
+	for _, part := range strings.Split(key, ".") {
+		name, indexes := part, make([]int, 0)
+		for strings.HasSuffix(name, "]") {
+			open := strings.LastIndex(name, "[")
+			if open < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(name[open+1 : len(name)-1])
+			if err != nil {
+				return nil, false
+			}
+			indexes = append([]int{index}, indexes...)
+			name = name[:open]
+		}
+		currentMap, isMap := current.(map[interface{}]interface{})
+		if !isMap {
+			return nil, false
+		}
+		mapKey, found := findKey(currentMap, name)
+		if !found {
+			return nil, false
+		}
+		current = currentMap[mapKey]
+		for _, index := range indexes {
+			list, isList := current.([]interface{})
+			if !isList || index < 0 || index >= len(list) {
+				return nil, false
+			}
+			current = list[index]
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,104 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	config := make(map[interface{}]interface{})
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+server:
+  host: localhost
+  port: 8080
+  url: http://${server.host}:${server.port}/
+db:
+  port: ${server.port}
+  user: ${db.username:admin}
+  password: ${db.secret:${server.host}-pwd}
+  url: ${db.jdbc}
+  jdbc: jdbc:${db.user}@${servers[1].name}
+servers:
+  - name: s1
+  - name: ${server.host}
+literal: no placeholders
+adjacent:
+  both: ${server.host}${server.port}
+  prefixed: x${server.host}${server.port}
+  suffixed: ${server.host}${server.port}/x
+env:
+  home: ${HOME}
+  path: ${HOME}/bin:${db.user}
+`), config))
+	result, err := Interpolate(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/", result["server"].(map[interface{}]interface{})["url"])
+	db := result["db"].(map[interface{}]interface{})
+	assert.Equal(t, 8080, db["port"])
+	assert.Equal(t, "admin", db["user"])
+	assert.Equal(t, "localhost-pwd", db["password"])
+	assert.Equal(t, "jdbc:admin@localhost", db["url"])
+	assert.Equal(t, "localhost", result["servers"].([]interface{})[1].(map[interface{}]interface{})["name"])
+	assert.Equal(t, "no placeholders", result["literal"])
+	adjacent := result["adjacent"].(map[interface{}]interface{})
+	assert.Equal(t, "localhost8080", adjacent["both"])
+	assert.Equal(t, "xlocalhost8080", adjacent["prefixed"])
+	assert.Equal(t, "localhost8080/x", adjacent["suffixed"])
+	// the missing keys are left untouched
+	env := result["env"].(map[interface{}]interface{})
+	assert.Equal(t, "${HOME}", env["home"])
+	assert.Equal(t, "${HOME}/bin:admin", env["path"])
+	// the merged configuration is not changed
+	assert.Equal(t, "${server.port}", config["db"].(map[interface{}]interface{})["port"])
+}
+
+func TestInterpolate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected error
+		message  string
+	}{
+		{"self reference", "a: ${a}", ErrPlaceholderCycle, "a -> a"},
+		{"cycle", "a: ${b}\nb: x${c}\nc: ${a}", ErrPlaceholderCycle, " -> "},
+		{"not closed", "a: ${b", ErrInvalidPlaceholder, "a"},
+		{"empty key", "a: ${:default}", ErrInvalidPlaceholder, "a"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := make(map[interface{}]interface{})
+			assert.NoError(t, yaml.Unmarshal([]byte(test.config), config))
+			_, err := Interpolate(config)
+			assert.True(t, errors.Is(err, test.expected), err)
+			assert.Contains(t, err.Error(), test.message)
+		})
+	}
+}
+
+func TestInterpolateText(t *testing.T) {
+	config := map[interface{}]interface{}{
+		"db":   map[interface{}]interface{}{"host": "localhost", "port": 5432},
+		"loop": "${loop}",
+		"cmd":  "echo ${db.host",
+	}
+	// only the referenced keys are resolved, the cycles and the malformed placeholders of the other keys are ignored
+	text, err := InterpolateText("url=jdbc:postgresql://${db.host}:${db.port}/${db.name:app}\nexport PATH=${HOME}/bin ${:x} ${db.host", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "url=jdbc:postgresql://localhost:5432/app\nexport PATH=${HOME}/bin ${:x} ${db.host", text)
+
+	text, err = InterpolateText("host=${db.host}${db.port}", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "host=localhost5432", text)
+	text, err = InterpolateText("${db.host}${db.port}\n", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost5432\n", text)
+
+	text, err = InterpolateText("${db.port}", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "5432", text)
+
+	_, err = InterpolateText("${loop}", config)
+	assert.True(t, errors.Is(err, ErrPlaceholderCycle))
+}
@@ -0,0 +1,298 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// layout placeholders, replaced by the application name and by the profile
+const (
+	ApplicationPlaceholder = "{application}"
+	ProfilePlaceholder     = "{profile}"
+)
+
+// Patterns represent a list of source patterns declared as a single value or as a list
+type Patterns []string
+
+// UnmarshalYAML accept both a single pattern and a list of patterns
+func (p *Patterns) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	values, err := unmarshalStrings(unmarshal)
+	*p = values
+	return err
+}
+
+// Layout the file layout of a merge strategy, the sources are file paths without extension (see SourceVariants)
+// that can contain the ApplicationPlaceholder and the ProfilePlaceholder
+type Layout struct {
+	// Common the sources merged first (i.e. `config`)
+	Common Patterns `yaml:"common"`
+	// Profile the sources merged for every requested profile (i.e. `{profile}/config`)
+	Profile Patterns `yaml:"profile"`
+	// Extensions the source extensions, the variants of a source are merged in this order (see SourceExtensions)
+	Extensions []string `yaml:"extensions"`
+	// SearchPaths the folders searched after the branch root (i.e. `{application}`, `config/{profile}`), the last one has the highest precedence
+	// (see SetDefaultSearchPaths)
+	SearchPaths Patterns `yaml:"searchPaths"`
+}
+
+var defaultSearchPaths = struct {
+	sync.RWMutex
+	patterns Patterns
+}{}
+
+// SetDefaultSearchPaths set the search paths of the layouts that don't declare them (none by default, only the branch root is searched)
+func SetDefaultSearchPaths(patterns []string) error {
+	err := validateSearchPaths(patterns)
+	if err != nil {
+		return err
+	}
+	defaultSearchPaths.Lock()
+	defer defaultSearchPaths.Unlock()
+	defaultSearchPaths.patterns = patterns
+	return nil
+}
+
+// searchPaths returns the declared search paths or the default ones
+func (l *Layout) searchPaths() Patterns {
+	if len(l.SearchPaths) > 0 {
+		return l.SearchPaths
+	}
+	defaultSearchPaths.RLock()
+	defer defaultSearchPaths.RUnlock()
+	return defaultSearchPaths.patterns
+}
+
+// locations returns the branch root ("") followed by the search paths, the search paths containing the ProfilePlaceholder
+// are expanded for every profile (and skipped if no profile is requested)
+func (l *Layout) locations(appName string, profiles []string) []string {
+	locations := []string{""}
+	added := map[string]bool{"": true}
+	add := func(pattern string) {
+		location := cleanSearchPath(strings.ReplaceAll(pattern, ApplicationPlaceholder, appName))
+		if !added[location] {
+			added[location] = true
+			locations = append(locations, location)
+		}
+	}
+	for _, pattern := range l.searchPaths() {
+		if !strings.Contains(pattern, ProfilePlaceholder) {
+			add(pattern)
+			continue
+		}
+		for _, profile := range profiles {
+			if profile != "" {
+				add(strings.ReplaceAll(pattern, ProfilePlaceholder, profile))
+			}
+		}
+	}
+	return locations
+}
+
+func cleanSearchPath(searchPath string) string {
+	cleanPath := path.Clean(strings.Trim(strings.TrimSpace(searchPath), "/"))
+	if cleanPath == "." {
+		return ""
+	}
+	return cleanPath
+}
+
+func validateSearchPaths(patterns []string) error {
+	for _, pattern := range patterns {
+		cleanPath := cleanSearchPath(pattern)
+		if cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+			return fmt.Errorf("the search path %s is outside the branch", pattern)
+		}
+	}
+	return nil
+}
+
+// SmartConfigLayout the default layout of the smart config strategy
+var SmartConfigLayout = &Layout{
+	Common:     Patterns{"config"},
+	Profile:    Patterns{ProfilePlaceholder + "/config"},
+	Extensions: SourceExtensions,
+}
+
+// SpringLayout the default layout of the spring-cloud-config strategy
+var SpringLayout = &Layout{
+	Common:     Patterns{"application", ApplicationPlaceholder},
+	Profile:    Patterns{"application-" + ProfilePlaceholder, ApplicationPlaceholder + "-" + ProfilePlaceholder},
+	Extensions: SourceExtensions,
+}
+
+// Sources returns the sources of an application ordered by precedence (the last one has the highest precedence),
+// the empty profiles are ignored.
+//
+// like spring-boot the profile sources override the common ones of every location (see locations) and a location overrides the previous ones
+func (l *Layout) Sources(appName string, profiles []string) []string {
+	locations := l.locations(appName, profiles)
+	sources := make([]string, 0, (len(l.Common)+len(profiles)*len(l.Profile))*len(locations))
+	for _, pattern := range l.Common {
+		source := strings.ReplaceAll(pattern, ApplicationPlaceholder, appName)
+		for _, location := range locations {
+			sources = append(sources, path.Join(location, source))
+		}
+	}
+	for _, profile := range profiles {
+		if profile == "" {
+			continue
+		}
+		for _, pattern := range l.Profile {
+			source := strings.ReplaceAll(strings.ReplaceAll(pattern, ApplicationPlaceholder, appName), ProfilePlaceholder, profile)
+			for _, location := range locations {
+				sources = append(sources, path.Join(location, source))
+			}
+		}
+	}
+	return sources
+}
+
+// Variants returns the file paths of the variants of a source (see SourceVariants) with the layout extensions
+func (l *Layout) Variants(source string) []string {
+	result := make([]string, len(l.Extensions))
+	for i, ext := range l.Extensions {
+		result[i] = source + ext
+	}
+	return result
+}
+
+// Affects returns false only if the file is a source of other profiles (i.e. prod/config.yml for dev),
+// every other file can affect the environment (i.e. the common sources, the included files and the schema)
+func (l *Layout) Affects(appName, filePath, environment string) bool {
+	ext := path.Ext(filePath)
+	source := strings.TrimSuffix(filePath, ext)
+	for _, envSource := range l.Sources(appName, []string{environment}) {
+		for _, variant := range l.Variants(envSource) {
+			if variant == filePath {
+				return true
+			}
+		}
+	}
+	isLayoutExt := false
+	for _, layoutExt := range l.Extensions {
+		isLayoutExt = isLayoutExt || ext == layoutExt
+	}
+	return !isLayoutExt || !l.isProfileSource(appName, source)
+}
+
+// isProfileSource returns true if the source (file path without extension) is the source of a profile
+// in any location (see locations), the ProfilePlaceholder matches a single path segment
+func (l *Layout) isProfileSource(appName, source string) bool {
+	profileSegment := regexp.QuoteMeta(ProfilePlaceholder)
+	for _, location := range append(Patterns{""}, l.searchPaths()...) {
+		location = cleanSearchPath(strings.ReplaceAll(location, ApplicationPlaceholder, appName))
+		for _, pattern := range append(append(Patterns{}, l.Common...), l.Profile...) {
+			sourcePattern := path.Join(location, strings.ReplaceAll(pattern, ApplicationPlaceholder, appName))
+			if !strings.Contains(sourcePattern, ProfilePlaceholder) {
+				continue
+			}
+			sourceRe := "^" + strings.ReplaceAll(regexp.QuoteMeta(sourcePattern), profileSegment, "([^/]+)") + "$"
+			if matchesSameProfile(regexp.MustCompile(sourceRe).FindStringSubmatch(source)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesSameProfile returns true if every placeholder of a source pattern matched the same profile
+func matchesSameProfile(match []string) bool {
+	if match == nil {
+		return false
+	}
+	for _, profile := range match[2:] {
+		if profile != match[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// AffectsEnvironment returns true if the change can affect the configuration of an environment,
+// the changed paths are checked against the layout of the strategy declared by the application manifest (see Layout.Affects).
+//
+// every change affects an unknown environment, and so do the changes with unknown paths, the manifest changes
+// and the changes of the applications with an invalid manifest or with a strategy without layout
+func AffectsEnvironment(repo configrepo.Repo, change configrepo.Change, environment string) bool {
+	if environment == "" || change.ChangedPaths == nil {
+		return true
+	}
+	app := &change.ApplicationVersion
+	manifest, err := ReadManifest(repo, app)
+	if err != nil {
+		return true
+	}
+	defaults, found := layoutOf(manifest.strategy())
+	if !found {
+		return true
+	}
+	layout := manifest.LayoutOf(manifest.strategy(), defaults)
+	for _, changedPath := range change.ChangedPaths {
+		if changedPath == ManifestFile || layout.Affects(app.AppName, changedPath, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaults returns the layout with the missing fields taken from the defaults
+func (l *Layout) withDefaults(defaults *Layout) *Layout {
+	result := *l
+	if len(result.Common) == 0 {
+		result.Common = defaults.Common
+	}
+	if len(result.Profile) == 0 {
+		result.Profile = defaults.Profile
+	}
+	if len(result.Extensions) == 0 {
+		result.Extensions = defaults.Extensions
+	}
+	if len(result.SearchPaths) == 0 {
+		result.SearchPaths = defaults.SearchPaths
+	}
+	return &result
+}
+
+// validate returns an error if a profile pattern doesn't contain the profile placeholder, an extension is not supported
+// or a search path is outside the branch
+func (l *Layout) validate() error {
+	if err := validateSearchPaths(l.SearchPaths); err != nil {
+		return err
+	}
+	for _, pattern := range l.Profile {
+		if !strings.Contains(pattern, ProfilePlaceholder) {
+			return fmt.Errorf("the profile pattern %s doesn't contain %s", pattern, ProfilePlaceholder)
+		}
+	}
+	for _, ext := range l.Extensions {
+		if !isSourceExtension(ext) {
+			return fmt.Errorf("unsupported extension %s, supported:%v", ext, SourceExtensions)
+		}
+	}
+	return nil
+}
+
+func isSourceExtension(ext string) bool {
+	for _, sourceExt := range SourceExtensions {
+		if ext == sourceExt {
+			return true
+		}
+	}
+	return false
+}
+
+func unmarshalStrings(unmarshal func(interface{}) error) ([]string, error) {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		return []string{value}, nil
+	}
+	var values []string
+	if err := unmarshal(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,139 @@
+package merger
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestLayout_Sources(t *testing.T) {
+	assert.Equal(t, []string{"config", "prod/config", "canary/config"}, SmartConfigLayout.Sources("app1", []string{"prod", "", "canary"}))
+	assert.Equal(t, []string{"application", "app1", "application-dev", "app1-dev"}, SpringLayout.Sources("app1", []string{"dev"}))
+	assert.Equal(t, []string{"app1-dev", "application-dev", "app1", "application"}, GetSpringApplicationSources("app1", []string{"dev"}, false))
+
+	layout := (&Layout{Profile: Patterns{"envs/{profile}"}, Extensions: []string{".json"}}).withDefaults(SmartConfigLayout)
+	assert.Equal(t, []string{"config", "envs/dev"}, layout.Sources("app1", []string{"dev"}))
+	assert.Equal(t, []string{"envs/dev.json"}, layout.Variants("envs/dev"))
+}
+
+func TestLayout_Sources_SearchPaths(t *testing.T) {
+	layout := SpringLayout.withDefaults(SpringLayout)
+	layout.SearchPaths = Patterns{"/{application}/", "config/{profile}", "."}
+	assert.Equal(t, []string{
+		"application", "app1/application", "config/dev/application", "config/prod/application",
+		"app1", "app1/app1", "config/dev/app1", "config/prod/app1",
+		"application-dev", "app1/application-dev", "config/dev/application-dev", "config/prod/application-dev",
+		"app1-dev", "app1/app1-dev", "config/dev/app1-dev", "config/prod/app1-dev",
+		"application-prod", "app1/application-prod", "config/dev/application-prod", "config/prod/application-prod",
+		"app1-prod", "app1/app1-prod", "config/dev/app1-prod", "config/prod/app1-prod",
+	}, layout.Sources("app1", []string{"dev", "prod"}))
+	// the profile search paths are skipped without profiles
+	assert.Equal(t, []string{"application", "app1/application", "app1", "app1/app1"}, layout.Sources("app1", []string{""}))
+
+	assert.NoError(t, SetDefaultSearchPaths([]string{"{application}"}))
+	defer func() { assert.NoError(t, SetDefaultSearchPaths(nil)) }()
+	assert.Equal(t, []string{"config", "app1/config", "dev/config", "app1/dev/config"}, SmartConfigLayout.Sources("app1", []string{"dev"}))
+	assert.Error(t, SetDefaultSearchPaths([]string{"config/../.."}))
+}
+
+func TestLayout_Affects(t *testing.T) {
+	assert.True(t, SmartConfigLayout.Affects("app1", "config.yml", "dev"))
+	assert.True(t, SmartConfigLayout.Affects("app1", "dev/config.json", "dev"))
+	assert.False(t, SmartConfigLayout.Affects("app1", "prod/config.yml", "dev"))
+	assert.False(t, SmartConfigLayout.Affects("app1", "prod/config.yml", "pro"))
+	// not a source (i.e. an included file)
+	assert.True(t, SmartConfigLayout.Affects("app1", "prod/certs.yml", "dev"))
+	assert.True(t, SmartConfigLayout.Affects("app1", "prod/config.txt", "dev"))
+
+	assert.True(t, SpringLayout.Affects("app1", "application.yml", "dev"))
+	assert.True(t, SpringLayout.Affects("app1", "app1.properties", "dev"))
+	assert.True(t, SpringLayout.Affects("app1", "app1-dev.yml", "dev"))
+	assert.False(t, SpringLayout.Affects("app1", "app1-prod.yml", "dev"))
+	assert.False(t, SpringLayout.Affects("app1", "application-eu-west.yml", "dev"))
+	assert.True(t, SpringLayout.Affects("app1", "app2-prod.yml", "dev"))
+
+	layout := SpringLayout.withDefaults(SpringLayout)
+	layout.SearchPaths = Patterns{"{application}", "config/{profile}"}
+	assert.True(t, layout.Affects("app1", "app1/application.yml", "dev"))
+	assert.True(t, layout.Affects("app1", "config/dev/application.yml", "dev"))
+	assert.False(t, layout.Affects("app1", "config/prod/application.yml", "dev"))
+	assert.False(t, layout.Affects("app1", "config/prod/app1-prod.yml", "dev"))
+	// the search path and the source profiles don't match
+	assert.True(t, layout.Affects("app1", "config/prod/app1-eu.yml", "dev"))
+}
+
+func TestAffectsEnvironment(t *testing.T) {
+	RegisterStrategy("test-affects-static", staticMerger{})
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("prop1: common")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", ManifestFile, []byte("strategy: spring\nlayout:\n  profile: 'profiles/{profile}'")))
+	assert.NoError(t, repo.SetFile("app3", "1.0.0", ManifestFile, []byte("strategy: test-affects-static")))
+	assert.NoError(t, repo.SetFile("app4", "1.0.0", ManifestFile, []byte("strategy: unknown")))
+	assert.NoError(t, repo.Init())
+	change := func(appName string, paths ...string) configrepo.Change {
+		return configrepo.Change{ApplicationVersion: configrepo.ApplicationVersion{AppName: appName, AppVersion: "1.0.0"}, ChangedPaths: paths}
+	}
+
+	assert.False(t, AffectsEnvironment(repo, change("app1", "prod/config.yml"), "dev"))
+	assert.True(t, AffectsEnvironment(repo, change("app1", "prod/config.yml"), "prod"))
+	assert.True(t, AffectsEnvironment(repo, change("app1", "prod/config.yml"), ""))
+	assert.True(t, AffectsEnvironment(repo, change("app1", "prod/config.yml", "config.yml"), "dev"))
+	assert.True(t, AffectsEnvironment(repo, change("app1", ManifestFile), "dev"))
+	assert.True(t, AffectsEnvironment(repo, change("app1"), "dev"))
+	assert.False(t, AffectsEnvironment(repo, change("app1", []string{}...), "dev"))
+	// the layout of the manifest strategy
+	assert.False(t, AffectsEnvironment(repo, change("app2", "profiles/prod.yml"), "dev"))
+	assert.True(t, AffectsEnvironment(repo, change("app2", "prod/config.yml"), "dev"))
+	// a strategy without layout and an invalid manifest
+	assert.True(t, AffectsEnvironment(repo, change("app3", "prod/config.yml"), "dev"))
+	assert.True(t, AffectsEnvironment(repo, change("app4", "prod/config.yml"), "dev"))
+}
+
+func TestManifestMerger_Merge(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("smart-app", "1.0.0", "config.yml", []byte("name: smart")))
+	assert.NoError(t, repo.SetFile("smart-app", "1.0.0", "dev/config.yml", []byte("env: dev")))
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", ManifestFile, []byte("strategy: spring")))
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", "application.yml", []byte("name: spring")))
+	assert.NoError(t, repo.SetFile("spring-app", "1.0.0", "spring-app-dev.yml", []byte("env: dev")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", ManifestFile, []byte("layout:\n  common: settings\n  profile: ['envs/{profile}', 'envs/{profile}-{application}']\n  extensions: [.json, .yml]")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "settings.json", []byte(`{"name": "custom", "format": "json"}`)))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "settings.yml", []byte("format: yml")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "settings.toml", []byte(`format = "toml"`)))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "envs/dev.yml", []byte("env: dev")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "envs/dev-custom-app.yml", []byte("app: custom-app")))
+	assert.NoError(t, repo.SetFile("custom-app", "1.0.0", "config.yml", []byte("ignored: true")))
+	assert.NoError(t, repo.Init())
+
+	tests := []struct {
+		name     string
+		app      *configrepo.ApplicationVersion
+		strategy string
+		expected map[interface{}]interface{}
+	}{
+		{"default strategy", configrepo.NewApplicationVersion("smart-app", "1.0.0"), SmartConfigStrategy, map[interface{}]interface{}{"name": "smart", "env": "dev"}},
+		{"spring strategy", configrepo.NewApplicationVersion("spring-app", "1.0.0"), SpringStrategy, map[interface{}]interface{}{"name": "spring", "env": "dev"}},
+		{"custom layout", configrepo.NewApplicationVersion("custom-app", "1.0.0"), SmartConfigStrategy, map[interface{}]interface{}{"name": "custom", "format": "yml", "env": "dev", "app": "custom-app"}},
+		{"missing version", configrepo.NewApplicationVersion("smart-app", "0.1.0"), SmartConfigStrategy, map[interface{}]interface{}{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy, err := StrategyOf(repo, test.app)
+			assert.NoError(t, err)
+			assert.Equal(t, test.strategy, strategy)
+			config, err := ManifestMerger{}.Merge(repo, test.app, []string{"dev"})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+
+	// the layout of a manifest doesn't apply to the other strategies
+	config, err := SpringMerger{}.Merge(repo, configrepo.NewApplicationVersion("custom-app", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{}, config)
+
+	explanation, err := ManifestMerger{}.Explain(repo, configrepo.NewApplicationVersion("spring-app", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "spring-app-dev.yml", explanation["env"].Origins[0].File)
+}
@@ -0,0 +1,189 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+// ManifestFile the optional application manifest
+const ManifestFile = ".vecosy.yml"
+
+// ErrInvalidManifest returned if an application manifest cannot be parsed
+var ErrInvalidManifest = fmt.Errorf("invalid manifest")
+
+// ErrInheritanceCycle returned if an application extends itself (directly or through its parents)
+var ErrInheritanceCycle = fmt.Errorf("inheritance cycle")
+
+// Parents represent the parent applications (appName/appVersion) declared as a single value or as a list
+type Parents []string
+
+// UnmarshalYAML accept both a single parent and a list of parents
+func (p *Parents) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	parents, err := unmarshalStrings(unmarshal)
+	*p = parents
+	return err
+}
+
+// Manifest represent the application manifest (.vecosy.yml)
+type Manifest struct {
+	// Extends the parent applications merged underneath the application, the last one has the highest precedence
+	Extends Parents `yaml:"extends"`
+	// Merge the merge rules applied to the application files
+	Merge MergeRules `yaml:"merge"`
+	// Strategy the merge strategy used by the generic endpoints (see RegisterStrategy), SmartConfigStrategy if empty
+	Strategy string `yaml:"strategy"`
+	// Layout the file layout of the strategy, the missing fields are taken from the strategy defaults (see LayoutOf)
+	Layout Layout `yaml:"layout"`
+}
+
+// strategy returns the declared strategy or SmartConfigStrategy if empty
+func (m *Manifest) strategy() string {
+	if m.Strategy == "" {
+		return SmartConfigStrategy
+	}
+	return m.Strategy
+}
+
+// LayoutOf returns the file layout used by a strategy to merge the application files,
+// the manifest layout applies only to its own strategy (the other strategies use their defaults)
+func (m *Manifest) LayoutOf(strategy string, defaults *Layout) *Layout {
+	if m.strategy() != strategy {
+		return defaults
+	}
+	return m.Layout.withDefaults(defaults)
+}
+
+// MergeRules the merge directives declared by the manifest, a yaml tag on the same key takes precedence
+type MergeRules struct {
+	// StrictNull a null value (`~`) deletes the key instead of being ignored
+	StrictNull bool `yaml:"strictNull"`
+	// Lists the directive (append, prepend or merge:<key>) applied to the lists by their dotted path (i.e. `servers: merge:name`)
+	Lists map[string]string `yaml:"lists"`
+}
+
+// IsEmpty returns true if no merge rule is declared
+func (r *MergeRules) IsEmpty() bool {
+	return !r.StrictNull && len(r.Lists) == 0
+}
+
+// listDirective returns the directive declared for the list at the path, nil if none
+func (r *MergeRules) listDirective(path string) *directive {
+	rule, found := r.Lists[path]
+	if !found {
+		return nil
+	}
+	// the rules are validated by ReadManifest
+	listDirective, _ := parseDirective(rule)
+	return listDirective
+}
+
+// layer an application merged by mergeFiles with its manifest
+type layer struct {
+	app      *configrepo.ApplicationVersion
+	manifest *Manifest
+}
+
+// ReadManifest returns the application manifest, an empty one if the application doesn't contain it
+func ReadManifest(repo configrepo.Repo, app *configrepo.ApplicationVersion) (*Manifest, error) {
+	manifest := &Manifest{}
+	manifestFile, err := repo.GetFile(app, ManifestFile)
+	if err != nil {
+		if errors.Is(err, configrepo.ErrFileNotFound) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+	err = yaml.Unmarshal(manifestFile.Content, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w:%s %s %s", ErrInvalidManifest, app.AppName, app.AppVersion, err)
+	}
+	for path, rule := range manifest.Merge.Lists {
+		listDirective, err := parseDirective(rule)
+		if err != nil || listDirective.name == DeleteDirective {
+			return nil, fmt.Errorf("%w:%s %s invalid list directive %s:%s", ErrInvalidManifest, app.AppName, app.AppVersion, path, rule)
+		}
+	}
+	if _, err := GetStrategy(manifest.Strategy); err != nil {
+		return nil, fmt.Errorf("%w:%s %s %s", ErrInvalidManifest, app.AppName, app.AppVersion, err)
+	}
+	if err := manifest.Layout.validate(); err != nil {
+		return nil, fmt.Errorf("%w:%s %s invalid layout:%s", ErrInvalidManifest, app.AppName, app.AppVersion, err)
+	}
+	return manifest, nil
+}
+
+// Layers returns the application ancestors (declared by the manifests extends) followed by the application itself,
+// ordered by precedence (the first one has the lowest precedence)
+//
+// the parents are read at their head (the application label is not applied) and an ancestor shared by several parents is merged only once
+func Layers(repo configrepo.Repo, app *configrepo.ApplicationVersion) ([]*configrepo.ApplicationVersion, error) {
+	layers, err := resolveLayers(repo, app)
+	if err != nil {
+		return nil, err
+	}
+	apps := make([]*configrepo.ApplicationVersion, len(layers))
+	for i, layer := range layers {
+		apps[i] = layer.app
+	}
+	return apps, nil
+}
+
+// resolveLayers returns the application layers (see Layers) with their manifests
+func resolveLayers(repo configrepo.Repo, app *configrepo.ApplicationVersion) ([]*layer, error) {
+	return appendLayers(repo, app, make([]*layer, 0), make(map[string]bool), nil)
+}
+
+func appendLayers(repo configrepo.Repo, app *configrepo.ApplicationVersion, layers []*layer, merged map[string]bool, chain []string) ([]*layer, error) {
+	key := fmt.Sprintf("%s/%s", app.AppName, app.AppVersion)
+	for _, child := range chain {
+		if child == key {
+			return nil, fmt.Errorf("%w:%s", ErrInheritanceCycle, strings.Join(append(chain, key), " -> "))
+		}
+	}
+	if merged[key] {
+		return layers, nil
+	}
+	manifest, err := ReadManifest(repo, app)
+	if err != nil {
+		// a missing application version is not an error, the request falls back to the shared files (if any)
+		if len(chain) > 0 || !errors.Is(err, configrepo.ErrVersionNotFound) {
+			return nil, err
+		}
+		logrus.Warnf("Error reading the %s manifest:%s", key, err)
+		manifest = &Manifest{}
+	}
+	childChain := append(append(make([]string, 0, len(chain)+1), chain...), key)
+	for _, parent := range manifest.Extends {
+		parentApp, err := parseParent(parent)
+		if err != nil {
+			return nil, fmt.Errorf("%w:%s extends %s", ErrInvalidManifest, key, parent)
+		}
+		layers, err = appendLayers(repo, parentApp, layers, merged, childChain)
+		if err != nil {
+			if errors.Is(err, ErrInheritanceCycle) || errors.Is(err, ErrInvalidManifest) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w:%s extends %s", err, key, parent)
+		}
+	}
+	merged[key] = true
+	return append(layers, &layer{app: app, manifest: manifest}), nil
+}
+
+// parseParent parse the appName/appVersion parent declaration
+func parseParent(parent string) (*configrepo.ApplicationVersion, error) {
+	separator := strings.LastIndex(parent, "/")
+	if separator <= 0 || separator == len(parent)-1 {
+		return nil, ErrInvalidManifest
+	}
+	return configrepo.NewApplicationVersion(parent[:separator], parent[separator+1:]), nil
+}
+
+// isApplicationError returns true if the error is related to the application itself and not to a single file
+func isApplicationError(err error) bool {
+	return errors.Is(err, configrepo.ErrApplicationNotFound) || errors.Is(err, configrepo.ErrLabelNotFound) || errors.Is(err, configrepo.ErrLabelNotSupported)
+}
@@ -0,0 +1,80 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func initInheritanceRepo(t *testing.T) *memconfigrepo.MemConfigRepo {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("base-service", "2.0.0", "config.yml", []byte("db: base\nlog: info\nport: 8080")))
+	assert.NoError(t, repo.SetFile("base-service", "2.0.0", "dev/config.yml", []byte("log: debug")))
+	assert.NoError(t, repo.SetFile("logging", "1.0.0", ManifestFile, []byte("extends: base-service/2.0.0")))
+	assert.NoError(t, repo.SetFile("logging", "1.0.0", "config.yml", []byte("log: warn\nformat: json")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte("extends: base-service/2.0.0")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("db: app1")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", ManifestFile, []byte("extends:\n  - base-service/2.0.0\n  - logging/1.0.0")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", "config.yml", []byte("port: 9090")))
+	assert.NoError(t, repo.Init())
+	return repo
+}
+
+func TestSmartConfigMerger_Merge_Extends(t *testing.T) {
+	repo := initInheritanceRepo(t)
+	tests := []struct {
+		name     string
+		app      *configrepo.ApplicationVersion
+		profile  string
+		expected map[interface{}]interface{}
+	}{
+		{"single parent", configrepo.NewApplicationVersion("app1", "1.0.0"), "", map[interface{}]interface{}{"db": "app1", "log": "info", "port": 8080}},
+		{"parent profile", configrepo.NewApplicationVersion("app1", "1.0.0"), "dev", map[interface{}]interface{}{"db": "app1", "log": "debug", "port": 8080}},
+		{"parents list", configrepo.NewApplicationVersion("app2", "1.5.0"), "dev", map[interface{}]interface{}{"db": "base", "log": "warn", "port": 9090, "format": "json"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := SmartConfigMerger{}.Merge(repo, test.app, []string{test.profile})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestLayers(t *testing.T) {
+	repo := initInheritanceRepo(t)
+	layers, err := Layers(repo, configrepo.NewApplicationVersion("app2", "1.0.0"))
+	assert.NoError(t, err)
+	// the common ancestor is merged only once
+	assert.Equal(t, []*configrepo.ApplicationVersion{
+		configrepo.NewApplicationVersion("base-service", "2.0.0"),
+		configrepo.NewApplicationVersion("logging", "1.0.0"),
+		configrepo.NewApplicationVersion("app2", "1.0.0"),
+	}, layers)
+}
+
+func TestLayers_Errors(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte("extends: app2/1.0.0")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", ManifestFile, []byte("extends: [app3/1.0.0, app1/1.0.0]")))
+	assert.NoError(t, repo.SetFile("app3", "1.0.0", "config.yml", []byte("prop: app3")))
+	assert.NoError(t, repo.SetFile("app4", "1.0.0", ManifestFile, []byte("extends: not-existing/1.0.0")))
+	assert.NoError(t, repo.SetFile("app5", "1.0.0", ManifestFile, []byte("extends: app3")))
+	assert.NoError(t, repo.SetFile("app6", "1.0.0", ManifestFile, []byte("extends: {app: app3}")))
+	assert.NoError(t, repo.Init())
+
+	_, err := Layers(repo, configrepo.NewApplicationVersion("app1", "1.0.0"))
+	assert.True(t, errors.Is(err, ErrInheritanceCycle))
+	assert.Contains(t, err.Error(), "app1/1.0.0 -> app2/1.0.0 -> app1/1.0.0")
+	_, err = SmartConfigMerger{}.Merge(repo, configrepo.NewApplicationVersion("app2", "1.0.0"), []string{"dev"})
+	assert.True(t, errors.Is(err, ErrInheritanceCycle))
+
+	_, err = Layers(repo, configrepo.NewApplicationVersion("app4", "1.0.0"))
+	assert.True(t, errors.Is(err, configrepo.ErrApplicationNotFound))
+	_, err = Layers(repo, configrepo.NewApplicationVersion("app5", "1.0.0"))
+	assert.True(t, errors.Is(err, ErrInvalidManifest))
+	_, err = Layers(repo, configrepo.NewApplicationVersion("app6", "1.0.0"))
+	assert.True(t, errors.Is(err, ErrInvalidManifest))
+}
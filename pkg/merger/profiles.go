@@ -0,0 +1,295 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"strings"
+	"unicode"
+)
+
+// spring profile conditions of a source document, the document is merged only if the requested profiles match them
+var profileConditionKeys = []string{"spring.config.activate.on-profile", "spring.profiles"}
+
+// DefaultProfile the profile active when no profile is requested (like spring-boot)
+const DefaultProfile = "default"
+
+// SourceDocument a document of a source file (only the yaml sources can have several documents separated by `---`)
+type SourceDocument struct {
+	// Index the position of the document in the source file
+	Index  int
+	Config map[interface{}]interface{}
+	// Directives the document declares merge directives (yaml tags), they are not applied to Config
+	Directives bool
+}
+
+// ReadActiveDocuments parse the documents of an application source file that are active for the profiles (see activeDocuments),
+// it returns the active documents with their source tags resolved by the application branch and the number of documents of the file,
+// the merge directives are not applied
+func ReadActiveDocuments(repo configrepo.Repo, app *configrepo.ApplicationVersion, filePath string, content []byte, profiles []string) ([]*SourceDocument, int, error) {
+	return readActiveDocuments(newAppIncludeResolver(repo, app), filePath, content, profiles)
+}
+
+// ReadSharedActiveDocuments parse the active documents of a shared source file (see ReadActiveDocuments),
+// their source tags are resolved by the shared branch
+func ReadSharedActiveDocuments(repo configrepo.SharedRepo, app *configrepo.ApplicationVersion, filePath string, content []byte, profiles []string) ([]*SourceDocument, int, error) {
+	return readActiveDocuments(newSharedIncludeResolver(repo, app), filePath, content, profiles)
+}
+
+// readActiveDocuments resolves the source tags of the active documents like mergeFile
+func readActiveDocuments(resolver *includeResolver, filePath string, content []byte, profiles []string) ([]*SourceDocument, int, error) {
+	documents, count, err := activeDocuments(filePath, content, profiles)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, doc := range documents {
+		err = resolver.resolve(doc.Config, []string{filePath})
+		if err != nil {
+			return nil, 0, err
+		}
+		doc.Directives = hasDirectives(doc.Config)
+		doc.Config = stripDirectives(doc.Config)
+	}
+	return documents, count, nil
+}
+
+// parseDocuments parse the documents of a source file (only the yaml sources can have several documents)
+func parseDocuments(filePath string, content []byte) ([]*SourceDocument, error) {
+	var configs []map[interface{}]interface{}
+	if isYAMLSource(filePath) {
+		var err error
+		configs, err = parseYAMLDocuments(content)
+		if err != nil {
+			return nil, fmt.Errorf("%w:%s %s", ErrInvalidSource, filePath, err)
+		}
+	} else {
+		config, err := parseSource(filePath, content)
+		if err != nil {
+			return nil, err
+		}
+		configs = []map[interface{}]interface{}{config}
+	}
+	documents := make([]*SourceDocument, len(configs))
+	for i, config := range configs {
+		documents[i] = &SourceDocument{Index: i, Config: config}
+	}
+	return documents, nil
+}
+
+// activeDocuments returns the documents of a source file whose profile conditions (spring.config.activate.on-profile or spring.profiles)
+// match the profiles, the conditions are removed from the returned documents
+func activeDocuments(filePath string, content []byte, profiles []string) ([]*SourceDocument, int, error) {
+	documents, err := parseDocuments(filePath, content)
+	if err != nil {
+		return nil, 0, err
+	}
+	activeProfiles := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		if profile != "" {
+			activeProfiles[profile] = true
+		}
+	}
+	if len(activeProfiles) == 0 {
+		activeProfiles[DefaultProfile] = true
+	}
+	active := make([]*SourceDocument, 0, len(documents))
+	for _, doc := range documents {
+		matches, err := matchProfileConditions(doc.Config, activeProfiles)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w:%s document #%d %s", ErrInvalidSource, filePath, doc.Index, err)
+		}
+		if matches {
+			active = append(active, doc)
+		}
+	}
+	return active, len(documents), nil
+}
+
+// matchProfileConditions returns true if every profile condition of the config matches the active profiles,
+// a condition is a list (or a comma separated string) of profile expressions, at least one of them has to match
+func matchProfileConditions(config map[interface{}]interface{}, activeProfiles map[string]bool) (bool, error) {
+	result := true
+	for _, conditionKey := range profileConditionKeys {
+		value, found := removeDottedKey(config, strings.Split(conditionKey, "."))
+		if !found {
+			continue
+		}
+		expressions, err := profileExpressions(value)
+		if err != nil {
+			return false, fmt.Errorf("%s %s", conditionKey, err)
+		}
+		matches := false
+		for _, expression := range expressions {
+			expressionMatches, err := matchProfileExpression(expression, activeProfiles)
+			if err != nil {
+				return false, fmt.Errorf("%s %s", conditionKey, err)
+			}
+			matches = matches || expressionMatches
+		}
+		result = result && matches
+	}
+	return result, nil
+}
+
+func profileExpressions(value interface{}) ([]string, error) {
+	var items []string
+	switch typedValue := unwrap(value).(type) {
+	case string:
+		items = []string{typedValue}
+	case []interface{}:
+		for _, item := range typedValue {
+			itemValue, isString := unwrap(item).(string)
+			if !isString {
+				return nil, fmt.Errorf("invalid profile:%v", item)
+			}
+			items = append(items, itemValue)
+		}
+	default:
+		return nil, fmt.Errorf("invalid profiles:%v", value)
+	}
+	expressions := make([]string, 0, len(items))
+	for _, item := range items {
+		for _, expression := range strings.Split(item, ",") {
+			if strings.TrimSpace(expression) != "" {
+				expressions = append(expressions, expression)
+			}
+		}
+	}
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("no profile")
+	}
+	return expressions, nil
+}
+
+// removeDottedKey removes the value of a dotted key both nested (spring: {profiles: dev}) and flat (spring.profiles: dev),
+// the maps (i.e. spring.profiles.active) are not profile conditions and they are left untouched
+func removeDottedKey(config map[interface{}]interface{}, keyParts []string) (interface{}, bool) {
+	for i := len(keyParts); i > 0; i-- {
+		key, found := findKey(config, strings.Join(keyParts[:i], "."))
+		if !found {
+			continue
+		}
+		if i == len(keyParts) {
+			value := config[key]
+			if _, isMap := unwrap(value).(map[interface{}]interface{}); isMap {
+				return nil, false
+			}
+			delete(config, key)
+			return value, true
+		}
+		child, isMap := unwrap(config[key]).(map[interface{}]interface{})
+		if !isMap {
+			continue
+		}
+		value, found := removeDottedKey(child, keyParts[i:])
+		if found {
+			if len(child) == 0 {
+				delete(config, key)
+			}
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// matchProfileExpression evaluate a spring profile expression (i.e. `prod & (eu | us)`, `!dev`),
+// like spring the `&` and `|` operators cannot be mixed without parentheses
+func matchProfileExpression(expression string, activeProfiles map[string]bool) (bool, error) {
+	parser := &profileParser{tokens: tokenizeProfileExpression(expression), activeProfiles: activeProfiles}
+	result, err := parser.parseExpression()
+	if err != nil {
+		return false, fmt.Errorf("malformed profile expression %q:%s", expression, err)
+	}
+	if parser.pos < len(parser.tokens) {
+		return false, fmt.Errorf("malformed profile expression %q:unexpected %s", expression, parser.tokens[parser.pos])
+	}
+	return result, nil
+}
+
+func tokenizeProfileExpression(expression string) []string {
+	tokens := make([]string, 0)
+	profile := strings.Builder{}
+	flushProfile := func() {
+		if profile.Len() > 0 {
+			tokens = append(tokens, profile.String())
+			profile.Reset()
+		}
+	}
+	for _, char := range expression {
+		switch {
+		case strings.ContainsRune("()&|!", char):
+			flushProfile()
+			tokens = append(tokens, string(char))
+		case unicode.IsSpace(char):
+			flushProfile()
+		default:
+			profile.WriteRune(char)
+		}
+	}
+	flushProfile()
+	return tokens
+}
+
+type profileParser struct {
+	tokens         []string
+	pos            int
+	activeProfiles map[string]bool
+}
+
+func (p *profileParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parseExpression parse a sequence of operands joined by the same operator
+func (p *profileParser) parseExpression() (bool, error) {
+	result, err := p.parseOperand()
+	if err != nil {
+		return false, err
+	}
+	operator := ""
+	for p.next() == "&" || p.next() == "|" {
+		if operator != "" && operator != p.next() {
+			return false, fmt.Errorf("mixed & and | without parentheses")
+		}
+		operator = p.next()
+		p.pos++
+		operand, err := p.parseOperand()
+		if err != nil {
+			return false, err
+		}
+		if operator == "&" {
+			result = result && operand
+		} else {
+			result = result || operand
+		}
+	}
+	return result, nil
+}
+
+func (p *profileParser) parseOperand() (bool, error) {
+	token := p.next()
+	p.pos++
+	switch token {
+	case "":
+		return false, fmt.Errorf("missing profile")
+	case "!":
+		operand, err := p.parseOperand()
+		return !operand, err
+	case "(":
+		result, err := p.parseExpression()
+		if err != nil {
+			return false, err
+		}
+		if p.next() != ")" {
+			return false, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return result, nil
+	case ")", "&", "|":
+		return false, fmt.Errorf("unexpected %s", token)
+	default:
+		return p.activeProfiles[token], nil
+	}
+}
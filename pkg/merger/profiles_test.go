@@ -0,0 +1,128 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestMatchProfileExpression(t *testing.T) {
+	activeProfiles := map[string]bool{"prod": true, "eu": true}
+	tests := map[string]bool{
+		"prod":                true,
+		"dev":                 false,
+		"!dev":                true,
+		"prod & eu":           true,
+		"prod & us":           false,
+		"dev | eu":            true,
+		"prod & (us | eu)":    true,
+		"!(prod & eu)":        false,
+		"prod&!us":            true,
+		"(dev | us) & !local": false,
+	}
+	for expression, expected := range tests {
+		t.Run(expression, func(t *testing.T) {
+			matches, err := matchProfileExpression(expression, activeProfiles)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, matches)
+		})
+	}
+	for _, expression := range []string{"prod & eu | us", "prod &", "(prod", "prod)", "!", "& prod"} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := matchProfileExpression(expression, activeProfiles)
+			assert.Error(t, err)
+		})
+	}
+}
+
+const multiDocumentYml = `server:
+  port: 8080
+name: common
+---
+spring:
+  config:
+    activate:
+      on-profile: prod & eu
+server:
+  port: 9090
+---
+spring.profiles: dev,test
+name: dev
+---
+spring:
+  profiles:
+    active: ignored
+  config.activate.on-profile: "!prod"
+local: true
+`
+
+func TestSpringMerger_Merge_MultipleDocuments(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte(multiDocumentYml)))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	tests := []struct {
+		name     string
+		profiles []string
+		expected map[interface{}]interface{}
+	}{
+		{"expression", []string{"prod", "eu"}, map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 9090}, "name": "common"}},
+		{"partial expression", []string{"prod"}, map[interface{}]interface{}{"server": map[interface{}]interface{}{"port": 8080}, "name": "common"}},
+		{"profiles list", []string{"test"}, map[interface{}]interface{}{
+			"server": map[interface{}]interface{}{"port": 8080},
+			"name":   "dev",
+			"local":  true,
+			"spring": map[interface{}]interface{}{"profiles": map[interface{}]interface{}{"active": "ignored"}},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := SpringMerger{}.Merge(repo, app, test.profiles)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestReadActiveDocuments(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "db.yml", []byte("host: localhost")))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+
+	documents, count, err := ReadActiveDocuments(repo, app, "application.yml", []byte(multiDocumentYml), []string{""})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Len(t, documents, 2)
+	assert.Equal(t, 0, documents[0].Index)
+	assert.Equal(t, 3, documents[1].Index)
+
+	documents, count, err = ReadActiveDocuments(repo, app, "application.properties", []byte("spring.profiles=dev\nname=dev"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, map[interface{}]interface{}{"name": "dev"}, documents[0].Config)
+
+	documents, _, err = ReadActiveDocuments(repo, app, "application.yml", []byte(""), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*SourceDocument{{Index: 0, Config: map[interface{}]interface{}{}}}, documents)
+
+	// the source tags are resolved by the application branch
+	documents, _, err = ReadActiveDocuments(repo, app, "application.yml", []byte("db: !include db.yml\n---\nspring.profiles: dev\nca: !file db.yml"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"db": map[interface{}]interface{}{"host": "localhost"}}, documents[0].Config)
+	assert.Equal(t, map[interface{}]interface{}{"ca": "host: localhost"}, documents[1].Config)
+	assert.False(t, documents[0].Directives)
+	// the merge directives are flagged and stripped
+	documents, _, err = ReadActiveDocuments(repo, app, "application.yml", []byte("db:\n  user: !delete\nservers: [!delete {name: s1}]"), nil)
+	assert.NoError(t, err)
+	assert.True(t, documents[0].Directives)
+	assert.Equal(t, map[interface{}]interface{}{"db": map[interface{}]interface{}{}, "servers": []interface{}{}}, documents[0].Config)
+	_, _, err = ReadActiveDocuments(repo, app, "application.yml", []byte("db: !include missing.yml"), nil)
+	assert.True(t, errors.Is(err, ErrInvalidInclude))
+
+	_, _, err = ReadActiveDocuments(repo, app, "application.yml", []byte("name: a\n---\nspring.profiles: prod & eu | us"), nil)
+	assert.True(t, errors.Is(err, ErrInvalidSource))
+	assert.Contains(t, err.Error(), "document #1")
+}
@@ -0,0 +1,127 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/vecosy/vecosy/v2/internal/encryption"
+	"github.com/vecosy/vecosy/v2/internal/utils"
+)
+
+// MaskedValue the explained value of the `{cipher}` values, the placeholders that reference them are resolved with it as well
+const MaskedValue = encryption.CipherPrefix + "******"
+
+// Origin a source file that set a configuration key
+type Origin struct {
+	// App the application of the file (appName/appVersion), `shared` for the shared files
+	App  string `json:"app"`
+	File string `json:"file"`
+	// Version the file version (i.e. the commit hash)
+	Version string `json:"version"`
+	// Directive the merge directive applied to the value (i.e. append), empty if the value overrides the previous one
+	Directive string `json:"directive,omitempty"`
+	// Deleted the file deleted the key
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// Provenance the origins of the flattened configuration keys (i.e. db.user), ordered by precedence (the last one has the highest precedence)
+//
+// the maps are flattened, the lists and the scalars are the values of the keys
+type Provenance map[string][]*Origin
+
+// ExplainedValue the final value of a flattened key and the files that set it
+//
+// the value is the served one: the placeholders are resolved (see Interpolate) and the `{cipher}` values are masked (see MaskedValue)
+type ExplainedValue struct {
+	Value   interface{} `json:"value"`
+	Origins []*Origin   `json:"origins"`
+}
+
+// Explanation the explained values of a merged configuration by flattened key
+type Explanation map[string]*ExplainedValue
+
+// record add the origin to the keys set by a source file (see mergeConfig)
+func (p Provenance) record(src map[interface{}]interface{}, path string, origin Origin, rules *MergeRules) {
+	for key, srcValue := range src {
+		keyPath := fmt.Sprint(key)
+		if path != "" {
+			keyPath = path + "." + keyPath
+		}
+		keyOrigin := origin
+		if listDirective := rules.listDirective(keyPath); listDirective != nil {
+			keyOrigin.Directive = listDirective.String()
+		}
+		if dirValue, isDirective := srcValue.(*directiveValue); isDirective {
+			keyOrigin.Directive = dirValue.directive.String()
+			srcValue = dirValue.value
+		}
+		switch {
+		case keyOrigin.Directive == DeleteDirective || (srcValue == nil && rules.StrictNull):
+			keyOrigin.Directive = ""
+			keyOrigin.Deleted = true
+		case srcValue == nil:
+			continue
+		}
+		if srcMap, isMap := srcValue.(map[interface{}]interface{}); isMap && !keyOrigin.Deleted {
+			p.record(srcMap, keyPath, origin, rules)
+			continue
+		}
+		p[keyPath] = append(p[keyPath], &keyOrigin)
+	}
+}
+
+// explain returns the flattened keys of the merged configuration with their origins and their resolved values
+func (p Provenance) explain(config map[interface{}]interface{}) (Explanation, error) {
+	resolved, err := Interpolate(maskEncrypted(config).(map[interface{}]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	explanation := make(Explanation)
+	return explanation, p.explainMap(explanation, resolved, "")
+}
+
+// maskEncrypted returns a copy of the value with the `{cipher}` values replaced by MaskedValue
+func maskEncrypted(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(typedValue))
+		for key, child := range typedValue {
+			result[key] = maskEncrypted(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			result[i] = maskEncrypted(item)
+		}
+		return result
+	default:
+		if encryption.IsEncrypted(value) {
+			return MaskedValue
+		}
+		return value
+	}
+}
+
+func (p Provenance) explainMap(explanation Explanation, config map[interface{}]interface{}, path string) error {
+	for key, value := range config {
+		keyPath := fmt.Sprint(key)
+		if path != "" {
+			keyPath = path + "." + keyPath
+		}
+		if valueMap, isMap := value.(map[interface{}]interface{}); isMap {
+			if err := p.explainMap(explanation, valueMap, keyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		normValue, err := utils.NormalizeValue(value)
+		if err != nil {
+			return err
+		}
+		origins := p[keyPath]
+		if origins == nil {
+			origins = make([]*Origin, 0)
+		}
+		explanation[keyPath] = &ExplainedValue{Value: normValue, Origins: origins}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestSmartConfigMerger_Explain(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("base", "1.0.0", "config.yml", []byte("db:\n  port: 5432\n  pool: 10")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte("extends: base/1.0.0\nmerge:\n  strictNull: true")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("db:\n  user: admin\n  port: 5433\nfeatures: [f1]")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.yml", []byte("db:\n  user: dev\n  pool: ~\nfeatures: !append [f2]")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "dev/config.json", []byte(`{"db": {"user": "json"}}`)))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+	appFile := func(filePath string) *Origin {
+		file, err := repo.GetFile(app, filePath)
+		assert.NoError(t, err)
+		return &Origin{App: "app1/1.0.0", File: filePath, Version: file.Version}
+	}
+	baseFile, err := repo.GetFile(configrepo.NewApplicationVersion("base", "1.0.0"), "config.yml")
+	assert.NoError(t, err)
+	baseOrigin := &Origin{App: "base/1.0.0", File: "config.yml", Version: baseFile.Version}
+	appendOrigin := appFile("dev/config.yml")
+	appendOrigin.Directive = AppendDirective
+	deleteOrigin := appFile("dev/config.yml")
+	deleteOrigin.Deleted = true
+
+	explanation, err := SmartConfigMerger{}.Explain(repo, app, []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, Explanation{
+		"db.port":  {Value: 5433, Origins: []*Origin{baseOrigin, appFile("config.yml")}},
+		"db.user":  {Value: "json", Origins: []*Origin{appFile("config.yml"), appFile("dev/config.yml"), appFile("dev/config.json")}},
+		"features": {Value: []interface{}{"f1", "f2"}, Origins: []*Origin{appFile("config.yml"), appendOrigin}},
+	}, explanation)
+
+	// the deleted keys are not explained, their deletion is reported if they are set again
+	provenance := make(Provenance)
+	_, err = mergeFiles(repo, app, SmartConfigStrategy, SmartConfigLayout, []string{"dev"}, provenance)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Origin{baseOrigin, deleteOrigin}, provenance["db.pool"])
+}
+
+func TestSpringMerger_Explain(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("server:\n  port: 8080\nname: common")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.yml", []byte("server:\n  port: 9090")))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+
+	explanation, err := SpringMerger{}.Explain(repo, app, []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, explanation["server.port"].Value)
+	assert.Len(t, explanation["server.port"].Origins, 2)
+	assert.Equal(t, "application.yml", explanation["server.port"].Origins[0].File)
+	assert.Equal(t, "app1-dev.yml", explanation["server.port"].Origins[1].File)
+	assert.Equal(t, "common", explanation["name"].Value)
+	assert.Len(t, explanation["name"].Origins, 1)
+}
+
+func TestSmartConfigMerger_Explain_Resolved(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("db:\n  host: db.local\n  url: 'jdbc://${db.host}/app'\n  password: '{cipher}a3f5'\n  dsn: 'admin:${db.password}@${db.host}'\n  secrets: ['{cipher}b4e6', plain]")))
+	assert.NoError(t, repo.Init())
+	app := configrepo.NewApplicationVersion("app1", "1.0.0")
+
+	// the values are the served ones: the placeholders are resolved and the `{cipher}` values are masked
+	explanation, err := SmartConfigMerger{}.Explain(repo, app, []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "jdbc://db.local/app", explanation["db.url"].Value)
+	assert.Equal(t, MaskedValue, explanation["db.password"].Value)
+	assert.Equal(t, "admin:"+MaskedValue+"@db.local", explanation["db.dsn"].Value)
+	assert.Equal(t, []interface{}{MaskedValue, "plain"}, explanation["db.secrets"].Value)
+	assert.Len(t, explanation["db.url"].Origins, 1)
+
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "config.yml", []byte("a: ${b}\nb: ${a}")))
+	repo.Publish()
+	_, err = SmartConfigMerger{}.Explain(repo, app, []string{"dev"})
+	assert.True(t, errors.Is(err, ErrPlaceholderCycle))
+}
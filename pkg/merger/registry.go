@@ -0,0 +1,186 @@
+package merger
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"sort"
+	"sync"
+)
+
+// ConfigMerger represent a merge configuration strategy, the strategies are registered by name (see RegisterStrategy)
+type ConfigMerger interface {
+	Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error)
+}
+
+// ConfigExplainer represent a merge configuration strategy that explains the origins of the merged keys
+type ConfigExplainer interface {
+	Explain(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (Explanation, error)
+}
+
+// merge strategies names
+const (
+	// SmartConfigStrategy see SmartConfigMerger
+	SmartConfigStrategy = "smart"
+	// SpringStrategy see SpringMerger
+	SpringStrategy = "spring"
+)
+
+// ErrUnknownStrategy returned if a merge strategy name is not registered
+var ErrUnknownStrategy = fmt.Errorf("unknown merge strategy")
+
+// ErrExplainNotSupported returned if a merge strategy doesn't implement ConfigExplainer
+var ErrExplainNotSupported = fmt.Errorf("explain not supported")
+
+var strategies = struct {
+	sync.RWMutex
+	mergers map[string]ConfigMerger
+}{mergers: make(map[string]ConfigMerger)}
+
+func init() {
+	RegisterStrategy(SmartConfigStrategy, SmartConfigMerger{})
+	RegisterStrategy(SpringStrategy, SpringMerger{})
+}
+
+// RegisterStrategy make a merge strategy available by name to the manifests (see Manifest.Strategy) and to the endpoints,
+// it panics if the name is empty, the merger is nil or the name is already registered
+func RegisterStrategy(name string, merger ConfigMerger) {
+	strategies.Lock()
+	defer strategies.Unlock()
+	if name == "" {
+		panic("merger: RegisterStrategy with an empty name")
+	}
+	if merger == nil {
+		panic("merger: RegisterStrategy merger is nil for " + name)
+	}
+	if _, duplicated := strategies.mergers[name]; duplicated {
+		panic("merger: RegisterStrategy called twice for " + name)
+	}
+	strategies.mergers[name] = merger
+}
+
+// GetStrategy returns the merger of a registered strategy (SmartConfigStrategy if empty)
+func GetStrategy(name string) (ConfigMerger, error) {
+	if name == "" {
+		name = SmartConfigStrategy
+	}
+	strategies.RLock()
+	defer strategies.RUnlock()
+	merger, found := strategies.mergers[name]
+	if !found {
+		return nil, fmt.Errorf("%w:%s", ErrUnknownStrategy, name)
+	}
+	return merger, nil
+}
+
+// Strategies returns the sorted names of the registered strategies
+func Strategies() []string {
+	strategies.RLock()
+	defer strategies.RUnlock()
+	names := make([]string, 0, len(strategies.mergers))
+	for name := range strategies.mergers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetExplainer returns the explainer of a registered strategy (SmartConfigStrategy if empty)
+func GetExplainer(name string) (ConfigExplainer, error) {
+	merger, err := GetStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+	explainer, isExplainer := merger.(ConfigExplainer)
+	if !isExplainer {
+		return nil, fmt.Errorf("%w:%s", ErrExplainNotSupported, name)
+	}
+	return explainer, nil
+}
+
+// layoutOf returns the default layout of a registered strategy, false if the strategy doesn't merge a layout
+func layoutOf(strategy string) (*Layout, bool) {
+	merger, err := GetStrategy(strategy)
+	if err != nil {
+		return nil, false
+	}
+	switch typedMerger := merger.(type) {
+	case SmartConfigMerger:
+		return SmartConfigLayout, true
+	case SpringMerger:
+		return SpringLayout, true
+	case LayoutMerger:
+		return typedMerger.Layout, true
+	case *LayoutMerger:
+		return typedMerger.Layout, true
+	default:
+		return nil, false
+	}
+}
+
+// StrategyOf returns the strategy declared by the application manifest (SmartConfigStrategy if none),
+// a missing application version falls back to SmartConfigStrategy (see Layers)
+func StrategyOf(repo configrepo.Repo, app *configrepo.ApplicationVersion) (string, error) {
+	manifest, err := ReadManifest(repo, app)
+	if err != nil {
+		if errors.Is(err, configrepo.ErrVersionNotFound) {
+			return SmartConfigStrategy, nil
+		}
+		return "", err
+	}
+	return manifest.strategy(), nil
+}
+
+// ManifestMerger implementation of ConfigMerger that use the strategy declared by the application manifest (see StrategyOf)
+type ManifestMerger struct{}
+
+// Merge the application configuration following the strategy of its manifest
+func (m ManifestMerger) Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error) {
+	strategy, err := StrategyOf(repo, app)
+	if err != nil {
+		return nil, err
+	}
+	merger, err := GetStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	return merger.Merge(repo, app, profiles)
+}
+
+// Explain returns the flattened keys of the configuration merged following the strategy of its manifest
+func (m ManifestMerger) Explain(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (Explanation, error) {
+	strategy, err := StrategyOf(repo, app)
+	if err != nil {
+		return nil, err
+	}
+	explainer, err := GetExplainer(strategy)
+	if err != nil {
+		return nil, err
+	}
+	return explainer.Explain(repo, app, profiles)
+}
+
+// LayoutMerger implementation of ConfigMerger that merge the sources of a Layout (see mergeFiles),
+// the layout declared by a manifest of the same strategy takes precedence (see Manifest.LayoutOf).
+// It can be registered by the third party strategies that only need a different file layout
+type LayoutMerger struct {
+	// Strategy the name of the strategy
+	Strategy string
+	// Layout the default layout of the strategy
+	Layout *Layout
+}
+
+// Merge the application configuration following the layout
+func (m LayoutMerger) Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error) {
+	return mergeFiles(repo, app, m.Strategy, m.Layout, profiles, nil)
+}
+
+// Explain returns the flattened keys of the configuration merged following the layout with the files that set them
+func (m LayoutMerger) Explain(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (Explanation, error) {
+	provenance := make(Provenance)
+	finalConfig, err := mergeFiles(repo, app, m.Strategy, m.Layout, profiles, provenance)
+	if err != nil {
+		return nil, err
+	}
+	return provenance.explain(finalConfig)
+}
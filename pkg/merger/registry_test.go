@@ -0,0 +1,62 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+type staticMerger map[interface{}]interface{}
+
+func (m staticMerger) Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error) {
+	return m, nil
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	RegisterStrategy("test-static", staticMerger{"static": true})
+	RegisterStrategy("test-layout", LayoutMerger{Strategy: "test-layout", Layout: &Layout{Common: Patterns{"{application}"}, Extensions: []string{".yml"}}})
+	assert.Subset(t, Strategies(), []string{SmartConfigStrategy, SpringStrategy, "test-layout", "test-static"})
+	assert.Panics(t, func() { RegisterStrategy("test-static", staticMerger{}) })
+	assert.Panics(t, func() { RegisterStrategy("", staticMerger{}) })
+	assert.Panics(t, func() { RegisterStrategy("test-nil", nil) })
+
+	defaultMerger, err := GetStrategy("")
+	assert.NoError(t, err)
+	assert.Equal(t, SmartConfigMerger{}, defaultMerger)
+	_, err = GetStrategy("unknown")
+	assert.True(t, errors.Is(err, ErrUnknownStrategy))
+	_, err = GetExplainer("test-static")
+	assert.True(t, errors.Is(err, ErrExplainNotSupported))
+
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte("strategy: test-static")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", ManifestFile, []byte("strategy: test-layout")))
+	assert.NoError(t, repo.SetFile("app2", "1.0.0", "app2.yml", []byte("name: app2")))
+	assert.NoError(t, repo.Init())
+	config, err := ManifestMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"static": true}, config)
+	config, err = ManifestMerger{}.Merge(repo, configrepo.NewApplicationVersion("app2", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"name": "app2"}, config)
+}
+
+func TestReadManifest_InvalidStrategyAndLayout(t *testing.T) {
+	tests := map[string]string{
+		"unknown strategy":      "strategy: unknown",
+		"no profile pattern":    "layout:\n  profile: config-dev",
+		"unsupported extension": "layout:\n  extensions: [.xml]",
+		"outside search path":   "layout:\n  searchPaths: ../other-app",
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := memconfigrepo.NewMemConfigRepo()
+			assert.NoError(t, repo.SetFile("app1", "1.0.0", ManifestFile, []byte(manifest)))
+			assert.NoError(t, repo.Init())
+			_, err := ManifestMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{"dev"})
+			assert.True(t, errors.Is(err, ErrInvalidManifest), "unexpected error:%s", err)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package merger
+
+import (
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+)
+
+// SmartConfigMerger represent a ConfigMerger for smart config strategy (see SmartConfigLayout)
+type SmartConfigMerger struct{}
+
+var smartConfigLayoutMerger = LayoutMerger{Strategy: SmartConfigStrategy, Layout: SmartConfigLayout}
+
+// Merge the application configuration following the smart config strategy
+func (s SmartConfigMerger) Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error) {
+	return smartConfigLayoutMerger.Merge(repo, app, profiles)
+}
+
+// Explain returns the flattened keys of the smart config merged configuration with the files that set them
+func (s SmartConfigMerger) Explain(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (Explanation, error) {
+	return smartConfigLayoutMerger.Explain(repo, app, profiles)
+}
@@ -0,0 +1,322 @@
+package merger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/magiconair/properties"
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceExtensions the supported source file extensions, when several variants of the same file exist
+// they are merged in this order (i.e. `application.properties` overrides `application.yml` like spring-boot does)
+var SourceExtensions = []string{".yml", ".yaml", ".json", ".toml", ".properties"}
+
+// ErrInvalidSource returned if a source file cannot be parsed, the message contains the file path and the error line
+var ErrInvalidSource = fmt.Errorf("invalid source file")
+
+// SourceVariants returns the file paths of all the supported variants of a source (file path without extension)
+func SourceVariants(source string) []string {
+	result := make([]string, len(SourceExtensions))
+	for i, ext := range SourceExtensions {
+		result[i] = source + ext
+	}
+	return result
+}
+
+// ParseSource parse a source file according to its extension (yaml is used for the unknown extensions),
+// the merge directives are not applied (the deleted keys are removed)
+func ParseSource(filePath string, content []byte) (map[interface{}]interface{}, error) {
+	config, err := parseSource(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+	return stripDirectives(config), nil
+}
+
+// parseSource parse a source file keeping the merge directives of the yaml tags (see directiveValue)
+func parseSource(filePath string, content []byte) (map[interface{}]interface{}, error) {
+	var config map[interface{}]interface{}
+	var err error
+	switch path.Ext(filePath) {
+	case ".json":
+		config, err = parseJSON(content)
+	case ".toml":
+		config, err = parseTOML(content)
+	case ".properties":
+		config, err = parseProperties(content)
+	default:
+		config, err = parseYAML(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w:%s %s", ErrInvalidSource, filePath, err)
+	}
+	return config, nil
+}
+
+// isYAMLSource returns true if the source is parsed as yaml (see parseSource)
+func isYAMLSource(filePath string) bool {
+	switch path.Ext(filePath) {
+	case ".json", ".toml", ".properties":
+		return false
+	default:
+		return true
+	}
+}
+
+func parseJSON(content []byte) (map[interface{}]interface{}, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return make(map[interface{}]interface{}), nil
+	}
+	config := make(map[string]interface{})
+	err := json.Unmarshal(content, &config)
+	if err != nil {
+		var offset int64 = -1
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+		} else if errors.As(err, &typeErr) {
+			offset = typeErr.Offset
+		}
+		if offset < 0 || offset > int64(len(content)) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("line %d: %s", bytes.Count(content[:offset], []byte("\n"))+1, err)
+	}
+	return toInterfaceMap(config), nil
+}
+
+func parseTOML(content []byte) (map[interface{}]interface{}, error) {
+	tree, err := toml.LoadBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	return toInterfaceMap(tree.ToMap()), nil
+}
+
+// parseProperties parse a .properties file, the dotted keys (i.e. `server.port`) are expanded to nested maps and the indexed keys
+// (i.e. `servers[0].name`) to lists. The keys are expanded in order so the result doesn't depend on the file order, a key that is
+// both a value and a parent of other keys (i.e. `server=on` and `server.port=8080`) is an error.
+//
+// the integers, the floats and the booleans (true or false) are converted like the yaml ones, the other values are strings
+func parseProperties(content []byte) (map[interface{}]interface{}, error) {
+	loader := &properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	props, err := loader.LoadBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	keys := props.Keys()
+	sort.Strings(keys)
+	config := make(map[interface{}]interface{})
+	for _, key := range keys {
+		keyPath, err := propertyPath(key)
+		if err != nil {
+			return nil, err
+		}
+		value, _ := props.Get(key)
+		err = setProperty(config, keyPath, propertyValue(value))
+		if err != nil {
+			return nil, err
+		}
+	}
+	result, err := finalizeProperties(config, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[interface{}]interface{}), nil
+}
+
+// propertyList the items of an indexed property (i.e. `servers[0]`) by index
+type propertyList map[int]interface{}
+
+var (
+	propertyIntRe   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	propertyFloatRe = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// propertyValue convert the integers, the floats and the booleans
+func propertyValue(value string) interface{} {
+	switch {
+	case value == "true" || value == "false":
+		return value == "true"
+	case propertyIntRe.MatchString(value):
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	case propertyFloatRe.MatchString(value):
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return value
+}
+
+// propertyPath split a property key into the map keys (string) and the list indexes (int), i.e. `servers[0].name` -> servers 0 name
+func propertyPath(key string) ([]interface{}, error) {
+	keyPath := make([]interface{}, 0)
+	for _, part := range strings.Split(key, ".") {
+		name := part
+		indexes := ""
+		if open := strings.Index(part, "["); open >= 0 {
+			name, indexes = part[:open], part[open:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("malformed property key %s", key)
+		}
+		keyPath = append(keyPath, name)
+		for indexes != "" {
+			closing := strings.Index(indexes, "]")
+			if indexes[0] != '[' || closing < 0 {
+				return nil, fmt.Errorf("malformed property key %s", key)
+			}
+			index, err := strconv.Atoi(indexes[1:closing])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("malformed property index %s", key)
+			}
+			keyPath = append(keyPath, index)
+			indexes = indexes[closing+1:]
+		}
+	}
+	return keyPath, nil
+}
+
+// setProperty set the value creating the missing maps and lists, it returns an error if the value or one of its parents collides
+// with an existing property
+func setProperty(config map[interface{}]interface{}, keyPath []interface{}, value interface{}) error {
+	var parent interface{} = config
+	for i, segment := range keyPath {
+		child, found := propertyChild(parent, segment)
+		if i == len(keyPath)-1 {
+			if found {
+				return fmt.Errorf("the property %s collides with the properties of the same prefix", propertyKey(keyPath))
+			}
+			setPropertyChild(parent, segment, value)
+			return nil
+		}
+		if !found {
+			if _, isIndex := keyPath[i+1].(int); isIndex {
+				child = make(propertyList)
+			} else {
+				child = make(map[interface{}]interface{})
+			}
+			setPropertyChild(parent, segment, child)
+		}
+		_, isMap := child.(map[interface{}]interface{})
+		_, isList := child.(propertyList)
+		_, nextIsIndex := keyPath[i+1].(int)
+		if (nextIsIndex && !isList) || (!nextIsIndex && !isMap) {
+			return fmt.Errorf("the property %s collides with %s", propertyKey(keyPath), propertyKey(keyPath[:i+1]))
+		}
+		parent = child
+	}
+	return nil
+}
+
+func propertyChild(parent interface{}, segment interface{}) (interface{}, bool) {
+	var child interface{}
+	found := false
+	switch typedParent := parent.(type) {
+	case map[interface{}]interface{}:
+		child, found = typedParent[segment]
+	case propertyList:
+		child, found = typedParent[segment.(int)]
+	}
+	return child, found
+}
+
+func setPropertyChild(parent interface{}, segment interface{}, child interface{}) {
+	switch typedParent := parent.(type) {
+	case map[interface{}]interface{}:
+		typedParent[segment] = child
+	case propertyList:
+		typedParent[segment.(int)] = child
+	}
+}
+
+// propertyKey returns the property key of a path (see propertyPath)
+func propertyKey(keyPath []interface{}) string {
+	key := strings.Builder{}
+	for _, segment := range keyPath {
+		if index, isIndex := segment.(int); isIndex {
+			key.WriteString(fmt.Sprintf("[%d]", index))
+			continue
+		}
+		if key.Len() > 0 {
+			key.WriteString(".")
+		}
+		key.WriteString(segment.(string))
+	}
+	return key.String()
+}
+
+// finalizeProperties convert the property lists to lists, it returns an error if an index is missing
+func finalizeProperties(value interface{}, key string) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		for childKey, child := range typedValue {
+			childPath := childKey.(string)
+			if key != "" {
+				childPath = key + "." + childPath
+			}
+			finalChild, err := finalizeProperties(child, childPath)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[childKey] = finalChild
+		}
+		return typedValue, nil
+	case propertyList:
+		result := make([]interface{}, len(typedValue))
+		for i := range result {
+			item, found := typedValue[i]
+			if !found {
+				return nil, fmt.Errorf("the property %s[%d] is missing", key, i)
+			}
+			finalItem, err := finalizeProperties(item, fmt.Sprintf("%s[%d]", key, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = finalItem
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// toInterfaceMap convert the nested string maps to the yaml map type
+func toInterfaceMap(src map[string]interface{}) map[interface{}]interface{} {
+	result := make(map[interface{}]interface{}, len(src))
+	for key, value := range src {
+		result[key] = toInterfaceValue(value)
+	}
+	return result
+}
+
+func toInterfaceValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		return toInterfaceMap(typedValue)
+	case []map[string]interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			result[i] = toInterfaceMap(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			result[i] = toInterfaceValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,113 @@
+package merger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo/memconfigrepo"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	expected := map[interface{}]interface{}{
+		"server": map[interface{}]interface{}{"scheme": "https", "host": "localhost"},
+	}
+	tests := []struct {
+		filePath string
+		content  string
+	}{
+		{"config.yml", "server:\n  scheme: https\n  host: localhost"},
+		{"config.yaml", "server:\n  scheme: https\n  host: localhost"},
+		{"config.json", `{"server": {"scheme": "https", "host": "localhost"}}`},
+		{"config.toml", "[server]\nscheme = \"https\"\nhost = \"localhost\""},
+		{"config.properties", "server.scheme=https\nserver.host: localhost"},
+	}
+	for _, test := range tests {
+		t.Run(test.filePath, func(t *testing.T) {
+			config, err := ParseSource(test.filePath, []byte(test.content))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, config)
+		})
+	}
+}
+
+func TestParseSource_Properties(t *testing.T) {
+	content := `
+servers[1].name=backup
+servers[0].name=main
+servers[0].port=8080
+server.ssl=true
+server.timeout=1.5
+server.version=v1.0
+matrix[0][0]=a
+matrix[0][1]=b
+empty=
+`
+	config, err := ParseSource("config.properties", []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"servers": []interface{}{
+			map[interface{}]interface{}{"name": "main", "port": 8080},
+			map[interface{}]interface{}{"name": "backup"},
+		},
+		"server": map[interface{}]interface{}{"ssl": true, "timeout": 1.5, "version": "v1.0"},
+		"matrix": []interface{}{[]interface{}{"a", "b"}},
+		"empty":  "",
+	}, config)
+}
+
+func TestParseSource_Properties_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"value and map", "server.port=8080\nserver=on", "server.port collides with server"},
+		{"map and value", "server=on\nserver.port=8080", "server.port collides with server"},
+		{"map and list", "servers[0]=main\nservers.main=on", "servers[0] collides with servers"},
+		{"missing index", "servers[1]=backup", "servers[0] is missing"},
+		{"malformed index", "servers[first]=main", "malformed property index"},
+		{"empty key part", "server..port=8080", "malformed property key"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseSource("config.properties", []byte(test.content))
+			assert.True(t, errors.Is(err, ErrInvalidSource))
+			assert.Contains(t, err.Error(), test.message)
+		})
+	}
+}
+
+func TestParseSource_Errors(t *testing.T) {
+	tests := []struct {
+		filePath string
+		content  string
+		line     string
+	}{
+		{"config.yml", "prop: value\n  wrong: : value", "line 2"},
+		{"config.json", "{\n\"prop\": \"value\",\n}", "line 3"},
+		{"config.toml", "prop = \"value\"\nwrong", "(2, "},
+		{"config.properties", "prop=value\nwrong=\\u12", "Line 2"},
+	}
+	for _, test := range tests {
+		t.Run(test.filePath, func(t *testing.T) {
+			_, err := ParseSource(test.filePath, []byte(test.content))
+			assert.True(t, errors.Is(err, ErrInvalidSource))
+			assert.Contains(t, err.Error(), test.filePath)
+			assert.Contains(t, err.Error(), test.line)
+		})
+	}
+}
+
+func TestSpringMerger_Merge_SourceVariants(t *testing.T) {
+	repo := memconfigrepo.NewMemConfigRepo()
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.yml", []byte("db: yml\nlog: yml\nport: 8080")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "application.properties", []byte("db=properties")))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1.json", []byte(`{"log": "json"}`)))
+	assert.NoError(t, repo.SetFile("app1", "1.0.0", "app1-dev.toml", []byte("port = 9090")))
+	assert.NoError(t, repo.Init())
+
+	config, err := SpringMerger{}.Merge(repo, configrepo.NewApplicationVersion("app1", "1.0.0"), []string{"dev"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{"db": "properties", "log": "json", "port": int64(9090)}, config)
+}
@@ -0,0 +1,30 @@
+package merger
+
+import (
+	"github.com/vecosy/vecosy/v2/internal/utils"
+	"github.com/vecosy/vecosy/v2/pkg/configrepo"
+)
+
+// SpringMerger implementation of ConfigMerger that use spring-cloud-config strategy (see SpringLayout)
+type SpringMerger struct{}
+
+var springLayoutMerger = LayoutMerger{Strategy: SpringStrategy, Layout: SpringLayout}
+
+// Merge an application configuration based on spring-cloud-config strategy
+func (m SpringMerger) Merge(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (map[interface{}]interface{}, error) {
+	return springLayoutMerger.Merge(repo, app, profiles)
+}
+
+// Explain returns the flattened keys of the spring-cloud-config merged configuration with the files that set them
+func (m SpringMerger) Explain(repo configrepo.Repo, app *configrepo.ApplicationVersion, profiles []string) (Explanation, error) {
+	return springLayoutMerger.Explain(repo, app, profiles)
+}
+
+// GetSpringApplicationSources returns the list of the sources (file paths without extension, see SourceVariants) that are matching with the parameters
+func GetSpringApplicationSources(appName string, profiles []string, commonFirst bool) []string {
+	appSources := SpringLayout.Sources(appName, profiles)
+	if !commonFirst {
+		utils.ReverseStrings(appSources)
+	}
+	return appSources
+}
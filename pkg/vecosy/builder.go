@@ -16,11 +16,13 @@ type ClientBuilder struct {
 	appName              string
 	appVersion           string
 	environment          string
+	environments         []string
 	jwsToken             string
 	insecure             bool
 	tls                  bool
 	certFile             string
 	serverDomainOverride string
+	disableInterpolation bool
 }
 
 // NewClientBuilder create a new ClientBuilder instance
@@ -54,6 +56,18 @@ func (b *ClientBuilder) WithTLS(certFile string) *ClientBuilder {
 	return b
 }
 
+// WithEnvironments merges additional environments in order after the builder one (i.e. `prod` then `eu-west` then `canary`)
+func (b *ClientBuilder) WithEnvironments(environments ...string) *ClientBuilder {
+	b.environments = environments
+	return b
+}
+
+// WithoutInterpolation leaves the `${...}` placeholders of the configuration untouched (resolved by the server by default)
+func (b *ClientBuilder) WithoutInterpolation() *ClientBuilder {
+	b.disableInterpolation = true
+	return b
+}
+
 // WithDomainOverride TEST ONLY: override the TLS server domain validation
 func (b *ClientBuilder) WithDomainOverride(serverDomainOverride string) *ClientBuilder {
 	b.serverDomainOverride = serverDomainOverride
@@ -63,7 +77,7 @@ func (b *ClientBuilder) WithDomainOverride(serverDomainOverride string) *ClientB
 // Build will generate a new vecosy client configuration
 func (b *ClientBuilder) Build(conf *viper.Viper) (*Client, error) {
 	var err error
-	vecosyCl := &Client{AppName: b.appName, AppVersion: b.appVersion, Environment: b.environment, jwsToken: b.jwsToken, onChangeHandlers: make([]OnChangeHandler, 0)}
+	vecosyCl := &Client{AppName: b.appName, AppVersion: b.appVersion, Environment: b.environment, Environments: b.environments, jwsToken: b.jwsToken, onChangeHandlers: make([]OnChangeHandler, 0), disableInterpolation: b.disableInterpolation}
 	vecosyCl.initViper(conf)
 	var transportOption grpc.DialOption
 	if b.tls {
@@ -18,9 +18,11 @@ type OnChangeHandler = func(oldSettings map[string]interface{})
 
 // Client represent a vecosy client
 type Client struct {
-	AppName           string
-	AppVersion        string
-	Environment       string
+	AppName     string
+	AppVersion  string
+	Environment string
+	// Environments the additional environments merged in order after the Environment one (i.e. eu-west, canary)
+	Environments      []string
 	jwsToken          string
 	conn              *grpc.ClientConn
 	watchClient       vecosyGrpc.WatchServiceClient
@@ -28,6 +30,8 @@ type Client struct {
 	viper             *viper.Viper
 	updateMutex       sync.Mutex
 	onChangeHandlers  []OnChangeHandler
+	// disableInterpolation the placeholders are not resolved by the server
+	disableInterpolation bool
 }
 
 // UpdateConfig read the configuration from the vecosy server and update viper
@@ -35,16 +39,17 @@ func (vc *Client) UpdateConfig() error {
 	vc.updateMutex.Lock()
 	defer vc.updateMutex.Unlock()
 	request := &vecosyGrpc.GetConfigRequest{
-		AppName:     vc.AppName,
-		AppVersion:  vc.AppVersion,
-		Environment: vc.Environment,
+		AppName:              vc.AppName,
+		AppVersion:           vc.AppVersion,
+		Environment:          vc.Environment,
+		Environments:         vc.Environments,
+		DisableInterpolation: vc.disableInterpolation,
 	}
 	response, err := vc.smartConfigClient.GetConfig(vc.genContext(context.Background()), request)
 	if err != nil {
 		logrus.Errorf("Error getting configuration:%s", err)
 		return err
 	}
-	logrus.Debugf("Received %s", response.ConfigContent)
 	configReader := strings.NewReader(response.ConfigContent)
 	return vc.viper.ReadConfig(configReader)
 }
@@ -57,6 +62,8 @@ func (vc *Client) WatchChanges() error {
 			AppName:    vc.AppName,
 			AppVersion: vc.AppVersion,
 		},
+		Environment:  vc.Environment,
+		Environments: vc.Environments,
 	}
 	watchStream, err := vc.watchClient.Watch(vc.genContext(context.Background()), request)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/vecosy/vecosy/v2/internal/grpcapi"
 	"github.com/vecosy/vecosy/v2/internal/security"
 	"github.com/vecosy/vecosy/v2/internal/testutil"
+	"github.com/vecosy/vecosy/v2/internal/testutil/repotest"
 	"github.com/vecosy/vecosy/v2/mocks"
 	"github.com/vecosy/vecosy/v2/pkg/configrepo"
 	"os"
@@ -23,6 +24,7 @@ func Test_Client_IT(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRepo := mocks.NewMockRepo(ctrl)
+	repotest.ExpectOnlyYmlSources(mockRepo)
 
 	appName := "app1"
 	appVersion := "1.0.0"
@@ -80,6 +80,7 @@ prop: %s`, environment, propValue1)
 			AppName:    appName,
 			AppVersion: appVersion,
 		},
+		Environment: environment,
 	}
 	watchResponse := grpcapi.NewMockWatchService_WatchClient(ctrl)
 	watchResponse.EXPECT().Recv().Return(&grpcapi.WatchResponse{Changed: true}, nil)